@@ -31,5 +31,5 @@ func (server *Server) GetAllUsers(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "no users found")
 	}
 
-	return c.JSON(users)
+	return c.JSON(newUserResponses(users))
 }
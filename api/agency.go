@@ -0,0 +1,773 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+type AgencyRequest struct {
+	Name          string `json:"name" validate:"required,max=200"`
+	LicenseNumber string `json:"license_number" validate:"required,max=50"`
+	LogoUrl       string `json:"logo_url" validate:"omitempty,url,max=500"`
+}
+
+type UpdateAgencyRequest struct {
+	Name    string `json:"name" validate:"required,max=200"`
+	LogoUrl string `json:"logo_url" validate:"omitempty,url,max=500"`
+}
+
+type AgencyMemberRequest struct {
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"omitempty,oneof=admin agent"`
+}
+
+type AgencyRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin agent"`
+}
+
+type AssetAgencyRequest struct {
+	// Agent defaults to nobody; agents may only assign listings to themselves.
+	Agent string `json:"agent"`
+}
+
+type SetAgencyVerifiedRequest struct {
+	Verified bool `json:"verified"`
+}
+
+type AgencyResponse struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	LicenseNumber string    `json:"license_number"`
+	LogoUrl       *string   `json:"logo_url"`
+	Verified      bool      `json:"verified"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AgencyInviteResponse struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MyAgencyInviteResponse struct {
+	ID             int64     `json:"id"`
+	AgencyID       int64     `json:"agency_id"`
+	AgencyName     string    `json:"agency_name"`
+	AgencyVerified bool      `json:"agency_verified"`
+	Role           string    `json:"role"`
+	InvitedBy      string    `json:"invited_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type AgencyMemberResponse struct {
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	ProfileUrl *string   `json:"profile_url"`
+	Role       string    `json:"role"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+func newAgencyResponse(agency db.Agency) AgencyResponse {
+	return AgencyResponse{
+		ID:            agency.ID,
+		Name:          agency.Name,
+		LicenseNumber: agency.LicenseNumber,
+		LogoUrl:       nullString(agency.LogoUrl),
+		Verified:      agency.Verified,
+		CreatedAt:     agency.CreatedAt,
+	}
+}
+
+func newAgencyResponses(agencies []db.Agency) []AgencyResponse {
+	rsp := make([]AgencyResponse, 0, len(agencies))
+	for _, agency := range agencies {
+		rsp = append(rsp, newAgencyResponse(agency))
+	}
+	return rsp
+}
+
+func newAgencyInviteResponse(invite db.AgencyInvite) AgencyInviteResponse {
+	return AgencyInviteResponse{
+		ID:        invite.ID,
+		Username:  invite.Username,
+		Role:      string(invite.Role),
+		InvitedBy: invite.InvitedBy,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+func newAgencyInviteResponses(invites []db.AgencyInvite) []AgencyInviteResponse {
+	rsp := make([]AgencyInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		rsp = append(rsp, newAgencyInviteResponse(invite))
+	}
+	return rsp
+}
+
+func newAgencyMemberResponses(members []db.GetAgencyMembersRow) []AgencyMemberResponse {
+	rsp := make([]AgencyMemberResponse, 0, len(members))
+	for _, member := range members {
+		rsp = append(rsp, AgencyMemberResponse{
+			Username:   member.Username,
+			Name:       member.Name,
+			ProfileUrl: nullString(member.ProfileUrl),
+			Role:       string(member.Role),
+			JoinedAt:   member.JoinedAt,
+		})
+	}
+	return rsp
+}
+
+func newAgencyAssetResponses(assets []db.GetAgencyAssetsRow) []AssetResponse {
+	rsp := make([]AssetResponse, 0, len(assets))
+	for _, asset := range assets {
+		rsp = append(rsp, newOwnerAssetResponse(db.GetAssetsByUsernameRow(asset)))
+	}
+	return rsp
+}
+
+func agencyTxError(err error) error {
+	switch err {
+	case sql.ErrNoRows:
+		return fiber.NewError(fiber.StatusNotFound, "member not found.")
+	case db.ErrLastAgencyAdmin:
+		return fiber.NewError(fiber.StatusConflict, "agency must keep at least one admin.")
+	}
+
+	return fiber.NewError(fiber.StatusInternalServerError, "update agency member failed.")
+}
+
+// getMembership returns the agency the logged-in user works for.
+func (server *Server) getMembership(c *fiber.Ctx) (db.AgencyMember, error) {
+	user := c.Locals("user").(db.User)
+
+	member, err := server.store.GetAgencyMembership(c.Context(), user.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return member, fiber.NewError(fiber.StatusNotFound, "you are not a member of any agency.")
+		}
+
+		return member, fiber.NewError(fiber.StatusInternalServerError, "cannot get agency membership.")
+	}
+
+	return member, nil
+}
+
+func (server *Server) getAgencyAdmin(c *fiber.Ctx) (db.AgencyMember, error) {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return member, err
+	}
+
+	if member.Role != db.AgencyRoleAdmin {
+		return member, fiber.NewError(fiber.StatusForbidden, "only agency admins can do this.")
+	}
+
+	return member, nil
+}
+
+func (server *Server) getAgencyParam(c *fiber.Ctx) (db.Agency, error) {
+	agencyId, err := strconv.Atoi(c.Params("agency_id"))
+	if err != nil {
+		return db.Agency{}, fiber.NewError(fiber.StatusBadRequest, "invalid agency_id.")
+	}
+
+	agency, err := server.store.GetAgency(c.Context(), int64(agencyId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return agency, fiber.NewError(fiber.StatusNotFound, "agency not found.")
+		}
+
+		return agency, fiber.NewError(fiber.StatusInternalServerError, "cannot get agency.")
+	}
+
+	return agency, nil
+}
+
+func (server *Server) CreateAgency(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	var req AgencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.LicenseNumber = strings.TrimSpace(req.LicenseNumber)
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	if _, err := server.store.GetAgencyMembership(c.Context(), user.Username); err == nil {
+		return fiber.NewError(fiber.StatusConflict, "you are already a member of an agency.")
+	} else if err != sql.ErrNoRows {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency membership.")
+	}
+
+	agency, err := server.store.CreateAgencyTx(c.Context(), db.CreateAgencyParams{
+		Name:          req.Name,
+		LicenseNumber: req.LicenseNumber,
+		LogoUrl:       sql.NullString{String: req.LogoUrl, Valid: req.LogoUrl != ""},
+		CreatedBy:     user.Username,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "license number is already registered.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "create agency failed.")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newAgencyResponse(agency))
+}
+
+func (server *Server) GetMyAgency(c *fiber.Ctx) error {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	agency, err := server.store.GetAgency(c.Context(), member.AgencyID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency.")
+	}
+
+	members, err := server.store.GetAgencyMembers(c.Context(), member.AgencyID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency members.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"agency":  newAgencyResponse(agency),
+		"role":    member.Role,
+		"members": newAgencyMemberResponses(members),
+	})
+}
+
+func (server *Server) UpdateMyAgency(c *fiber.Ctx) error {
+	member, err := server.getAgencyAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateAgencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	agency, err := server.store.UpdateAgency(c.Context(), db.UpdateAgencyParams{
+		ID:      member.AgencyID,
+		Name:    req.Name,
+		LogoUrl: sql.NullString{String: req.LogoUrl, Valid: req.LogoUrl != ""},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "update agency failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newAgencyResponse(agency))
+}
+
+// MyAgencyAssets lists every listing of the user's agency, whatever its
+// moderation status, so members can see what they are working on.
+func (server *Server) MyAgencyAssets(c *fiber.Ctx) error {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	agencyId := sql.NullInt64{Int64: member.AgencyID, Valid: true}
+
+	assets, err := server.store.GetAgencyAssets(c.Context(), db.GetAgencyAssetsParams{
+		AgencyID:   agencyId,
+		ActiveOnly: false,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get assets.")
+	}
+
+	total, err := server.store.GetAgencyAssetCount(c.Context(), db.GetAgencyAssetCountParams{
+		AgencyID:   agencyId,
+		ActiveOnly: false,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"assets": newAgencyAssetResponses(assets),
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+// InviteAgencyMember invites a user to the agency. They only join once they
+// accept, see AcceptAgencyInvite.
+func (server *Server) InviteAgencyMember(c *fiber.Ctx) error {
+	admin, err := server.getAgencyAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	var req AgencyMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	if req.Role == "" {
+		req.Role = string(db.AgencyRoleAgent)
+	}
+
+	if _, err := server.store.GetUser(c.Context(), req.Username); err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "user not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get user.")
+	}
+
+	if _, err := server.store.GetAgencyMembership(c.Context(), req.Username); err == nil {
+		return fiber.NewError(fiber.StatusConflict, "user is already a member of an agency.")
+	} else if err != sql.ErrNoRows {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency membership.")
+	}
+
+	invite, err := server.store.CreateAgencyInvite(c.Context(), db.CreateAgencyInviteParams{
+		AgencyID:  admin.AgencyID,
+		Username:  req.Username,
+		Role:      db.AgencyRole(req.Role),
+		InvitedBy: admin.Username,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "user is already invited to your agency.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "invite agency member failed.")
+	}
+
+	server.notifyUser(c.Context(), invite.Username, notify.Notification{
+		Kind:  "agency_invite",
+		Title: "Agency invitation",
+		Body:  fmt.Sprintf("%s invited you to join their agency.", admin.Username),
+		Data:  map[string]any{"invite_id": invite.ID, "agency_id": invite.AgencyID},
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(newAgencyInviteResponse(invite))
+}
+
+// ListAgencyInvites lists the invites of the admin's agency nobody answered yet.
+func (server *Server) ListAgencyInvites(c *fiber.Ctx) error {
+	admin, err := server.getAgencyAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	invites, err := server.store.ListAgencyInvites(c.Context(), admin.AgencyID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency invites.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newAgencyInviteResponses(invites))
+}
+
+func (server *Server) CancelAgencyInvite(c *fiber.Ctx) error {
+	admin, err := server.getAgencyAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	inviteId, err := strconv.Atoi(c.Params("invite_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid invite_id.")
+	}
+
+	rows, err := server.store.CancelAgencyInvite(c.Context(), db.CancelAgencyInviteParams{
+		ID:       int64(inviteId),
+		AgencyID: admin.AgencyID,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cancel agency invite failed.")
+	}
+
+	if rows == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "invite not found.")
+	}
+
+	return okResponse(c, "cancel agency invite successfully.")
+}
+
+// MyAgencyInvites lists the agencies that invited the logged-in user.
+func (server *Server) MyAgencyInvites(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	invites, err := server.store.ListAgencyInvitesByUser(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency invites.")
+	}
+
+	rsp := make([]MyAgencyInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		rsp = append(rsp, MyAgencyInviteResponse{
+			ID:             invite.ID,
+			AgencyID:       invite.AgencyID,
+			AgencyName:     invite.AgencyName,
+			AgencyVerified: invite.AgencyVerified,
+			Role:           string(invite.Role),
+			InvitedBy:      invite.InvitedBy,
+			CreatedAt:      invite.CreatedAt,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rsp)
+}
+
+func (server *Server) AcceptAgencyInvite(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	inviteId, err := strconv.Atoi(c.Params("invite_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid invite_id.")
+	}
+
+	member, err := server.store.AcceptAgencyInviteTx(c.Context(), db.AcceptAgencyInviteTxParams{
+		ID:       int64(inviteId),
+		Username: user.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "invite not found.")
+		}
+
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "you are already a member of an agency.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "accept agency invite failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"agency_id": member.AgencyID,
+		"role":      member.Role,
+		"joined_at": member.JoinedAt,
+	})
+}
+
+func (server *Server) DeclineAgencyInvite(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	inviteId, err := strconv.Atoi(c.Params("invite_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid invite_id.")
+	}
+
+	rows, err := server.store.DeclineAgencyInvite(c.Context(), db.DeclineAgencyInviteParams{
+		ID:       int64(inviteId),
+		Username: user.Username,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "decline agency invite failed.")
+	}
+
+	if rows == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "invite not found.")
+	}
+
+	return okResponse(c, "decline agency invite successfully.")
+}
+
+func (server *Server) UpdateAgencyMemberRole(c *fiber.Ctx) error {
+	admin, err := server.getAgencyAdmin(c)
+	if err != nil {
+		return err
+	}
+
+	var req AgencyRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	err = server.store.UpdateAgencyMemberRoleTx(c.Context(), db.UpdateAgencyMemberRoleParams{
+		AgencyID: admin.AgencyID,
+		Username: c.Params("username"),
+		Role:     db.AgencyRole(req.Role),
+	})
+	if err != nil {
+		return agencyTxError(err)
+	}
+
+	return okResponse(c, "update agency member successfully.")
+}
+
+// RemoveAgencyMember lets admins remove anyone and members leave on their own.
+func (server *Server) RemoveAgencyMember(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+	username := c.Params("username")
+
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	if username != user.Username && member.Role != db.AgencyRoleAdmin {
+		return fiber.NewError(fiber.StatusForbidden, "only agency admins can do this.")
+	}
+
+	err = server.store.RemoveAgencyMemberTx(c.Context(), db.RemoveAgencyMemberParams{
+		AgencyID: member.AgencyID,
+		Username: username,
+	})
+	if err != nil {
+		return agencyTxError(err)
+	}
+
+	return okResponse(c, "remove agency member successfully.")
+}
+
+// AssignAssetAgency moves a listing into the user's agency and optionally
+// assigns it to one of the agency's agents.
+func (server *Server) AssignAssetAgency(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	if asset.AgencyID.Valid && asset.AgencyID.Int64 != member.AgencyID {
+		return fiber.NewError(fiber.StatusConflict, "asset already belongs to another agency.")
+	}
+
+	// agents reach this through AssetEditorMiddleware for listings of their
+	// agency, but only the owner and agency admins assign them
+	if asset.Owner != user.Username && member.Role != db.AgencyRoleAdmin {
+		return fiber.NewError(fiber.StatusForbidden, "only the owner or an agency admin can do this.")
+	}
+
+	var req AssetAgencyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	req.Agent = strings.TrimSpace(req.Agent)
+	if req.Agent != "" {
+		if member.Role != db.AgencyRoleAdmin && req.Agent != user.Username {
+			return fiber.NewError(fiber.StatusForbidden, "only agency admins can assign other agents.")
+		}
+
+		agent, err := server.store.GetAgencyMembership(c.Context(), req.Agent)
+		if err != nil && err != sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency membership.")
+		}
+
+		if err == sql.ErrNoRows || agent.AgencyID != member.AgencyID {
+			return fiber.NewError(fiber.StatusBadRequest, "agent is not a member of your agency.")
+		}
+	}
+
+	err = server.store.SetAssetAgency(c.Context(), db.SetAssetAgencyParams{
+		ID:       asset.ID,
+		AgencyID: sql.NullInt64{Int64: member.AgencyID, Valid: true},
+		Agent:    sql.NullString{String: req.Agent, Valid: req.Agent != ""},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "assign asset failed.")
+	}
+
+	return okResponse(c, "assign asset successfully.")
+}
+
+// RemoveAssetAgency takes a listing back from its agency. Only the owner can,
+// the route is behind AssetMiddleware.
+func (server *Server) RemoveAssetAgency(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	if !asset.AgencyID.Valid {
+		return fiber.NewError(fiber.StatusNotFound, "asset does not belong to an agency.")
+	}
+
+	err := server.store.SetAssetAgency(c.Context(), db.SetAssetAgencyParams{ID: asset.ID})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "remove asset from agency failed.")
+	}
+
+	return okResponse(c, "remove asset from agency successfully.")
+}
+
+// GetAgencyPage is the public profile of an agency with its agents and active
+// listings.
+func (server *Server) GetAgencyPage(c *fiber.Ctx) error {
+	agency, err := server.getAgencyParam(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	agencyId := sql.NullInt64{Int64: agency.ID, Valid: true}
+
+	members, err := server.store.GetAgencyMembers(c.Context(), agency.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency members.")
+	}
+
+	assets, err := server.store.GetAgencyAssets(c.Context(), db.GetAgencyAssetsParams{
+		AgencyID:   agencyId,
+		ActiveOnly: true,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get assets.")
+	}
+
+	total, err := server.store.GetAgencyAssetCount(c.Context(), db.GetAgencyAssetCountParams{
+		AgencyID:   agencyId,
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
+	}
+
+	rsp := newAgencyAssetResponses(assets)
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.hideContacts(c, rsp); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"agency": newAgencyResponse(agency),
+		"agents": newAgencyMemberResponses(members),
+		"assets": rsp,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+func (server *Server) ListAgencies(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	var verified sql.NullBool
+	if value := c.Query("verified"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid verified.")
+		}
+		verified = sql.NullBool{Bool: parsed, Valid: true}
+	}
+
+	agencies, err := server.store.ListAgencies(c.Context(), db.ListAgenciesParams{
+		Verified:   verified,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agencies.")
+	}
+
+	total, err := server.store.CountAgencies(c.Context(), verified)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count agencies.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"agencies": newAgencyResponses(agencies),
+		"page":     page,
+		"limit":    limit,
+		"total":    total,
+	})
+}
+
+func (server *Server) SetAgencyVerified(c *fiber.Ctx) error {
+	agency, err := server.getAgencyParam(c)
+	if err != nil {
+		return err
+	}
+
+	var req SetAgencyVerifiedRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	agency, err = server.store.SetAgencyVerified(c.Context(), db.SetAgencyVerifiedParams{
+		ID:       agency.ID,
+		Verified: req.Verified,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot update agency.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newAgencyResponse(agency))
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestInviteAgencyMemberDoesNotAddThem(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	admin := testUser(db.UserRoleUser)
+	invitee := testUser(db.UserRoleUser)
+	invitee.Username = "malee"
+
+	cookie := login(t, server, fake, admin)
+	fake.Handle("GetUser", func(args []driver.Value) (dbtest.Result, error) {
+		for _, user := range []db.User{admin, invitee} {
+			if args[0] == user.Username {
+				return dbtest.Row(userRow(user)...), nil
+			}
+		}
+		return dbtest.Result{}, nil
+	})
+	fake.Handle("GetAgencyMembership", func(args []driver.Value) (dbtest.Result, error) {
+		if args[0] != admin.Username {
+			return dbtest.Result{}, nil
+		}
+		return dbtest.Row(int64(5), admin.Username, "admin", time.Now()), nil
+	})
+	fake.Handle("CreateAgencyInvite", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(int64(1), args[0], args[1], args[2], args[3], time.Now()), nil
+	})
+	fake.Handle("CreateNotification", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/my-agency/members", bytes.NewBufferString(`{"username":"malee"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+
+	var rsp AgencyInviteResponse
+	do(t, server, req, http.StatusCreated, &rsp)
+
+	if rsp.Username != invitee.Username || rsp.Role != string(db.AgencyRoleAgent) {
+		t.Errorf("got %+v, want an agent invite for %s", rsp, invitee.Username)
+	}
+	if calls := fake.Calls(); slices.Contains(calls, "AddAgencyMember") {
+		t.Errorf("the user was added without accepting: %v", calls)
+	}
+}
+
+func TestAcceptAgencyInviteOfSomeoneElse(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	user := testUser(db.UserRoleUser)
+
+	fake.Handle("GetAgencyInviteForUpdate", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(args[0], int64(5), "malee", "agent", "somsak", time.Now()), nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/agency-invites/1/accept", nil)
+	req.AddCookie(login(t, server, fake, user))
+	do(t, server, req, http.StatusNotFound, nil)
+
+	if calls := fake.Calls(); !slices.Contains(calls, "ROLLBACK") || slices.Contains(calls, "AddAgencyMember") {
+		t.Errorf("got calls %v, want the accept rolled back before adding a member", calls)
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const maxAnalyticsDays = 365
+
+type AnalyticsDayResponse struct {
+	Day            string `json:"day"`
+	Views          int64  `json:"views"`
+	UniqueVisitors int64  `json:"unique_visitors"`
+	Favorites      int64  `json:"favorites"`
+	Inquiries      int64  `json:"inquiries"`
+}
+
+type AnalyticsTotalsResponse struct {
+	Views int64 `json:"views"`
+	// sum of the daily unique visitors, a visitor coming back on another day counts again
+	UniqueVisitors int64 `json:"unique_visitors"`
+	Favorites      int64 `json:"favorites"`
+	Inquiries      int64 `json:"inquiries"`
+
+	// percentage of unique visitors who favorited or inquired, nil without visitors
+	FavoriteRate *float64 `json:"favorite_rate"`
+	InquiryRate  *float64 `json:"inquiry_rate"`
+}
+
+// trackView counts a view of an approved listing, leaving out its owner.
+// Anonymous visitors are told apart by a hash of their IP and user agent.
+func (server *Server) trackView(c *fiber.Ctx, asset db.GetAssetByIdRow) {
+	if server.views == nil {
+		return
+	}
+
+	var visitor string
+	if user, ok := currentUser(c); ok {
+		if user.Username == asset.Owner {
+			return
+		}
+		visitor = "user:" + user.Username
+	} else {
+		sum := sha256.Sum256([]byte(c.IP() + "|" + c.Get(fiber.HeaderUserAgent)))
+		visitor = "anon:" + hex.EncodeToString(sum[:16])
+	}
+
+	server.views.Track(asset.ID, visitor)
+}
+
+func conversionRate(count, visitors int64) *float64 {
+	if visitors == 0 {
+		return nil
+	}
+
+	rate := math.Round(float64(count)*1000/float64(visitors)) / 10
+	return &rate
+}
+
+func (server *Server) GetAssetAnalytics(c *fiber.Ctx) error {
+	assetId := c.Locals("asset_id").(int)
+
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days < 1 || days > maxAnalyticsDays {
+		return fiber.NewError(fiber.StatusBadRequest, "days must be between 1 and 365.")
+	}
+
+	// days are UTC, as the view tracker records them
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, 1-days)
+
+	rows, err := server.store.GetAssetAnalytics(c.Context(), db.GetAssetAnalyticsParams{
+		AssetID: int64(assetId),
+		FromDay: from,
+		ToDay:   to,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get analytics.")
+	}
+
+	var totals AnalyticsTotalsResponse
+	daily := make([]AnalyticsDayResponse, 0, len(rows))
+	for _, row := range rows {
+		daily = append(daily, AnalyticsDayResponse{
+			Day:            row.Day.Format(dateLayout),
+			Views:          row.Views,
+			UniqueVisitors: row.UniqueVisitors,
+			Favorites:      row.Favorites,
+			Inquiries:      row.Inquiries,
+		})
+
+		totals.Views += row.Views
+		totals.UniqueVisitors += row.UniqueVisitors
+		totals.Favorites += row.Favorites
+		totals.Inquiries += row.Inquiries
+	}
+
+	totals.FavoriteRate = conversionRate(totals.Favorites, totals.UniqueVisitors)
+	totals.InquiryRate = conversionRate(totals.Inquiries, totals.UniqueVisitors)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"from":   from.Format(dateLayout),
+		"to":     to.Format(dateLayout),
+		"totals": totals,
+		"daily":  daily,
+	})
+}
@@ -9,12 +9,114 @@ import (
 	"time"
 
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
 	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
 )
 
+// parseAssetFilter reads the public listing filters from the query string.
+func parseAssetFilter(c *fiber.Ctx) (db.AssetFilter, error) {
+	filter := db.AssetFilter{
+		PropertyType: c.Query("property_type"),
+		Province:     strings.TrimSpace(c.Query("province")),
+	}
+
+	minPrice, err := parsePriceQuery(c, "min_price")
+	if err != nil {
+		return filter, err
+	}
+
+	maxPrice, err := parsePriceQuery(c, "max_price")
+	if err != nil {
+		return filter, err
+	}
+
+	reducedWithin, err := parseReducedWithin(c)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.MinPrice = minPrice
+	filter.MaxPrice = maxPrice
+	filter.ReducedWithin = reducedWithin
+	return filter, validateAssetFilter(filter)
+}
+
+// validateAssetFilter checks filters coming from the query string or a saved search.
+func validateAssetFilter(filter db.AssetFilter) error {
+	switch db.PropertyType(filter.PropertyType) {
+	case "", db.PropertyTypeHouse, db.PropertyTypeCondo, db.PropertyTypeTownhouse,
+		db.PropertyTypeLand, db.PropertyTypeCommercial, db.PropertyTypeOther:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "invalid property_type.")
+	}
+
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0) {
+		return fiber.NewError(fiber.StatusBadRequest, "price must not be negative.")
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return fiber.NewError(fiber.StatusBadRequest, "min_price must not be greater than max_price.")
+	}
+
+	if filter.ReducedWithin != nil && (*filter.ReducedWithin <= 0 || *filter.ReducedWithin > 365) {
+		return fiber.NewError(fiber.StatusBadRequest, "reduced_within must be between 1 and 365 days.")
+	}
+
+	return nil
+}
+
+func parsePriceQuery(c *fiber.Ctx, name string) (*int64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || price < 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+name+".")
+	}
+
+	return &price, nil
+}
+
+// parseReducedWithin reads ?reduced_within=N, listings whose price dropped in
+// the last N days. The range is checked by validateAssetFilter.
+func parseReducedWithin(c *fiber.Ctx) (*int, error) {
+	value := c.Query("reduced_within")
+	if value == "" {
+		return nil, nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "reduced_within must be between 1 and 365 days.")
+	}
+
+	return &days, nil
+}
+
+// assetDetail responds with a single asset and its recent price changes.
+func (server *Server) assetDetail(c *fiber.Ctx, asset AssetResponse) error {
+	history, err := server.store.GetAssetPriceHistory(c.Context(), db.GetAssetPriceHistoryParams{
+		AssetID: asset.ID,
+		Limit:   50,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get price history.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"asset":         asset,
+		"price_history": newPriceHistoryResponses(history),
+	})
+}
+
 func (server *Server) GetAllAssets(c *fiber.Ctx) error {
+	filter, err := parseAssetFilter(c)
+	if err != nil {
+		return err
+	}
+
 	page, err := strconv.Atoi(c.Query("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
@@ -23,9 +125,25 @@ func (server *Server) GetAllAssets(c *fiber.Ctx) error {
 	limit := 10
 	offset := (page - 1) * limit
 
+	// the same window for the page and the count
+	reducedSince := filter.NullReducedSince()
+
+	sort := c.Query("sort", "newest")
+	switch sort {
+	case "newest", "recently_reduced":
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "sort must be newest or recently_reduced.")
+	}
+
 	arg := db.GetAllAssetsParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		PropertyType: filter.NullPropertyType(),
+		Province:     filter.NullProvince(),
+		MinPrice:     filter.NullMinPrice(),
+		MaxPrice:     filter.NullMaxPrice(),
+		ReducedSince: reducedSince,
+		Sort:         sort,
+		PageLimit:    int32(limit),
+		PageOffset:   int32(offset),
 	}
 
 	assets, err := server.store.GetAllAssets(c.Context(), arg)
@@ -38,13 +156,40 @@ func (server *Server) GetAllAssets(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "no asset found.")
 	}
 
-	total, err := server.store.GetAssetCount(c.Context())
+	total, err := server.store.GetAssetCount(c.Context(), db.GetAssetCountParams{
+		PropertyType: filter.NullPropertyType(),
+		Province:     filter.NullProvince(),
+		MinPrice:     filter.NullMinPrice(),
+		MaxPrice:     filter.NullMaxPrice(),
+		ReducedSince: reducedSince,
+	})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot count asset")
 	}
 
+	rsp := newAssetResponses(assets)
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.hideContacts(c, rsp); err != nil {
+		return err
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"assets": assets,
+		"assets": rsp,
 		"page":   page,
 		"limit":  limit,
 		"total":  total,
@@ -58,7 +203,12 @@ func (server *Server) GetAssetById(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	return server.showAsset(c, int64(assetId))
+}
+
+// showAsset responds with a public listing as a buyer sees it.
+func (server *Server) showAsset(c *fiber.Ctx, assetId int64) error {
+	asset, err := server.store.GetAssetById(c.Context(), assetId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
@@ -67,7 +217,35 @@ func (server *Server) GetAssetById(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot not get asset.")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"asset": asset})
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	server.trackView(c, asset)
+
+	rsp := []AssetResponse{newAssetResponse(asset)}
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.hideContacts(c, rsp); err != nil {
+		return err
+	}
+
+	contentLanguage(c, rsp[0])
+	return server.assetDetail(c, rsp[0])
 }
 
 func (server *Server) GetAssetsByUsername(c *fiber.Ctx) error {
@@ -76,6 +254,15 @@ func (server *Server) GetAssetsByUsername(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "username is not provided.")
 	}
 
+	user, err := server.store.GetUser(c.Context(), username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "user not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get user.")
+	}
+
 	page, err := strconv.Atoi(c.Query("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
@@ -84,13 +271,13 @@ func (server *Server) GetAssetsByUsername(c *fiber.Ctx) error {
 	limit := 10
 	offset := (page - 1) * limit
 
-	arg := db.GetAssetsByUsernameParams{
+	arg := db.GetApprovedAssetsByUsernameParams{
 		Limit:  int32(limit),
 		Offset: int32(offset),
 		Owner:  username,
 	}
 
-	assets, err := server.store.GetAssetsByUsername(c.Context(), arg)
+	assets, err := server.store.GetApprovedAssetsByUsername(c.Context(), arg)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot get assets.")
 	}
@@ -99,13 +286,35 @@ func (server *Server) GetAssetsByUsername(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusNotFound, "no asset found.")
 	}
 
-	total, err := server.store.GetAssetCountByUsername(c.Context(), username)
+	total, err := server.store.GetApprovedAssetCountByUsername(c.Context(), username)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
 	}
 
+	rsp := newApprovedOwnerAssetResponses(assets)
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.hideContacts(c, rsp); err != nil {
+		return err
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"assets": assets,
+		"user":   newPublicUserResponse(user),
+		"assets": rsp,
 		"page":   page,
 		"limit":  limit,
 		"total":  total,
@@ -143,8 +352,25 @@ func (server *Server) AllMyAssets(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
 	}
 
+	rsp := newOwnerAssetResponses(assets)
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"assets": assets,
+		"assets": rsp,
 		"page":   page,
 		"limit":  limit,
 		"total":  total,
@@ -167,13 +393,37 @@ func (server *Server) EditAsset(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot not get asset.")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"asset": asset})
+	rsp := []AssetResponse{newAssetResponse(asset)}
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	return server.assetDetail(c, rsp[0])
 }
 
+// AssetRequest is a new listing. Price is in minor units of Currency, satang
+// for THB.
 type AssetRequest struct {
-	Owner  string `json:"owner"`
-	Price  int    `json:"price" validate:"required,min=0"`
-	Detail string `json:"detail" validate:"required"`
+	Owner        string `json:"owner"`
+	Price        int64  `json:"price" validate:"required,min=0"`
+	Title        string `json:"title" validate:"max=200"`
+	Detail       string `json:"detail" validate:"required"`
+	PropertyType string `json:"property_type" validate:"omitempty,oneof=house condo townhouse land commercial other"`
+	Province     string `json:"province" validate:"max=100"`
+	Locale       string `json:"locale" validate:"omitempty,oneof=th en"`
+	Currency     string `json:"currency" validate:"omitempty,len=3"`
 }
 
 type AssetContactRequest struct {
@@ -196,28 +446,54 @@ func (server *Server) CreateAsset(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid JSON data.")
 	}
 
-	validate := validator.New()
 	if err := validate.Struct(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
-	assetArg := db.InsertAssetParams{
-		Owner:  userData.Username,
-		Price:  int64(req.Asset.Price),
-		Detail: req.Asset.Detail,
+	assetCurrency, err := server.checkListingCurrency(c, req.Asset.Currency)
+	if err != nil {
+		return err
 	}
-	asset, err := server.store.InsertAsset(c.Context(), assetArg)
+
+	moderationStatus, moderationReason := server.newListingStatus(userData)
+	assetArg := db.InsertAssetParams{
+		Owner:            userData.Username,
+		Price:            req.Asset.Price,
+		Detail:           req.Asset.Detail,
+		ModerationStatus: moderationStatus,
+		PropertyType:     db.PropertyTypeOther,
+		Province:         strings.TrimSpace(req.Asset.Province),
+		ExpiresAt:        time.Now().Add(server.listingDuration()),
+		PrimaryLocale:    req.Asset.Locale,
+		Currency:         assetCurrency,
+	}
+	if req.Asset.PropertyType != "" {
+		assetArg.PropertyType = db.PropertyType(req.Asset.PropertyType)
+	}
+	asset, err := server.store.InsertAssetTx(c.Context(), db.InsertAssetTxParams{
+		InsertAssetParams: assetArg,
+		Title:             strings.TrimSpace(req.Asset.Title),
+	})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot create asset")
 	}
 
+	_, err = server.store.InsertModerationLog(c.Context(), db.InsertModerationLogParams{
+		AssetID: asset.ID,
+		Status:  moderationStatus,
+		Reason:  sql.NullString{String: moderationReason, Valid: true},
+	})
+	if err != nil {
+		log.Printf("moderation log insert error: %v\n", err)
+	}
+
 	for _, contact := range req.Contacts {
 		contactArg := db.InsertAssetContactParams{
 			AssetID:       asset.ID,
 			ContactName:   contact.ContactName,
 			ContactDetail: contact.ContactDetail,
 		}
-		if _, err := server.store.InsertAssetContact(c.Context(), contactArg); err != nil {
+		if _, err := server.store.InsertAssetContactTx(c.Context(), contactArg); err != nil {
 			fmt.Printf("contact insert error: %v\n", err)
 		}
 	}
@@ -237,21 +513,27 @@ func (server *Server) CreateAsset(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 
-		server.store.InsertAssetImage(c.Context(), db.InsertAssetImageParams{
+		server.store.InsertAssetImageTx(c.Context(), db.InsertAssetImageParams{
 			AssetID:  asset.ID,
 			ImageUrl: "uploads/" + uniqueName,
 		})
 	}
 
+	if moderationStatus == db.ModerationStatusPending {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Create asset successfully, waiting for review."})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Create asset successfully."})
 }
 
+// UpdateAssetRequest changes a listing, Price is in minor units of its currency.
 type UpdateAssetRequest struct {
 	Price  *int64  `json:"price" validate:"omitempty,min=0"`
 	Detail *string `json:"detail"`
 }
 
 func (server *Server) UpdateAsset(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
 	assetId := c.Locals("asset_id").(int)
 
 	var req UpdateAssetRequest
@@ -259,30 +541,67 @@ func (server *Server) UpdateAsset(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req) ; err != nil{
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
-	arg := db.UpdateAssetParams{
-		ID:     int64(assetId),
-		Price:  *req.Price,
-		Detail: *req.Detail,
+	arg := db.UpdateAssetTxParams{
+		ID:        int64(assetId),
+		Price:     asset.Price,
+		Detail:    asset.Detail,
+		ChangedBy: c.Locals("user").(db.User).Username,
+	}
+	if req.Price != nil {
+		arg.Price = *req.Price
 	}
-	err := server.store.UpdateAsset(c.Context(), arg)
+	if req.Detail != nil {
+		arg.Detail = *req.Detail
+	}
+
+	result, err := server.store.UpdateAssetTx(c.Context(), arg)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
+	if err := server.reviewAssetEdit(c, result.PriceChanged); err != nil {
+		return err
+	}
+
 	return okResponse(c, "update asset successfully.")
 }
 
 func (server *Server) DeleteAsset(c *fiber.Ctx) error {
-	assetId := c.Locals("asset_id").(int)
-	err := server.store.DeleteAsset(c.Context(), int64(assetId))
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	images, err := server.store.GetAssetImages(c.Context(), asset.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset images.")
+	}
+
+	err = server.store.DeleteAssetTx(c.Context(), asset)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
+	for _, image := range images {
+		server.removeUploads(c, image.ImageUrl)
+	}
+
 	return okResponse(c, "delete asset successfully.")
 }
+
+// MarkAssetSold closes the listing once the owner has sold it.
+func (server *Server) MarkAssetSold(c *fiber.Ctx) error {
+	assetId := c.Locals("asset_id").(int)
+
+	rows, err := server.store.MarkAssetSoldTx(c.Context(), int64(assetId))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "mark asset as sold failed.")
+	}
+
+	if rows == 0 {
+		return fiber.NewError(fiber.StatusConflict, "asset is already sold.")
+	}
+
+	return okResponse(c, "mark asset as sold successfully.")
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
 )
@@ -21,9 +20,8 @@ func (server *Server) AddNewContact(c *fiber.Ctx) error{
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req) ; err != nil{
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
 	arg := db.InsertAssetContactParams{
@@ -31,11 +29,15 @@ func (server *Server) AddNewContact(c *fiber.Ctx) error{
 		ContactName: req.ContactName,
 		ContactDetail: req.ContactDetail,
 	}
-	_, err := server.store.InsertAssetContact(c.Context(), arg)
+	_, err := server.store.InsertAssetContactTx(c.Context(), arg)
 	if err != nil{
 		return fiber.NewError(fiber.StatusInternalServerError, "add contact failed.")
 	}
 
+	if err := server.reviewAssetEdit(c, false); err != nil{
+		return err
+	}
+
 	return okResponse(c, "add contact successfully.")
 }
 
@@ -55,23 +57,29 @@ func (server *Server) UpdateContact(c *fiber.Ctx) error{
 		return fiber.NewError(http.StatusBadRequest, err.Error())
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req) ; err != nil{
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
-	arg := db.UpdateContactParams{
-		ID: int64(contactId),
-		ContactName: *req.ContactName,
-		ContactDetail: *req.ContactDetail,
+	arg := db.UpdateContactTxParams{
+		UpdateContactParams: db.UpdateContactParams{
+			ID: int64(contactId),
+			ContactName: *req.ContactName,
+			ContactDetail: *req.ContactDetail,
+		},
+		AssetID: int64(c.Locals("asset_id").(int)),
 	}
 
-	err = server.store.UpdateContact(c.Context(), arg)
+	err = server.store.UpdateContactTx(c.Context(), arg)
 	if err != nil{
 		return fiber.NewError(fiber.StatusInternalServerError, "update contact failed.")
 	} 
 
 	
+	if err := server.reviewAssetEdit(c, false); err != nil{
+		return err
+	}
+
 	return okResponse(c, "update contact successfully.")
 }
 
@@ -82,12 +90,19 @@ func (server *Server) DeleteContact(c *fiber.Ctx) error{
 		return fiber.NewError(http.StatusBadRequest, err.Error())
 	}
 
-	err = server.store.RemoveContact(c.Context(), int64(contactId))
+	err = server.store.RemoveContactTx(c.Context(), db.RemoveContactTxParams{
+		ID: int64(contactId),
+		AssetID: int64(c.Locals("asset_id").(int)),
+	})
 	if err != nil{
 		return fiber.NewError(fiber.StatusInternalServerError, "update contact failed.")
 	} 
 
 	
+	if err := server.reviewAssetEdit(c, false); err != nil{
+		return err
+	}
+
 	return okResponse(c, "delete contact successfully.")
 }
 
@@ -3,7 +3,6 @@ package api
 import (
 	"database/sql"
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
@@ -32,13 +31,17 @@ func (server *Server) AddNewImage(c *fiber.Ctx) error{
 			ImageUrl: "uploads/" + uniqueName,
 		} 
 
-		_, err := server.store.InsertAssetImage(c.Context(), arg)
+		_, err := server.store.InsertAssetImageTx(c.Context(), arg)
 		if err != nil{
 			return fiber.NewError(fiber.StatusInternalServerError, "add image failed.")
 		}
 	}
 
 
+	if err := server.reviewAssetEdit(c, true); err != nil{
+		return err
+	}
+
 	return okResponse(c, "add image successfully.")
 }
 
@@ -57,13 +60,14 @@ func (server *Server) DeleteImage(c *fiber.Ctx) error{
 		return fiber.NewError(fiber.StatusInternalServerError, "cannot get image.")
 	}
 
-	filePath := fmt.Sprintf("./%s", imageData.ImageUrl)
-	if err = os.Remove(filePath) ; err != nil{
-		fmt.Printf("failed to delete file: %v\n", err)
+	if err = server.store.DeleteImageTx(c.Context(), imageData); err != nil{
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	if err = server.store.DeleteImage(c.Context(), int64(imageId)); err != nil{
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	server.removeUploads(c, imageData.ImageUrl)
+
+	if err := server.reviewAssetEdit(c, false); err != nil{
+		return err
 	}
 
 	return okResponse(c, "delete image successfully.")
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+type AssetTranslationResponse struct {
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Primary     bool      `json:"primary"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newAssetTranslationResponse(translation db.AssetTranslation, primaryLocale string) AssetTranslationResponse {
+	return AssetTranslationResponse{
+		Locale:      translation.Locale,
+		Title:       translation.Title,
+		Description: translation.Description,
+		Primary:     translation.Locale == primaryLocale,
+		UpdatedAt:   translation.UpdatedAt,
+	}
+}
+
+// withTranslations shows the title and description of the assets in the
+// request's locale, or in their primary language when they have no
+// translation for it.
+func (server *Server) withTranslations(c *fiber.Ctx, assets []AssetResponse) error {
+	if len(assets) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(assets))
+	for _, asset := range assets {
+		ids = append(ids, asset.ID)
+	}
+
+	localized, err := server.store.GetLocalizedAssets(c.Context(), db.GetLocalizedAssetsParams{
+		Locale:   requestLocale(c),
+		AssetIds: ids,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get translations.")
+	}
+
+	byId := make(map[int64]db.GetLocalizedAssetsRow, len(localized))
+	for _, row := range localized {
+		byId[row.AssetID] = row
+	}
+
+	for i := range assets {
+		row, ok := byId[assets[i].ID]
+		if !ok || !row.Locale.Valid {
+			continue
+		}
+
+		assets[i].Locale = row.Locale.String
+		assets[i].Title = &row.Title.String
+		assets[i].Detail = row.Description.String
+	}
+
+	return nil
+}
+
+// contentLanguage tells caches and clients which language a single listing
+// was answered in.
+func contentLanguage(c *fiber.Ctx, asset AssetResponse) {
+	if asset.Locale != "" {
+		c.Set(fiber.HeaderContentLanguage, asset.Locale)
+	}
+}
+
+func (server *Server) GetAssetTranslations(c *fiber.Ctx) error {
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	translations, err := server.store.GetAssetTranslations(c.Context(), asset.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get translations.")
+	}
+
+	rsp := make([]AssetTranslationResponse, 0, len(translations))
+	for _, translation := range translations {
+		rsp = append(rsp, newAssetTranslationResponse(translation, asset.PrimaryLocale))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"primary_locale": asset.PrimaryLocale,
+		"translations":   rsp,
+	})
+}
+
+type AssetTranslationRequest struct {
+	Title       string `json:"title" validate:"required,max=200"`
+	Description string `json:"description" validate:"required"`
+}
+
+// UpsertAssetTranslation adds or replaces the translation of a listing in one
+// locale. Translating the primary locale also changes the listing's detail.
+func (server *Server) UpsertAssetTranslation(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	locale := strings.ToLower(c.Params("locale"))
+	if !isSupportedLocale(locale) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid locale.")
+	}
+
+	var req AssetTranslationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request")
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	translation, err := server.store.UpsertAssetTranslationTx(c.Context(), db.UpsertAssetTranslationParams{
+		AssetID:     asset.ID,
+		Locale:      locale,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot save translation.")
+	}
+
+	if err := server.reviewAssetEdit(c, false); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newAssetTranslationResponse(translation, asset.PrimaryLocale))
+}
+
+func (server *Server) DeleteAssetTranslation(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	locale := strings.ToLower(c.Params("locale"))
+	if !isSupportedLocale(locale) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid locale.")
+	}
+
+	rows, err := server.store.DeleteAssetTranslationTx(c.Context(), db.DeleteAssetTranslationParams{
+		AssetID: asset.ID,
+		Locale:  locale,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot delete translation.")
+	}
+
+	if rows == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "translation not found.")
+	}
+
+	return okResponse(c, "delete translation successfully.")
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"database/sql"
+	"math"
+	"strconv"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// UserResponse is what a user sees about themselves (and what admins see about anyone).
+type UserResponse struct {
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Phone      string    `json:"phone"`
+	ProfileUrl *string   `json:"profile_url"`
+	Roles      string    `json:"roles"`
+	IsTrusted  bool      `json:"is_trusted"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PublicUserResponse is what anyone else may see about a user.
+type PublicUserResponse struct {
+	Username   string    `json:"username"`
+	Name       string    `json:"name"`
+	ProfileUrl *string   `json:"profile_url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AssetResponse is a listing as the API returns it. Prices are in minor units
+// of Currency, see the exchange rates for how many decimals it has.
+type AssetResponse struct {
+	ID               int64     `json:"id"`
+	Slug             string    `json:"slug"`
+	Owner            string    `json:"owner"`
+	Price            int64     `json:"price"`
+	Currency         string    `json:"currency"`
+	Title            *string   `json:"title"`
+	Detail           string    `json:"detail"`
+	Locale           string    `json:"locale"`
+	PrimaryLocale    string    `json:"primary_locale"`
+	Status           bool      `json:"status"`
+	UnderOffer       bool      `json:"under_offer"`
+	AgencyID         *int64    `json:"agency_id"`
+	Agent            *string   `json:"agent"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	ModerationStatus string    `json:"moderation_status"`
+	ModerationReason *string   `json:"moderation_reason"`
+	PropertyType     string    `json:"property_type"`
+	Province         string    `json:"province"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	ContactID        *int64    `json:"contact_id"`
+	ContactName      *string   `json:"contact_name"`
+	ContactDetail    *string   `json:"contact_detail"`
+	ImageID          *int64    `json:"image_id"`
+	ImageUrl         *string   `json:"image_url"`
+
+	// set when the latest price change was a reduction
+	PreviousPrice    *int64     `json:"previous_price,omitempty"`
+	PriceReducedAt   *time.Time `json:"price_reduced_at,omitempty"`
+	PriceDropPercent *float64   `json:"price_drop_percent,omitempty"`
+
+	// set when ?currency= asks for prices in another currency, see withCurrency
+	DisplayPrice *DisplayPriceResponse `json:"display_price,omitempty"`
+
+	// set while a promotion is running, see withPromotions
+	Promoted       bool     `json:"promoted"`
+	PromotionTypes []string `json:"promotion_types,omitempty"`
+
+	// only filled in for logged-in viewers
+	IsFavorited   *bool  `json:"is_favorited,omitempty"`
+	FavoriteCount *int64 `json:"favorite_count,omitempty"`
+
+	// set when contact details are withheld until the seller answers an inquiry
+	ContactHidden bool `json:"contact_hidden,omitempty"`
+}
+
+func newUserResponse(user db.User) UserResponse {
+	return UserResponse{
+		Username:   user.Username,
+		Name:       user.Name,
+		Email:      user.Email,
+		Phone:      user.Phone,
+		ProfileUrl: nullString(user.ProfileUrl),
+		Roles:      string(user.Roles),
+		IsTrusted:  user.IsTrusted,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+	}
+}
+
+func newUserResponses(users []db.User) []UserResponse {
+	rsp := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		rsp = append(rsp, newUserResponse(user))
+	}
+	return rsp
+}
+
+func newPublicUserResponse(user db.User) PublicUserResponse {
+	return PublicUserResponse{
+		Username:   user.Username,
+		Name:       user.Name,
+		ProfileUrl: nullString(user.ProfileUrl),
+		CreatedAt:  user.CreatedAt,
+	}
+}
+
+func newAssetResponse(asset db.GetAssetByIdRow) AssetResponse {
+	rsp := AssetResponse{
+		ID:               asset.ID,
+		Slug:             asset.Slug,
+		Owner:            asset.Owner,
+		Price:            asset.Price,
+		Currency:         asset.Currency,
+		Detail:           asset.Detail,
+		Locale:           asset.PrimaryLocale,
+		PrimaryLocale:    asset.PrimaryLocale,
+		Status:           asset.Status,
+		UnderOffer:       asset.UnderOffer,
+		AgencyID:         nullInt64(asset.AgencyID),
+		Agent:            nullString(asset.Agent),
+		ExpiresAt:        asset.ExpiresAt,
+		ModerationStatus: string(asset.ModerationStatus),
+		ModerationReason: nullString(asset.ModerationReason),
+		PropertyType:     string(asset.PropertyType),
+		Province:         asset.Province,
+		CreatedAt:        asset.CreatedAt,
+		UpdatedAt:        asset.UpdatedAt,
+		ContactID:        nullInt64(asset.ContactID),
+		ContactName:      nullString(asset.ContactName),
+		ContactDetail:    nullString(asset.ContactDetail),
+		ImageID:          nullInt64(asset.ImageID),
+		ImageUrl:         nullString(asset.ImageUrl),
+	}
+
+	if asset.PreviousPrice.Valid && asset.PreviousPrice.Int64 > asset.Price {
+		percent := math.Round(float64(asset.PreviousPrice.Int64-asset.Price)*1000/float64(asset.PreviousPrice.Int64)) / 10
+		rsp.PreviousPrice = &asset.PreviousPrice.Int64
+		rsp.PriceReducedAt = nullTime(asset.PriceChangedAt)
+		rsp.PriceDropPercent = &percent
+	}
+
+	return rsp
+}
+
+func newAssetResponses(assets []db.GetAllAssetsRow) []AssetResponse {
+	rsp := make([]AssetResponse, 0, len(assets))
+	for _, asset := range assets {
+		rsp = append(rsp, newAssetResponse(db.GetAssetByIdRow(asset)))
+	}
+	return rsp
+}
+
+func newOwnerAssetResponse(asset db.GetAssetsByUsernameRow) AssetResponse {
+	return AssetResponse{
+		ID:               asset.ID,
+		Slug:             asset.Slug,
+		Owner:            asset.Owner,
+		Price:            asset.Price,
+		Currency:         asset.Currency,
+		Detail:           asset.Detail,
+		Locale:           asset.PrimaryLocale,
+		PrimaryLocale:    asset.PrimaryLocale,
+		Status:           asset.Status,
+		UnderOffer:       asset.UnderOffer,
+		AgencyID:         nullInt64(asset.AgencyID),
+		Agent:            nullString(asset.Agent),
+		ExpiresAt:        asset.ExpiresAt,
+		ModerationStatus: string(asset.ModerationStatus),
+		ModerationReason: nullString(asset.ModerationReason),
+		PropertyType:     string(asset.PropertyType),
+		Province:         asset.Province,
+		CreatedAt:        asset.CreatedAt,
+		UpdatedAt:        asset.UpdatedAt,
+		ContactID:        anyInt64(asset.ContactID),
+		ContactName:      anyString(asset.ContactName),
+		ContactDetail:    anyString(asset.ContactDetail),
+		ImageID:          anyInt64(asset.ImageID),
+		ImageUrl:         anyString(asset.ImageUrl),
+	}
+}
+
+func newFavoriteAssetResponses(assets []db.GetFavoriteAssetsRow) []AssetResponse {
+	rsp := make([]AssetResponse, 0, len(assets))
+	for _, asset := range assets {
+		rsp = append(rsp, newOwnerAssetResponse(db.GetAssetsByUsernameRow(asset)))
+	}
+	return rsp
+}
+
+func newOwnerAssetResponses(assets []db.GetAssetsByUsernameRow) []AssetResponse {
+	rsp := make([]AssetResponse, 0, len(assets))
+	for _, asset := range assets {
+		rsp = append(rsp, newOwnerAssetResponse(asset))
+	}
+	return rsp
+}
+
+func newApprovedOwnerAssetResponses(assets []db.GetApprovedAssetsByUsernameRow) []AssetResponse {
+	rsp := make([]AssetResponse, 0, len(assets))
+	for _, asset := range assets {
+		rsp = append(rsp, newOwnerAssetResponse(db.GetAssetsByUsernameRow(asset)))
+	}
+	return rsp
+}
+
+type PriceHistoryResponse struct {
+	OldPrice  int64     `json:"old_price"`
+	NewPrice  int64     `json:"new_price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func newPriceHistoryResponses(history []db.AssetPriceHistory) []PriceHistoryResponse {
+	rsp := make([]PriceHistoryResponse, 0, len(history))
+	for _, change := range history {
+		rsp = append(rsp, PriceHistoryResponse{
+			OldPrice:  change.OldPrice,
+			NewPrice:  change.NewPrice,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return rsp
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullInt32(i sql.NullInt32) *int32 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int32
+}
+
+func nullInt64(i sql.NullInt64) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// anyString and anyInt64 unwrap the untyped columns sqlc gives us for
+// aggregates like MIN(), which lib/pq scans as []byte or int64.
+func anyString(v interface{}) *string {
+	switch s := v.(type) {
+	case string:
+		return &s
+	case []byte:
+		str := string(s)
+		return &str
+	}
+	return nil
+}
+
+func anyInt64(v interface{}) *int64 {
+	switch i := v.(type) {
+	case int64:
+		return &i
+	case []byte:
+		n, err := strconv.ParseInt(string(i), 10, 64)
+		if err != nil {
+			return nil
+		}
+		return &n
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+func testUser(roles db.UserRole) db.User {
+	now := time.Now().UTC().Truncate(time.Second)
+	return db.User{
+		Username:   "somchai",
+		Name:       "Somchai Jaidee",
+		Email:      "somchai@example.com",
+		Phone:      "0812345678",
+		Password:   "$2a$10$7EqJtq98hPqEX7fNZaFWoOhi5BWX4Z3ZfJzG0t9fVQ8pqIvl7o1Xa",
+		ProfileUrl: sql.NullString{String: "https://cdn.example.com/somchai.png", Valid: true},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Roles:      roles,
+	}
+}
+
+// jsonKeys encodes v the way handlers do and returns its top level keys.
+func jsonKeys(t *testing.T, v any) map[string]json.RawMessage {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("cannot encode %T: %v", v, err)
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("cannot decode %T: %v", v, err)
+	}
+	return keys
+}
+
+func requireNoKeys(t *testing.T, keys map[string]json.RawMessage, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, ok := keys[name]; ok {
+			t.Errorf("response has a %q key", name)
+		}
+	}
+}
+
+func TestNewUserResponse(t *testing.T) {
+	user := testUser(db.UserRoleUser)
+
+	rsp := newUserResponse(user)
+	if rsp.Username != user.Username || rsp.Email != user.Email || rsp.Phone != user.Phone {
+		t.Errorf("got %+v, want the fields of %+v", rsp, user)
+	}
+	if rsp.ProfileUrl == nil || *rsp.ProfileUrl != user.ProfileUrl.String {
+		t.Errorf("profile_url = %v, want %q", rsp.ProfileUrl, user.ProfileUrl.String)
+	}
+
+	keys := jsonKeys(t, rsp)
+	requireNoKeys(t, keys, "password")
+	for _, name := range []string{"username", "email", "phone", "roles"} {
+		if _, ok := keys[name]; !ok {
+			t.Errorf("response has no %q key", name)
+		}
+	}
+}
+
+func TestNewUserResponses(t *testing.T) {
+	users := []db.User{testUser(db.UserRoleUser), testUser(db.UserRoleAdmin)}
+
+	rsp := newUserResponses(users)
+	if len(rsp) != len(users) {
+		t.Fatalf("got %d responses, want %d", len(rsp), len(users))
+	}
+	for _, user := range rsp {
+		requireNoKeys(t, jsonKeys(t, user), "password")
+	}
+
+	if rsp := newUserResponses(nil); rsp == nil {
+		t.Error("no users should encode as an empty list, not null")
+	}
+}
+
+func TestNewPublicUserResponse(t *testing.T) {
+	user := testUser(db.UserRoleUser)
+
+	keys := jsonKeys(t, newPublicUserResponse(user))
+	requireNoKeys(t, keys, "password", "email", "phone", "roles", "is_trusted")
+
+	user.ProfileUrl = sql.NullString{}
+	if rsp := newPublicUserResponse(user); rsp.ProfileUrl != nil {
+		t.Errorf("profile_url = %q, want nil", *rsp.ProfileUrl)
+	}
+}
+
+func TestNewAssetResponse(t *testing.T) {
+	now := time.Now().UTC()
+	asset := db.GetAssetByIdRow{
+		ID:               1,
+		Owner:            "somchai",
+		Price:            4_500_000,
+		Detail:           "Condo near BTS",
+		Currency:         "THB",
+		ModerationStatus: db.ModerationStatusApproved,
+		PropertyType:     db.PropertyTypeCondo,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		PreviousPrice:    sql.NullInt64{Int64: 5_000_000, Valid: true},
+		PriceChangedAt:   sql.NullTime{Time: now, Valid: true},
+	}
+
+	rsp := newAssetResponse(asset)
+	if rsp.PreviousPrice == nil || *rsp.PreviousPrice != 5_000_000 {
+		t.Errorf("previous_price = %v, want 5000000", rsp.PreviousPrice)
+	}
+	if rsp.PriceDropPercent == nil || *rsp.PriceDropPercent != 10 {
+		t.Errorf("price_drop_percent = %v, want 10", rsp.PriceDropPercent)
+	}
+	requireNoKeys(t, jsonKeys(t, rsp), "password", "email", "phone")
+
+	// a price increase is not a reduction
+	asset.PreviousPrice.Int64 = 4_000_000
+	if rsp := newAssetResponse(asset); rsp.PreviousPrice != nil || rsp.PriceDropPercent != nil {
+		t.Errorf("got a price reduction for a price increase: %+v", rsp)
+	}
+}
+
+func TestNewOwnerAssetResponses(t *testing.T) {
+	assets := []db.GetAssetsByUsernameRow{{
+		ID:               2,
+		Owner:            "somchai",
+		Price:            12_000,
+		Currency:         "THB",
+		ModerationStatus: db.ModerationStatusPending,
+		PropertyType:     db.PropertyTypeHouse,
+		ContactName:      "Somchai",
+		ImageUrl:         nil,
+	}}
+
+	rsp := newOwnerAssetResponses(assets)
+	if len(rsp) != 1 {
+		t.Fatalf("got %d responses, want 1", len(rsp))
+	}
+	if rsp[0].ContactName == nil || *rsp[0].ContactName != "Somchai" {
+		t.Errorf("contact_name = %v, want Somchai", rsp[0].ContactName)
+	}
+	if rsp[0].ImageUrl != nil {
+		t.Errorf("image_url = %q, want nil", *rsp[0].ImageUrl)
+	}
+	requireNoKeys(t, jsonKeys(t, rsp[0]), "password", "email", "phone")
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/sangketkit01/real-estate-backend/currency"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+type ExchangeRateResponse struct {
+	Currency   string    `json:"currency"`
+	Rate       string    `json:"rate"`
+	MinorUnits int32     `json:"minor_units"`
+	UpdatedBy  *string   `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func newExchangeRateResponse(rate db.ExchangeRate) ExchangeRateResponse {
+	rsp := ExchangeRateResponse{
+		Currency:   rate.Currency,
+		Rate:       rate.Rate,
+		MinorUnits: int32(rate.MinorUnits),
+		UpdatedBy:  nullString(rate.UpdatedBy),
+		UpdatedAt:  rate.UpdatedAt,
+	}
+	if parsed, err := newCurrencyRate(rate); err == nil {
+		rsp.Rate = parsed.String()
+	}
+	return rsp
+}
+
+func newCurrencyRate(rate db.ExchangeRate) (currency.Rate, error) {
+	return currency.NewRate(rate.Currency, rate.Rate, int32(rate.MinorUnits))
+}
+
+// DisplayPriceResponse is a listing's price in the currency the client asked
+// for. Amounts are in minor units, e.g. cents for USD.
+type DisplayPriceResponse struct {
+	Currency       string `json:"currency"`
+	MinorUnits     int32  `json:"minor_units"`
+	Amount         int64  `json:"amount"`
+	PreviousAmount *int64 `json:"previous_amount,omitempty"`
+}
+
+// exchangeRates loads every rate, the table holds a handful of currencies.
+func (server *Server) exchangeRates(ctx context.Context) (map[string]currency.Rate, error) {
+	rows, err := server.store.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]currency.Rate, len(rows))
+	for _, row := range rows {
+		rate, err := newCurrencyRate(row)
+		if err != nil {
+			return nil, err
+		}
+		rates[rate.Currency] = rate
+	}
+	return rates, nil
+}
+
+// withCurrency adds the price of the assets in the currency of ?currency=.
+// Prices are converted from minor units of the listing's currency to minor
+// units of the asked one and rounded half away from zero.
+func (server *Server) withCurrency(c *fiber.Ctx, assets []AssetResponse) error {
+	code := currency.NormalizeCode(c.Query("currency"))
+	if code == "" || len(assets) == 0 {
+		return nil
+	}
+
+	if !currency.ValidCode(code) {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid currency.")
+	}
+
+	rates, err := server.exchangeRates(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get exchange rates.")
+	}
+
+	to, ok := rates[code]
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "unknown currency.")
+	}
+
+	for i := range assets {
+		from, ok := rates[assets[i].Currency]
+		if !ok {
+			continue
+		}
+
+		amount, ok := currency.Convert(assets[i].Price, from, to)
+		if !ok {
+			continue
+		}
+
+		display := &DisplayPriceResponse{Currency: to.Currency, MinorUnits: to.MinorUnits, Amount: amount}
+		if assets[i].PreviousPrice != nil {
+			previous, ok := currency.Convert(*assets[i].PreviousPrice, from, to)
+			if ok {
+				display.PreviousAmount = &previous
+			}
+		}
+		assets[i].DisplayPrice = display
+	}
+
+	return nil
+}
+
+// formatAssetPrice writes an amount in minor units of a listing's currency
+// for people, e.g. "1234.50 USD".
+func (server *Server) formatAssetPrice(ctx context.Context, assetId, amount int64) string {
+	rate, err := server.store.GetAssetExchangeRate(ctx, assetId)
+	if err != nil {
+		return strconv.FormatInt(amount, 10)
+	}
+	return currency.FormatAmount(amount, int32(rate.MinorUnits)) + " " + rate.Currency
+}
+
+// checkListingCurrency returns the currency a new listing is priced in.
+func (server *Server) checkListingCurrency(c *fiber.Ctx, code string) (string, error) {
+	code = currency.NormalizeCode(code)
+	if code == "" {
+		return db.DefaultCurrency, nil
+	}
+
+	if _, err := server.store.GetExchangeRate(c.Context(), code); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fiber.NewError(fiber.StatusBadRequest, "unknown currency.")
+		}
+
+		return "", fiber.NewError(fiber.StatusInternalServerError, "cannot get exchange rate.")
+	}
+
+	return code, nil
+}
+
+func (server *Server) ListExchangeRates(c *fiber.Ctx) error {
+	rates, err := server.store.ListExchangeRates(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get exchange rates.")
+	}
+
+	rsp := make([]ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		rsp = append(rsp, newExchangeRateResponse(rate))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"base":  currency.Base,
+		"rates": rsp,
+	})
+}
+
+type SetExchangeRateRequest struct {
+	// baht per one unit of the currency, a decimal string like "35.12"
+	Rate       string `json:"rate" validate:"required"`
+	MinorUnits *int32 `json:"minor_units" validate:"omitempty,min=0,max=4"`
+}
+
+func upsertExchangeRateParams(rate currency.Rate, updatedBy string) db.UpsertExchangeRateParams {
+	return db.UpsertExchangeRateParams{
+		Currency:   rate.Currency,
+		Rate:       rate.String(),
+		MinorUnits: int16(rate.MinorUnits),
+		UpdatedBy:  sql.NullString{String: updatedBy, Valid: true},
+	}
+}
+
+// SetExchangeRate adds a currency or changes its rate.
+func (server *Server) SetExchangeRate(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	var req SetExchangeRateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request")
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	// a rate update may leave out the minor units the currency already has
+	minorUnits := int32(2)
+	if req.MinorUnits != nil {
+		minorUnits = *req.MinorUnits
+	} else if existing, err := server.store.GetExchangeRate(c.Context(), currency.NormalizeCode(c.Params("currency"))); err == nil {
+		minorUnits = int32(existing.MinorUnits)
+	}
+
+	rate, err := currency.NewRate(c.Params("currency"), req.Rate, minorUnits)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	saved, err := server.store.UpsertExchangeRate(c.Context(), upsertExchangeRateParams(rate, user.Username))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusConflict, "minor_units of a currency cannot change.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot save exchange rate.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newExchangeRateResponse(saved))
+}
+
+func (server *Server) DeleteExchangeRate(c *fiber.Ctx) error {
+	code := currency.NormalizeCode(c.Params("currency"))
+	if code == currency.Base {
+		return fiber.NewError(fiber.StatusBadRequest, "the base currency cannot be removed.")
+	}
+
+	rows, err := server.store.DeleteExchangeRate(c.Context(), code)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return fiber.NewError(fiber.StatusConflict, "currency is used by listings.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot delete exchange rate.")
+	}
+
+	if rows == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "exchange rate not found.")
+	}
+
+	return okResponse(c, "delete exchange rate successfully.")
+}
+
+// ImportExchangeRates loads a rates file, see currency.ReadRates for the
+// format. Currencies missing from the file keep their rate.
+func (server *Server) ImportExchangeRates(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "file is required.")
+	}
+
+	data, err := readFormFile(file)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "cannot read file.")
+	}
+
+	rates, err := currency.ReadRates(bytes.NewReader(data))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	args := make([]db.UpsertExchangeRateParams, 0, len(rates))
+	for _, rate := range rates {
+		args = append(args, upsertExchangeRateParams(rate, user.Username))
+	}
+
+	saved, err := server.store.ImportExchangeRatesTx(c.Context(), args)
+	if err != nil {
+		if err == db.ErrMinorUnitsChanged {
+			return fiber.NewError(fiber.StatusConflict, "minor_units of a currency cannot change.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot save exchange rates.")
+	}
+
+	rsp := make([]ExchangeRateResponse, 0, len(saved))
+	for _, rate := range saved {
+		rsp = append(rsp, newExchangeRateResponse(rate))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"rates": rsp,
+	})
+}
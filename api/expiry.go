@@ -0,0 +1,45 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const defaultListingDuration = 90 * 24 * time.Hour
+
+func (server *Server) listingDuration() time.Duration {
+	if server.config.ListingDuration <= 0 {
+		return defaultListingDuration
+	}
+	return server.config.ListingDuration
+}
+
+// isPublicAsset reports whether buyers may see a listing: approved and not expired.
+func isPublicAsset(asset db.GetAssetByIdRow) bool {
+	return asset.ModerationStatus == db.ModerationStatusApproved && asset.ExpiresAt.After(time.Now())
+}
+
+// RenewAsset puts a listing up for another full listing duration, bringing it
+// back if it was archived.
+func (server *Server) RenewAsset(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	if asset.Status {
+		return fiber.NewError(fiber.StatusBadRequest, "sold assets cannot be renewed.")
+	}
+
+	expiresAt, err := server.store.RenewAsset(c.Context(), db.RenewAssetParams{
+		ID:        asset.ID,
+		ExpiresAt: time.Now().Add(server.listingDuration()),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "renew asset failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":    "renew asset successfully.",
+		"expires_at": expiresAt,
+	})
+}
@@ -0,0 +1,378 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// assets are read in pages of this size so an export never holds one huge
+// result set open
+const exportBatchSize = 500
+
+type ExportContact struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// ExportAsset is one listing in an export, with all of its contacts and
+// images instead of the first one the listing queries return. Price is in
+// minor units of Currency, like in AssetResponse.
+type ExportAsset struct {
+	ID               int64           `json:"id"`
+	Owner            string          `json:"owner"`
+	AgencyID         *int64          `json:"agency_id"`
+	Agent            *string         `json:"agent"`
+	Price            int64           `json:"price"`
+	Currency         string          `json:"currency"`
+	Detail           string          `json:"detail"`
+	PropertyType     string          `json:"property_type"`
+	Province         string          `json:"province"`
+	Sold             bool            `json:"sold"`
+	UnderOffer       bool            `json:"under_offer"`
+	ModerationStatus string          `json:"moderation_status"`
+	ExpiresAt        time.Time       `json:"expires_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	Contacts         []ExportContact `json:"contacts"`
+	Images           []string        `json:"images"`
+}
+
+// uploadURL turns a stored image path like "uploads/x.jpg" into the address
+// it is served from. Images that already are URLs are kept.
+func (server *Server) uploadURL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	base := strings.TrimSuffix(server.config.PublicBaseURL, "/")
+	return base + "/static/" + strings.TrimPrefix(path, "uploads/")
+}
+
+// assetExtras loads every contact and image of a page of listings, keyed by
+// listing, in two queries.
+func (server *Server) assetExtras(ctx context.Context, assetIds []int64) (map[int64][]db.AssetContact, map[int64][]db.AssetImage, error) {
+	if len(assetIds) == 0 {
+		return nil, nil, nil
+	}
+
+	contacts, err := server.store.GetAssetContactsByAssetIDs(ctx, assetIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	images, err := server.store.GetAssetImagesByAssetIDs(ctx, assetIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contactsByAsset := make(map[int64][]db.AssetContact, len(assetIds))
+	for _, contact := range contacts {
+		contactsByAsset[contact.AssetID] = append(contactsByAsset[contact.AssetID], contact)
+	}
+	imagesByAsset := make(map[int64][]db.AssetImage, len(assetIds))
+	for _, image := range images {
+		imagesByAsset[image.AssetID] = append(imagesByAsset[image.AssetID], image)
+	}
+
+	return contactsByAsset, imagesByAsset, nil
+}
+
+func (server *Server) newExportAsset(asset db.GetAssetsByUsernameRow, contacts []db.AssetContact, images []db.AssetImage) ExportAsset {
+	export := ExportAsset{
+		ID:               asset.ID,
+		Owner:            asset.Owner,
+		AgencyID:         nullInt64(asset.AgencyID),
+		Agent:            nullString(asset.Agent),
+		Price:            asset.Price,
+		Currency:         asset.Currency,
+		Detail:           asset.Detail,
+		PropertyType:     string(asset.PropertyType),
+		Province:         asset.Province,
+		Sold:             asset.Status,
+		UnderOffer:       asset.UnderOffer,
+		ModerationStatus: string(asset.ModerationStatus),
+		ExpiresAt:        asset.ExpiresAt,
+		CreatedAt:        asset.CreatedAt,
+		UpdatedAt:        asset.UpdatedAt,
+		Contacts:         make([]ExportContact, 0, len(contacts)),
+		Images:           make([]string, 0, len(images)),
+	}
+	for _, contact := range contacts {
+		export.Contacts = append(export.Contacts, ExportContact{Name: contact.ContactName, Detail: contact.ContactDetail})
+	}
+	for _, image := range images {
+		export.Images = append(export.Images, server.uploadURL(image.ImageUrl))
+	}
+
+	return export
+}
+
+// exportPage reads one page of the listings to export.
+type exportPage func(ctx context.Context, limit, offset int32) ([]db.GetAssetsByUsernameRow, error)
+
+// writeExport pages through one of the listing queries until it runs dry and
+// hands every listing to write. first is the page at offset 0.
+func (server *Server) writeExport(ctx context.Context, first []db.GetAssetsByUsernameRow, page exportPage, write func(ExportAsset) error) error {
+	assets := first
+	for offset := int32(0); ; {
+		assetIds := make([]int64, 0, len(assets))
+		for _, asset := range assets {
+			assetIds = append(assetIds, asset.ID)
+		}
+
+		contacts, images, err := server.assetExtras(ctx, assetIds)
+		if err != nil {
+			return err
+		}
+
+		for _, asset := range assets {
+			if err := write(server.newExportAsset(asset, contacts[asset.ID], images[asset.ID])); err != nil {
+				return err
+			}
+		}
+
+		if len(assets) < exportBatchSize {
+			return nil
+		}
+
+		offset += exportBatchSize
+		if assets, err = page(ctx, exportBatchSize, offset); err != nil {
+			return err
+		}
+	}
+}
+
+var exportColumns = []string{
+	"id", "owner", "agency_id", "agent", "price", "currency", "detail", "property_type", "province",
+	"sold", "under_offer", "moderation_status", "expires_at", "created_at", "updated_at",
+	"contact_name", "contact_detail", "images",
+}
+
+// csvCell keeps spreadsheets from running a cell as a formula. Listing text
+// is written by users, so cells starting like a formula get a leading quote.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// exportCSV writes one line per listing. Contacts and images are joined with
+// "|", the separator bulk imports read images with.
+type exportCSV struct {
+	w *csv.Writer
+}
+
+func newExportCSV(w io.Writer) (*exportCSV, error) {
+	// lets Excel open the file as UTF-8, listings are often written in Thai
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+
+	export := &exportCSV{w: csv.NewWriter(w)}
+	return export, export.w.Write(exportColumns)
+}
+
+func (e *exportCSV) write(export ExportAsset) error {
+	var agencyId, agent string
+	if export.AgencyID != nil {
+		agencyId = strconv.FormatInt(*export.AgencyID, 10)
+	}
+	if export.Agent != nil {
+		agent = *export.Agent
+	}
+
+	names := make([]string, 0, len(export.Contacts))
+	details := make([]string, 0, len(export.Contacts))
+	for _, contact := range export.Contacts {
+		names = append(names, contact.Name)
+		details = append(details, contact.Detail)
+	}
+
+	record := []string{
+		strconv.FormatInt(export.ID, 10),
+		export.Owner,
+		agencyId,
+		agent,
+		strconv.FormatInt(export.Price, 10),
+		export.Currency,
+		export.Detail,
+		export.PropertyType,
+		export.Province,
+		strconv.FormatBool(export.Sold),
+		strconv.FormatBool(export.UnderOffer),
+		export.ModerationStatus,
+		export.ExpiresAt.Format(time.RFC3339),
+		export.CreatedAt.Format(time.RFC3339),
+		export.UpdatedAt.Format(time.RFC3339),
+		strings.Join(names, "|"),
+		strings.Join(details, "|"),
+		strings.Join(export.Images, "|"),
+	}
+	for i := range record {
+		record[i] = csvCell(record[i])
+	}
+
+	return e.w.Write(record)
+}
+
+func (e *exportCSV) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// exportJSON writes {"assets": [...], "total": n} one listing at a time.
+type exportJSON struct {
+	w     io.Writer
+	total int
+}
+
+func newExportJSON(w io.Writer) (*exportJSON, error) {
+	_, err := io.WriteString(w, `{"assets":[`)
+	return &exportJSON{w: w}, err
+}
+
+func (e *exportJSON) write(export ExportAsset) error {
+	data, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	if e.total > 0 {
+		data = append([]byte{','}, data...)
+	}
+	e.total++
+
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *exportJSON) close() error {
+	_, err := fmt.Fprintf(e.w, `],"total":%d}`, e.total)
+	return err
+}
+
+func parseExportFormat(c *fiber.Ctx) (string, error) {
+	format := c.Query("format", "csv")
+	switch format {
+	case "csv", "json":
+		return format, nil
+	}
+	return "", fiber.NewError(fiber.StatusBadRequest, "format must be csv or json.")
+}
+
+// sendExport streams the listings to the client a page at a time, so an
+// export is never held in memory as a whole. The first page is read before
+// the response starts, a failing query still gets an error status; a later
+// failure can only cut the download short.
+func (server *Server) sendExport(c *fiber.Ctx, name, format string, page exportPage) error {
+	first, err := page(c.Context(), exportBatchSize, 0)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot export assets.")
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format(dateLayout), format)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	if format == "json" {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	c.Status(fiber.StatusOK)
+
+	// the stream is written after the handler returned, c must not be used in it
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := server.streamExport(context.Background(), w, format, first, page); err != nil {
+			log.Printf("export %s: %v", filename, err)
+		}
+	})
+	return nil
+}
+
+func (server *Server) streamExport(ctx context.Context, w *bufio.Writer, format string, first []db.GetAssetsByUsernameRow, page exportPage) error {
+	var writer interface {
+		write(ExportAsset) error
+		close() error
+	}
+
+	var err error
+	if format == "json" {
+		writer, err = newExportJSON(w)
+	} else {
+		writer, err = newExportCSV(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := server.writeExport(ctx, first, page, writer.write); err != nil {
+		return err
+	}
+	if err := writer.close(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// ExportMyAssets downloads every listing of the user, whatever its status.
+func (server *Server) ExportMyAssets(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	username := user.Username
+	return server.sendExport(c, "assets-"+username, format, func(ctx context.Context, limit, offset int32) ([]db.GetAssetsByUsernameRow, error) {
+		return server.store.GetAssetsByUsername(ctx, db.GetAssetsByUsernameParams{
+			Owner:  username,
+			Limit:  limit,
+			Offset: offset,
+		})
+	})
+}
+
+// ExportAgencyAssets downloads every listing of the member's agency.
+func (server *Server) ExportAgencyAssets(c *fiber.Ctx) error {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	format, err := parseExportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	agencyId := sql.NullInt64{Int64: member.AgencyID, Valid: true}
+
+	return server.sendExport(c, fmt.Sprintf("agency-%d-assets", member.AgencyID), format, func(ctx context.Context, limit, offset int32) ([]db.GetAssetsByUsernameRow, error) {
+		assets, err := server.store.GetAgencyAssets(ctx, db.GetAgencyAssetsParams{
+			AgencyID:   agencyId,
+			ActiveOnly: false,
+			PageLimit:  limit,
+			PageOffset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rows := make([]db.GetAssetsByUsernameRow, 0, len(assets))
+		for _, asset := range assets {
+			rows = append(rows, db.GetAssetsByUsernameRow(asset))
+		}
+		return rows, nil
+	})
+}
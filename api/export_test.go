@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"Condo near BTS":    "Condo near BTS",
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+66 81 234 5678":   "'+66 81 234 5678",
+		"-1+1":              "'-1+1",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"\t=1":              "'\t=1",
+		"\r=1":              "'\r=1",
+		"4500000":           "4500000",
+		"a=b":               "a=b",
+		"2026-03-14T10:00Z": "2026-03-14T10:00Z",
+		"บ้านเดี่ยว =ราคาดี": "บ้านเดี่ยว =ราคาดี",
+	}
+
+	for in, want := range tests {
+		if got := csvCell(in); got != want {
+			t.Errorf("csvCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func exportRow(id int64) db.GetAssetsByUsernameRow {
+	return db.GetAssetsByUsernameRow{
+		ID:               id,
+		Owner:            "somchai",
+		Price:            450000000,
+		Currency:         "THB",
+		Detail:           "=cmd|' /C calc'!A0",
+		PropertyType:     db.PropertyTypeCondo,
+		Province:         "Bangkok",
+		ModerationStatus: db.ModerationStatusApproved,
+	}
+}
+
+func TestWriteExportLoadsExtrasPerPage(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{PublicBaseURL: "https://api.example.com"})
+
+	fake.Handle("GetAssetContactsByAssetIDs", func(args []driver.Value) (dbtest.Result, error) {
+		now := time.Now()
+		return dbtest.Result{Rows: [][]driver.Value{
+			{int64(11), int64(1), "Somchai", "081", now, now},
+			{int64(12), int64(2), "@agent", "-", now, now},
+		}}, nil
+	})
+	fake.Handle("GetAssetImagesByAssetIDs", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{Rows: [][]driver.Value{
+			{int64(21), int64(1), "uploads/a.jpg"},
+			{int64(22), int64(1), "uploads/b.jpg"},
+		}}, nil
+	})
+
+	first := []db.GetAssetsByUsernameRow{exportRow(1), exportRow(2), exportRow(3)}
+	page := func(ctx context.Context, limit, offset int32) ([]db.GetAssetsByUsernameRow, error) {
+		return nil, errors.New("a short first page is the last one")
+	}
+
+	var buf bytes.Buffer
+	writer, err := newExportCSV(&buf)
+	if err != nil {
+		t.Fatalf("newExportCSV: %v", err)
+	}
+	if err := server.writeExport(context.Background(), first, page, writer.write); err != nil {
+		t.Fatalf("writeExport: %v", err)
+	}
+	if err := writer.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	want := []string{"GetAssetContactsByAssetIDs", "GetAssetImagesByAssetIDs"}
+	if got := fake.Calls(); !slices.Equal(got, want) {
+		t.Errorf("queries = %v, want %v", got, want)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("cannot read the csv back: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d lines, want a header and 3 listings", len(records))
+	}
+
+	column := func(name string) int { return slices.Index(exportColumns, name) }
+	listing1, listing2 := records[1], records[2]
+	if got := listing1[column("images")]; got != "https://api.example.com/static/a.jpg|https://api.example.com/static/b.jpg" {
+		t.Errorf("images of listing 1 = %q", got)
+	}
+	if got := listing2[column("contact_name")]; got != "'@agent" {
+		t.Errorf("contact_name of listing 2 = %q, want it quoted", got)
+	}
+	if got := listing2[column("contact_detail")]; got != "'-" {
+		t.Errorf("contact_detail of listing 2 = %q, want it quoted", got)
+	}
+	if got := listing1[column("detail")]; got != "'=cmd|' /C calc'!A0" {
+		t.Errorf("detail = %q, want it quoted", got)
+	}
+	if got := records[3][column("images")]; got != "" {
+		t.Errorf("images of listing 3 = %q, want none", got)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := newExportJSON(&buf)
+	if err != nil {
+		t.Fatalf("newExportJSON: %v", err)
+	}
+	for _, id := range []int64{1, 2} {
+		if err := writer.write(ExportAsset{ID: id, Contacts: []ExportContact{}, Images: []string{}}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := writer.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	var rsp struct {
+		Assets []ExportAsset `json:"assets"`
+		Total  int           `json:"total"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rsp); err != nil {
+		t.Fatalf("export is not valid json: %v\n%s", err, buf.String())
+	}
+	if rsp.Total != 2 || len(rsp.Assets) != 2 || rsp.Assets[1].ID != 2 {
+		t.Errorf("got %+v", rsp)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+// withFavorites fills in favorite_count and is_favorited when the viewer is
+// logged in. Anonymous visitors get the listing without either field.
+func (server *Server) withFavorites(c *fiber.Ctx, assets []AssetResponse) error {
+	user, ok := currentUser(c)
+	if !ok || len(assets) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(assets))
+	for _, asset := range assets {
+		ids = append(ids, asset.ID)
+	}
+
+	counts, err := server.store.GetFavoriteCounts(c.Context(), ids)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count favorites.")
+	}
+
+	favorited, err := server.store.GetFavoritedAssetIDs(c.Context(), db.GetFavoritedAssetIDsParams{
+		Username: user.Username,
+		AssetIds: ids,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get favorites.")
+	}
+
+	countById := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		countById[count.AssetID] = count.FavoriteCount
+	}
+
+	favoritedById := make(map[int64]bool, len(favorited))
+	for _, id := range favorited {
+		favoritedById[id] = true
+	}
+
+	for i := range assets {
+		count := countById[assets[i].ID]
+		isFavorited := favoritedById[assets[i].ID]
+		assets[i].FavoriteCount = &count
+		assets[i].IsFavorited = &isFavorited
+	}
+
+	return nil
+}
+
+func (server *Server) AddFavorite(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	added, err := server.store.AddFavorite(c.Context(), db.AddFavoriteParams{
+		Username: user.Username,
+		AssetID:  asset.ID,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "add favorite failed.")
+	}
+
+	if added > 0 && asset.Owner != user.Username {
+		server.notifyUser(c.Context(), asset.Owner, notify.Notification{
+			Kind:  "favorite_added",
+			Title: "Someone saved your listing",
+			Body:  fmt.Sprintf("%s added listing #%d to their favorites.", user.Username, asset.ID),
+			Data:  map[string]any{"asset_id": asset.ID, "username": user.Username},
+		})
+	}
+
+	return okResponse(c, "add favorite successfully.")
+}
+
+func (server *Server) RemoveFavorite(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	err = server.store.RemoveFavorite(c.Context(), db.RemoveFavoriteParams{
+		Username: user.Username,
+		AssetID:  int64(assetId),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "remove favorite failed.")
+	}
+
+	return okResponse(c, "remove favorite successfully.")
+}
+
+func (server *Server) MyFavorites(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	assets, err := server.store.GetFavoriteAssets(c.Context(), db.GetFavoriteAssetsParams{
+		Username: user.Username,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get favorites.")
+	}
+
+	total, err := server.store.CountFavoritesByUsername(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count favorites.")
+	}
+
+	rsp := newFavoriteAssetResponses(assets)
+	if err := server.withFavorites(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withTranslations(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withPromotions(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.withCurrency(c, rsp); err != nil {
+		return err
+	}
+
+	if err := server.hideContacts(c, rsp); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"assets": rsp,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
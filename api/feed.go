@@ -0,0 +1,315 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sangketkit01/real-estate-backend/currency"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// The public feed uses the Trovit homes XML format, which most listing
+// aggregators read.
+type feedDocument struct {
+	XMLName xml.Name `xml:"trovit"`
+	Ads     []feedAd `xml:"ad"`
+}
+
+type feedAd struct {
+	ID               string        `xml:"id"`
+	URL              string        `xml:"url"`
+	Title            string        `xml:"title"`
+	Type             string        `xml:"type"`
+	Agency           string        `xml:"agency,omitempty"`
+	Content          string        `xml:"content"`
+	Price            int64         `xml:"price"`
+	PropertyType     string        `xml:"property_type"`
+	Region           string        `xml:"region"`
+	ContactName      string        `xml:"contact_name,omitempty"`
+	ContactEmail     string        `xml:"contact_email,omitempty"`
+	ContactTelephone string        `xml:"contact_telephone,omitempty"`
+	Pictures         *feedPictures `xml:"pictures,omitempty"`
+	Date             string        `xml:"date"`
+}
+
+type feedPictures struct {
+	Pictures []feedPicture `xml:"picture"`
+}
+
+type feedPicture struct {
+	URL string `xml:"picture_url"`
+}
+
+var errFeedUnavailable = errors.New("feed could not be built")
+
+// propertyFeed is a rendered feed. Building it reads every live listing, so
+// it is kept for FEED_CACHE_TTL and served to all pollers in the meantime.
+type propertyFeed struct {
+	body         []byte
+	etag         string
+	lastModified time.Time
+	expiresAt    time.Time
+}
+
+// feedCache holds the last feed. The lock only guards the fields, a feed is
+// built without it while building is set, and the other pollers get the
+// stale feed or wait for the new one.
+type feedCache struct {
+	mu       sync.Mutex
+	feed     *propertyFeed
+	building chan struct{}
+}
+
+// feedPrice gives a price in whole baht, aggregators read the feed as a Thai
+// one.
+func feedPrice(price int64, code string, rates map[string]currency.Rate) int64 {
+	from, fromOk := rates[code]
+	baht, bahtOk := rates[currency.Base]
+	if !fromOk || !bahtOk {
+		return price
+	}
+
+	baht.MinorUnits = 0
+	converted, ok := currency.Convert(price, from, baht)
+	if !ok {
+		return price
+	}
+	return converted
+}
+
+func (server *Server) newFeedAd(ctx context.Context, asset db.GetAllAssetsRow, contacts []db.AssetContact, images []db.AssetImage, agencies map[int64]string, rates map[string]currency.Rate) (feedAd, time.Time, error) {
+	ad := feedAd{
+		ID:           strconv.FormatInt(asset.ID, 10),
+		URL:          server.listingURL(asset.Slug),
+		Title:        listingTitle(asset.PropertyType, asset.Province),
+		Type:         "For Sale",
+		Content:      asset.Detail,
+		Price:        feedPrice(asset.Price, asset.Currency, rates),
+		PropertyType: string(asset.PropertyType),
+		Region:       asset.Province,
+		Date:         asset.CreatedAt.Format("02/01/2006"),
+	}
+
+	if asset.AgencyID.Valid {
+		name, ok := agencies[asset.AgencyID.Int64]
+		if !ok {
+			agency, err := server.store.GetAgency(ctx, asset.AgencyID.Int64)
+			if err != nil {
+				return feedAd{}, time.Time{}, err
+			}
+			name = agency.Name
+			agencies[asset.AgencyID.Int64] = name
+		}
+		ad.Agency = name
+	}
+
+	modified := asset.UpdatedAt
+
+	// buyers only get contact details through an inquiry when they are withheld
+	if len(contacts) > 0 && !server.config.HideContactUntilReply {
+		contact := contacts[0]
+		ad.ContactName = contact.ContactName
+		if strings.Contains(contact.ContactDetail, "@") {
+			ad.ContactEmail = contact.ContactDetail
+		} else {
+			ad.ContactTelephone = contact.ContactDetail
+		}
+	}
+	for _, contact := range contacts {
+		if contact.UpdatedAt.After(modified) {
+			modified = contact.UpdatedAt
+		}
+	}
+
+	if len(images) > 0 {
+		ad.Pictures = &feedPictures{}
+		for _, image := range images {
+			ad.Pictures.Pictures = append(ad.Pictures.Pictures, feedPicture{URL: server.uploadURL(image.ImageUrl)})
+		}
+	}
+
+	return ad, modified, nil
+}
+
+// buildPropertyFeed renders every approved, unexpired and unsold listing.
+func (server *Server) buildPropertyFeed(ctx context.Context) (*propertyFeed, error) {
+	doc := feedDocument{Ads: []feedAd{}}
+	var lastModified time.Time
+
+	// GetAllAssets returns a row per contact and image, an asset can span pages
+	seen := map[int64]bool{}
+	agencies := map[int64]string{}
+
+	rates, err := server.exchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for offset := int32(0); ; offset += exportBatchSize {
+		assets, err := server.store.GetAllAssets(ctx, db.GetAllAssetsParams{
+			Sort:       "newest",
+			PageLimit:  exportBatchSize,
+			PageOffset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []db.GetAllAssetsRow
+		assetIds := make([]int64, 0, len(assets))
+		for _, asset := range assets {
+			if seen[asset.ID] || asset.Status {
+				continue
+			}
+			seen[asset.ID] = true
+			page = append(page, asset)
+			assetIds = append(assetIds, asset.ID)
+		}
+
+		contacts, images, err := server.assetExtras(ctx, assetIds)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, asset := range page {
+			ad, modified, err := server.newFeedAd(ctx, asset, contacts[asset.ID], images[asset.ID], agencies, rates)
+			if err != nil {
+				return nil, err
+			}
+			doc.Ads = append(doc.Ads, ad)
+
+			if modified.After(lastModified) {
+				lastModified = modified
+			}
+		}
+
+		if len(assets) < exportBatchSize {
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+
+	return &propertyFeed{
+		body:         buf.Bytes(),
+		etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		lastModified: lastModified.UTC().Truncate(time.Second),
+		expiresAt:    time.Now().Add(server.config.FeedCacheTTL),
+	}, nil
+}
+
+func (server *Server) propertyFeed(ctx context.Context) (*propertyFeed, error) {
+	cache := &server.feedCache
+
+	cache.mu.Lock()
+	stale := cache.feed
+	if stale != nil && time.Now().Before(stale.expiresAt) {
+		cache.mu.Unlock()
+		return stale, nil
+	}
+
+	if building := cache.building; building != nil {
+		cache.mu.Unlock()
+		if stale != nil {
+			return stale, nil
+		}
+
+		select {
+		case <-building:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		if cache.feed == nil {
+			return nil, errFeedUnavailable
+		}
+		return cache.feed, nil
+	}
+
+	building := make(chan struct{})
+	cache.building = building
+	cache.mu.Unlock()
+
+	feed, err := server.buildPropertyFeed(ctx)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.building = nil
+	close(building)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// removed listings and new images change the feed without a newer updated_at
+	if old := cache.feed; old != nil && old.etag != feed.etag && !feed.lastModified.After(old.lastModified) {
+		feed.lastModified = time.Now().UTC().Truncate(time.Second)
+	}
+	cache.feed = feed
+
+	return feed, nil
+}
+
+// notModified reports whether the poller already has this version of the
+// feed. If-None-Match wins over If-Modified-Since, as in RFC 9110.
+func notModified(c *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince)); err == nil {
+		return !lastModified.After(since)
+	}
+
+	return false
+}
+
+// PropertyFeed serves all live listings as XML for aggregators. Pollers
+// should send If-None-Match or If-Modified-Since and get 304 when nothing
+// changed.
+func (server *Server) PropertyFeed(c *fiber.Ctx) error {
+	feed, err := server.propertyFeed(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot build feed.")
+	}
+
+	c.Set(fiber.HeaderETag, feed.etag)
+	c.Set(fiber.HeaderLastModified, feed.lastModified.Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(server.config.FeedCacheTTL.Seconds())))
+
+	if notModified(c, feed.etag, feed.lastModified) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(feed.body)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestPropertyFeedServesStaleFeedWhileBuilding(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{FeedCacheTTL: time.Minute})
+
+	stale := &propertyFeed{body: []byte("<trovit/>"), etag: `"stale"`, expiresAt: time.Now().Add(-time.Second)}
+	server.feedCache.feed = stale
+
+	// the build blocks on its first query until the test lets it go
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fake.Handle("ListExchangeRates", func(args []driver.Value) (dbtest.Result, error) {
+		close(started)
+		<-release
+		return dbtest.Result{}, errors.New("database is down")
+	})
+
+	built := make(chan error)
+	go func() {
+		_, err := server.propertyFeed(context.Background())
+		built <- err
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		feed, err := server.propertyFeed(context.Background())
+		if err != nil || feed != stale {
+			t.Errorf("got %v, %v while the feed is rebuilt, want the stale feed", feed, err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a poller waited for the feed build")
+	}
+
+	close(release)
+	if err := <-built; err == nil {
+		t.Error("got no error from the failed build")
+	}
+	if server.feedCache.feed != stale || server.feedCache.building != nil {
+		t.Error("a failed build must keep the old feed and allow the next build")
+	}
+}
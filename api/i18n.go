@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/th"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	th_translations "github.com/go-playground/validator/v10/translations/th"
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// Locales the API answers in. English is the language of the code, so every
+// message exists in it; Thai messages come from thaiMessages.
+var supportedLocales = []string{db.DefaultLocale, "en"}
+
+var (
+	universalTranslator = ut.New(en.New(), en.New(), th.New())
+
+	// validate is shared by all handlers, translations are registered on
+	// the instance that reports the errors
+	validate = newValidator()
+)
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	enTrans, _ := universalTranslator.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		panic(err)
+	}
+
+	thTrans, _ := universalTranslator.GetTranslator("th")
+	if err := th_translations.RegisterDefaultTranslations(v, thTrans); err != nil {
+		panic(err)
+	}
+	for message, translation := range thaiMessages {
+		if err := thTrans.Add(message, translation, false); err != nil {
+			panic(err)
+		}
+	}
+	if err := thTrans.Add(invalidParamKey, "{0} ไม่ถูกต้อง", false); err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// jsonFieldName makes validation errors name fields the way clients send them.
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func isSupportedLocale(locale string) bool {
+	for _, supported := range supportedLocales {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLocale picks the locale from ?lang= or else Accept-Language. It is
+// empty when the client has no preference we support, listings then show
+// their primary language and messages stay in English.
+func resolveLocale(c *fiber.Ctx) string {
+	if lang := strings.ToLower(c.Query("lang")); isSupportedLocale(lang) {
+		return lang
+	}
+
+	if c.Get(fiber.HeaderAcceptLanguage) == "" {
+		return ""
+	}
+	return c.AcceptsLanguages(supportedLocales...)
+}
+
+// LocaleMiddleware resolves the locale once per request.
+func (server *Server) LocaleMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("locale", resolveLocale(c))
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+func requestLocale(c *fiber.Ctx) string {
+	if locale, ok := c.Locals("locale").(string); ok {
+		return locale
+	}
+	return resolveLocale(c)
+}
+
+func requestTranslator(c *fiber.Ctx) ut.Translator {
+	trans, _ := universalTranslator.FindTranslator(requestLocale(c))
+	return trans
+}
+
+const (
+	invalidParamKey = "invalid-param"
+	serverErrorKey  = "server-error"
+)
+
+var invalidParamMessage = regexp.MustCompile(`^invalid ([a-z_]+)\.$`)
+
+// translateMessage looks up the message of a fiber error in the request's
+// locale. Client errors without a translation are returned as they are, server
+// errors fall back to a generic message.
+func translateMessage(c *fiber.Ctx, code int, message string) string {
+	trans := requestTranslator(c)
+	if trans.Locale() == "en" {
+		return message
+	}
+
+	if translated, err := trans.T(message); err == nil {
+		return translated
+	}
+
+	if m := invalidParamMessage.FindStringSubmatch(message); m != nil {
+		if translated, err := trans.T(invalidParamKey, m[1]); err == nil {
+			return translated
+		}
+	}
+
+	if code >= fiber.StatusInternalServerError {
+		if translated, err := trans.T(serverErrorKey); err == nil {
+			return translated
+		}
+	}
+
+	return message
+}
+
+// translateValidation turns validation errors into one message and a message
+// per field, both in the request's locale.
+func translateValidation(c *fiber.Ctx, err error) (string, map[string]string, bool) {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return "", nil, false
+	}
+
+	trans := requestTranslator(c)
+	fields := make(map[string]string, len(fieldErrors))
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		message := fe.Translate(trans)
+		fields[fe.Field()] = message
+		messages = append(messages, message)
+	}
+
+	return strings.Join(messages, "; "), fields, true
+}
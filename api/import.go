@@ -0,0 +1,414 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sangketkit01/real-estate-backend/currency"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/importer"
+	"github.com/sangketkit01/real-estate-backend/worker"
+)
+
+const (
+	maxImportRows      = 2000
+	maxImagesPerRow    = 20
+	importArchivesPath = "uploads/imports"
+)
+
+type AssetImportResponse struct {
+	ID            int64      `json:"id"`
+	Filename      string     `json:"filename"`
+	Status        string     `json:"status"`
+	CreatedBy     string     `json:"created_by"`
+	TotalRows     int32      `json:"total_rows"`
+	ProcessedRows int32      `json:"processed_rows"`
+	ImportedRows  int32      `json:"imported_rows"`
+	FailedRows    int32      `json:"failed_rows"`
+	Progress      float64    `json:"progress"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func importProgress(processed, total int32) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(processed*1000/total) / 10
+}
+
+func newAssetImportResponse(assetImport db.ListAssetImportsRow) AssetImportResponse {
+	return AssetImportResponse{
+		ID:            assetImport.ID,
+		Filename:      assetImport.Filename,
+		Status:        string(assetImport.Status),
+		CreatedBy:     assetImport.CreatedBy,
+		TotalRows:     assetImport.TotalRows,
+		ProcessedRows: assetImport.ProcessedRows,
+		ImportedRows:  assetImport.ImportedRows,
+		FailedRows:    assetImport.FailedRows,
+		Progress:      importProgress(assetImport.ProcessedRows, assetImport.TotalRows),
+		FinishedAt:    nullTime(assetImport.FinishedAt),
+		CreatedAt:     assetImport.CreatedAt,
+	}
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param() + " characters"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	}
+	return fmt.Sprintf("failed the %q rule", fe.Tag())
+}
+
+// parseImportPrice accepts baht prices the way spreadsheets tend to format
+// them, like "4,500,000" or "฿4500000.50", and gives them in satang.
+func parseImportPrice(s string) (int64, error) {
+	s = strings.NewReplacer(",", "", " ", "", "฿", "", "THB", "", "thb", "").Replace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return currency.ParseAmount(s, currency.BaseMinorUnits)
+}
+
+// validateImportRows checks every record with the same rules CreateAsset
+// applies to an AssetRequest, plus the contact and image columns.
+func (server *Server) validateImportRows(records []importer.Record, archive *zip.Reader) ([]importer.Row, []importer.RowError) {
+	hosts := importer.ParseHosts(server.config.ImportImageHosts)
+
+	rows := make([]importer.Row, 0, len(records))
+	var rowErrors []importer.RowError
+	for _, record := range records {
+		values := record.Values
+		fail := func(field, message string) {
+			rowErrors = append(rowErrors, importer.RowError{Line: record.Line, Field: field, Message: message})
+		}
+		failed := len(rowErrors)
+
+		price, err := parseImportPrice(values[importer.FieldPrice])
+		if err != nil {
+			fail(importer.FieldPrice, "must be a number with at most two decimals")
+		}
+
+		req := AssetRequest{
+			Price:        price,
+			Detail:       values[importer.FieldDetail],
+			PropertyType: strings.ToLower(values[importer.FieldPropertyType]),
+			Province:     values[importer.FieldProvince],
+		}
+		if err := validate.Struct(req); err != nil {
+			var fieldErrors validator.ValidationErrors
+			if errors.As(err, &fieldErrors) {
+				for _, fe := range fieldErrors {
+					// a price that did not parse is already reported
+					if fe.Field() == importer.FieldPrice && values[importer.FieldPrice] != "" && price == 0 {
+						continue
+					}
+					fail(fe.Field(), validationMessage(fe))
+				}
+			}
+		}
+		if req.PropertyType == "" {
+			req.PropertyType = string(db.PropertyTypeOther)
+		}
+
+		contactName, contactDetail := values[importer.FieldContactName], values[importer.FieldContactDetail]
+		if (contactName == "") != (contactDetail == "") {
+			fail(importer.FieldContactDetail, "contact_name and contact_detail must be given together")
+		}
+
+		images := importer.SplitImages(values[importer.FieldImages])
+		if len(images) > maxImagesPerRow {
+			fail(importer.FieldImages, fmt.Sprintf("at most %d images per listing", maxImagesPerRow))
+		}
+		for _, image := range images {
+			switch {
+			case importer.IsURL(image):
+				if !importer.AllowedURL(image, hosts) {
+					fail(importer.FieldImages, image+" is not on the image host allowlist")
+				}
+			case archive == nil:
+				fail(importer.FieldImages, image+" is not a URL and no zip file was uploaded")
+			case importer.FindInZip(archive, image) == nil:
+				fail(importer.FieldImages, image+" is not in the zip file")
+			}
+		}
+
+		if len(rowErrors) > failed {
+			continue
+		}
+
+		rows = append(rows, importer.Row{
+			Line:          record.Line,
+			Price:         req.Price,
+			Detail:        req.Detail,
+			PropertyType:  req.PropertyType,
+			Province:      req.Province,
+			ContactName:   contactName,
+			ContactDetail: contactDetail,
+			Images:        images,
+		})
+	}
+
+	return rows, rowErrors
+}
+
+func readFormFile(file *multipart.FileHeader) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
+
+// ImportAssets checks a CSV or XLSX sheet of listings for the user's agency
+// and, unless dry_run is set, imports it in the background. Fields are
+// matched to columns through the optional mapping, a JSON object like
+// {"price": "Asking price"}. Images are URLs on the allowed hosts or file
+// names inside the optional images zip.
+func (server *Server) ImportAssets(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "file is required.")
+	}
+
+	var mapping importer.Mapping
+	if s := c.FormValue("mapping"); s != "" {
+		if err := json.Unmarshal([]byte(s), &mapping); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "mapping must be a JSON object of field to column name.")
+		}
+	}
+
+	data, err := readFormFile(file)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "cannot read file.")
+	}
+
+	sheet, err := importer.ReadSheet(file.Filename, data)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	records, err := importer.MapRecords(sheet, mapping)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if len(records) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "file has no listings.")
+	}
+
+	if len(records) > maxImportRows {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("at most %d listings can be imported at once.", maxImportRows))
+	}
+
+	var archive *zip.Reader
+	var archiveData []byte
+	if imagesFile, err := c.FormFile("images"); err == nil {
+		if archiveData, err = readFormFile(imagesFile); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "cannot read images.")
+		}
+
+		if archive, err = zip.NewReader(bytes.NewReader(archiveData), int64(len(archiveData))); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "images must be a zip file.")
+		}
+	}
+
+	rows, rowErrors := server.validateImportRows(records, archive)
+
+	dryRun := c.QueryBool("dry_run") || c.FormValue("dry_run") == "true"
+	if dryRun || len(rowErrors) > 0 {
+		status := fiber.StatusOK
+		if !dryRun {
+			status = fiber.StatusUnprocessableEntity
+		}
+
+		if rowErrors == nil {
+			rowErrors = []importer.RowError{}
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"dry_run":    dryRun,
+			"total_rows": len(records),
+			"valid_rows": len(rows),
+			"errors":     rowErrors,
+		})
+	}
+
+	items, err := json.Marshal(rows)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "import failed.")
+	}
+
+	var archivePath sql.NullString
+	if archive != nil {
+		if err := os.MkdirAll(importArchivesPath, 0o755); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "cannot store images.")
+		}
+
+		path := fmt.Sprintf("%s/%d_%s.zip", importArchivesPath, time.Now().UnixNano(), user.Username)
+		if err := os.WriteFile("./"+path, archiveData, 0o644); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "cannot store images.")
+		}
+		archivePath = sql.NullString{String: path, Valid: true}
+	}
+
+	moderationStatus, moderationReason := server.newListingStatus(user)
+	assetImport, err := server.store.CreateAssetImport(c.Context(), db.CreateAssetImportParams{
+		AgencyID:         member.AgencyID,
+		CreatedBy:        user.Username,
+		Filename:         file.Filename,
+		Items:            items,
+		ArchivePath:      archivePath,
+		ModerationStatus: moderationStatus,
+		ModerationReason: moderationReason,
+		ExpiresAt:        time.Now().Add(server.listingDuration()),
+		TotalRows:        int32(len(rows)),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "import failed.")
+	}
+
+	job, err := server.jobs.Enqueue(c.Context(), worker.JobImportAssets, worker.ImportAssetsPayload{ImportID: assetImport.ID})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot start import.")
+	}
+
+	if err := server.store.SetAssetImportJob(c.Context(), db.SetAssetImportJobParams{
+		ID:    assetImport.ID,
+		JobID: sql.NullInt64{Int64: job.ID, Valid: true},
+	}); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot start import.")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(newAssetImportResponse(db.ListAssetImportsRow{
+		ID:        assetImport.ID,
+		AgencyID:  assetImport.AgencyID,
+		CreatedBy: assetImport.CreatedBy,
+		Filename:  assetImport.Filename,
+		Status:    assetImport.Status,
+		TotalRows: assetImport.TotalRows,
+		CreatedAt: assetImport.CreatedAt,
+	}))
+}
+
+func (server *Server) ListAssetImports(c *fiber.Ctx) error {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	imports, err := server.store.ListAssetImports(c.Context(), db.ListAssetImportsParams{
+		AgencyID: member.AgencyID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get imports.")
+	}
+
+	total, err := server.store.CountAssetImports(c.Context(), member.AgencyID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count imports.")
+	}
+
+	rsp := make([]AssetImportResponse, 0, len(imports))
+	for _, assetImport := range imports {
+		rsp = append(rsp, newAssetImportResponse(assetImport))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"imports": rsp,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
+// GetAssetImport reports the progress of an import and the rows that failed.
+func (server *Server) GetAssetImport(c *fiber.Ctx) error {
+	member, err := server.getMembership(c)
+	if err != nil {
+		return err
+	}
+
+	importId, err := strconv.Atoi(c.Params("import_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid import_id.")
+	}
+
+	assetImport, err := server.store.GetAssetImport(c.Context(), int64(importId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "import not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get import.")
+	}
+
+	if assetImport.AgencyID != member.AgencyID {
+		return fiber.NewError(fiber.StatusNotFound, "import not found.")
+	}
+
+	rsp := newAssetImportResponse(db.ListAssetImportsRow{
+		ID:            assetImport.ID,
+		AgencyID:      assetImport.AgencyID,
+		CreatedBy:     assetImport.CreatedBy,
+		Filename:      assetImport.Filename,
+		Status:        assetImport.Status,
+		TotalRows:     assetImport.TotalRows,
+		ProcessedRows: assetImport.ProcessedRows,
+		ImportedRows:  assetImport.ImportedRows,
+		FailedRows:    assetImport.FailedRows,
+		JobID:         assetImport.JobID,
+		FinishedAt:    assetImport.FinishedAt,
+		CreatedAt:     assetImport.CreatedAt,
+	})
+
+	// the job gave up after its last retry
+	if assetImport.Status != db.ImportStatusCompleted && assetImport.JobID.Valid {
+		if job, err := server.store.GetJob(c.Context(), assetImport.JobID.Int64); err == nil && job.Status == db.JobStatusDead {
+			rsp.Status = string(db.ImportStatusFailed)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"import": rsp,
+		"errors": assetImport.Errors,
+	})
+}
@@ -0,0 +1,440 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+type InquiryMessageRequest struct {
+	Message string `json:"message" validate:"required,max=5000"`
+}
+
+type MarkInquiryReadRequest struct {
+	// UpToID marks only messages up to and including this id, all when omitted.
+	UpToID *int64 `json:"up_to_id"`
+}
+
+type InquiryThreadResponse struct {
+	ID              int64      `json:"id"`
+	AssetID         int64      `json:"asset_id"`
+	Buyer           string     `json:"buyer"`
+	Seller          string     `json:"seller"`
+	SellerRepliedAt *time.Time `json:"seller_replied_at"`
+	LastMessageAt   time.Time  `json:"last_message_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type InquiryThreadSummaryResponse struct {
+	InquiryThreadResponse
+	AssetPrice  int64  `json:"asset_price"`
+	AssetDetail string `json:"asset_detail"`
+	LastMessage string `json:"last_message"`
+	UnreadCount int64  `json:"unread_count"`
+}
+
+type InquiryMessageResponse struct {
+	ID        int64      `json:"id"`
+	ThreadID  int64      `json:"thread_id"`
+	Sender    string     `json:"sender"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newInquiryThreadResponse(thread db.InquiryThread) InquiryThreadResponse {
+	return InquiryThreadResponse{
+		ID:              thread.ID,
+		AssetID:         thread.AssetID,
+		Buyer:           thread.Buyer,
+		Seller:          thread.Seller,
+		SellerRepliedAt: nullTime(thread.SellerRepliedAt),
+		LastMessageAt:   thread.LastMessageAt,
+		CreatedAt:       thread.CreatedAt,
+	}
+}
+
+func newInquiryThreadSummaryResponses(threads []db.ListInquiryThreadsRow) []InquiryThreadSummaryResponse {
+	rsp := make([]InquiryThreadSummaryResponse, 0, len(threads))
+	for _, thread := range threads {
+		rsp = append(rsp, InquiryThreadSummaryResponse{
+			InquiryThreadResponse: InquiryThreadResponse{
+				ID:              thread.ID,
+				AssetID:         thread.AssetID,
+				Buyer:           thread.Buyer,
+				Seller:          thread.Seller,
+				SellerRepliedAt: nullTime(thread.SellerRepliedAt),
+				LastMessageAt:   thread.LastMessageAt,
+				CreatedAt:       thread.CreatedAt,
+			},
+			AssetPrice:  thread.AssetPrice,
+			AssetDetail: thread.AssetDetail,
+			LastMessage: thread.LastMessage,
+			UnreadCount: thread.UnreadCount,
+		})
+	}
+	return rsp
+}
+
+func newInquiryMessageResponse(message db.InquiryMessage) InquiryMessageResponse {
+	return InquiryMessageResponse{
+		ID:        message.ID,
+		ThreadID:  message.ThreadID,
+		Sender:    message.Sender,
+		Body:      message.Body,
+		ReadAt:    nullTime(message.ReadAt),
+		CreatedAt: message.CreatedAt,
+	}
+}
+
+// hideContacts withholds seller contact details until the seller has replied to
+// one of the viewer's inquiries, so first contact goes through the platform.
+func (server *Server) hideContacts(c *fiber.Ctx, assets []AssetResponse) error {
+	if !server.config.HideContactUntilReply || len(assets) == 0 {
+		return nil
+	}
+
+	user, loggedIn := currentUser(c)
+
+	replied := make(map[int64]bool)
+	if loggedIn {
+		ids := make([]int64, 0, len(assets))
+		for _, asset := range assets {
+			ids = append(ids, asset.ID)
+		}
+
+		assetIds, err := server.store.GetRepliedInquiryAssetIDs(c.Context(), db.GetRepliedInquiryAssetIDsParams{
+			Buyer:    user.Username,
+			AssetIds: ids,
+		})
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "cannot get inquiries.")
+		}
+
+		for _, id := range assetIds {
+			replied[id] = true
+		}
+	}
+
+	for i := range assets {
+		if (loggedIn && assets[i].Owner == user.Username) || replied[assets[i].ID] {
+			continue
+		}
+
+		if assets[i].ContactID == nil {
+			continue
+		}
+
+		assets[i].ContactID = nil
+		assets[i].ContactName = nil
+		assets[i].ContactDetail = nil
+		assets[i].ContactHidden = true
+	}
+
+	return nil
+}
+
+// notifyInquiryMessage tells the other side of the thread about a new message.
+func (server *Server) notifyInquiryMessage(ctx context.Context, result db.SendInquiryMessageTxResult) {
+	recipient := result.Thread.Seller
+	if result.Message.Sender == result.Thread.Seller {
+		recipient = result.Thread.Buyer
+	}
+
+	server.notifyUser(ctx, recipient, notify.Notification{
+		Kind:  "inquiry_message",
+		Title: "New message from " + result.Message.Sender,
+		Body:  result.Message.Body,
+		Data: map[string]any{
+			"thread_id":  result.Thread.ID,
+			"asset_id":   result.Thread.AssetID,
+			"message_id": result.Message.ID,
+		},
+	})
+}
+
+func parseInquiryMessage(c *fiber.Ctx) (string, error) {
+	var req InquiryMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+
+	if err := validate.Struct(req); err != nil {
+		return "", err
+	}
+
+	return req.Message, nil
+}
+
+// getInquiryThread loads :thread_id for one of its two participants.
+func (server *Server) getInquiryThread(c *fiber.Ctx) (db.InquiryThread, error) {
+	user := c.Locals("user").(db.User)
+
+	threadId, err := strconv.Atoi(c.Params("thread_id"))
+	if err != nil {
+		return db.InquiryThread{}, fiber.NewError(fiber.StatusBadRequest, "invalid thread_id.")
+	}
+
+	thread, err := server.store.GetInquiryThread(c.Context(), int64(threadId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return thread, fiber.NewError(fiber.StatusNotFound, "inquiry not found.")
+		}
+
+		return thread, fiber.NewError(fiber.StatusInternalServerError, "cannot get inquiry.")
+	}
+
+	if thread.Buyer != user.Username && thread.Seller != user.Username {
+		return thread, fiber.NewError(fiber.StatusNotFound, "inquiry not found.")
+	}
+
+	return thread, nil
+}
+
+func (server *Server) StartInquiry(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	message, err := parseInquiryMessage(c)
+	if err != nil {
+		return err
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	if asset.Owner == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "cannot send an inquiry about your own asset.")
+	}
+
+	result, err := server.store.SendInquiryMessageTx(c.Context(), db.SendInquiryMessageTxParams{
+		AssetID: asset.ID,
+		Buyer:   user.Username,
+		Seller:  asset.Owner,
+		Sender:  user.Username,
+		Body:    message,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "send inquiry failed.")
+	}
+
+	server.notifyInquiryMessage(c.Context(), result)
+	server.emitInquiryReceived(c.Context(), result)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"thread":  newInquiryThreadResponse(result.Thread),
+		"message": newInquiryMessageResponse(result.Message),
+	})
+}
+
+func (server *Server) SendInquiryMessage(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	thread, err := server.getInquiryThread(c)
+	if err != nil {
+		return err
+	}
+
+	message, err := parseInquiryMessage(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := server.store.SendInquiryMessageTx(c.Context(), db.SendInquiryMessageTxParams{
+		ThreadID: thread.ID,
+		Sender:   user.Username,
+		Body:     message,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "send message failed.")
+	}
+
+	server.notifyInquiryMessage(c.Context(), result)
+	server.emitInquiryReceived(c.Context(), result)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"thread":  newInquiryThreadResponse(result.Thread),
+		"message": newInquiryMessageResponse(result.Message),
+	})
+}
+
+// GetInquiryMessages returns messages newest first. Pass the returned
+// next_before as ?before= to load older messages.
+func (server *Server) GetInquiryMessages(c *fiber.Ctx) error {
+	thread, err := server.getInquiryThread(c)
+	if err != nil {
+		return err
+	}
+
+	var before sql.NullInt64
+	if s := c.Query("before"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid before.")
+		}
+		before = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	messages, err := server.store.GetInquiryMessages(c.Context(), db.GetInquiryMessagesParams{
+		ThreadID:  thread.ID,
+		BeforeID:  before,
+		PageLimit: int32(limit),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get messages.")
+	}
+
+	rsp := make([]InquiryMessageResponse, 0, len(messages))
+	for _, message := range messages {
+		rsp = append(rsp, newInquiryMessageResponse(message))
+	}
+
+	var nextBefore *int64
+	if len(messages) == limit {
+		nextBefore = &messages[len(messages)-1].ID
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"thread":      newInquiryThreadResponse(thread),
+		"messages":    rsp,
+		"next_before": nextBefore,
+	})
+}
+
+func (server *Server) MarkInquiryRead(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	thread, err := server.getInquiryThread(c)
+	if err != nil {
+		return err
+	}
+
+	var req MarkInquiryReadRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	var upTo sql.NullInt64
+	if req.UpToID != nil {
+		upTo = sql.NullInt64{Int64: *req.UpToID, Valid: true}
+	}
+
+	marked, err := server.store.MarkInquiryMessagesRead(c.Context(), db.MarkInquiryMessagesReadParams{
+		ThreadID: thread.ID,
+		Reader:   user.Username,
+		UpToID:   upTo,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "mark messages read failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"marked": marked,
+	})
+}
+
+func (server *Server) listInquiryThreads(c *fiber.Ctx, assetId sql.NullInt64) error {
+	user := c.Locals("user").(db.User)
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	threads, err := server.store.ListInquiryThreads(c.Context(), db.ListInquiryThreadsParams{
+		Username:   user.Username,
+		AssetID:    assetId,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get inquiries.")
+	}
+
+	total, err := server.store.CountInquiryThreads(c.Context(), db.CountInquiryThreadsParams{
+		Username: user.Username,
+		AssetID:  assetId,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count inquiries.")
+	}
+
+	unread, err := server.store.CountUnreadInquiryMessages(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count unread messages.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"threads": newInquiryThreadSummaryResponses(threads),
+		"unread":  unread,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
+func (server *Server) MyInquiries(c *fiber.Ctx) error {
+	var assetId sql.NullInt64
+	if s := c.Query("asset_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+		}
+		assetId = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	return server.listInquiryThreads(c, assetId)
+}
+
+// AssetInquiries is the seller's inbox for a single listing.
+func (server *Server) AssetInquiries(c *fiber.Ctx) error {
+	assetId := c.Locals("asset_id").(int)
+
+	return server.listInquiryThreads(c, sql.NullInt64{Int64: int64(assetId), Valid: true})
+}
+
+// MyInquiryInbox summarises a seller's inquiries per listing.
+func (server *Server) MyInquiryInbox(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	inbox, err := server.store.GetInquiryInboxByAsset(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get inbox.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"assets": inbox,
+	})
+}
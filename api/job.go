@@ -0,0 +1,178 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/jobs"
+	"github.com/sangketkit01/real-estate-backend/worker"
+)
+
+type JobResponse struct {
+	ID          int64      `json:"id"`
+	Queue       string     `json:"queue"`
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"`
+	Attempts    int32      `json:"attempts"`
+	MaxAttempts int32      `json:"max_attempts"`
+	RunAt       time.Time  `json:"run_at"`
+	LockedBy    *string    `json:"locked_by"`
+	LastError   *string    `json:"last_error"`
+	UniqueKey   *string    `json:"unique_key"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newJobResponse(job db.Job) JobResponse {
+	return JobResponse{
+		ID:          job.ID,
+		Queue:       job.Queue,
+		Kind:        job.Kind,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedBy:    nullString(job.LockedBy),
+		LastError:   nullString(job.LastError),
+		UniqueKey:   nullString(job.UniqueKey),
+		FinishedAt:  nullTime(job.FinishedAt),
+		CreatedAt:   job.CreatedAt,
+	}
+}
+
+// removeUploads deletes uploaded files in the background once the rows
+// pointing at them are gone.
+func (server *Server) removeUploads(c *fiber.Ctx, paths ...string) {
+	for _, path := range paths {
+		_, err := server.jobs.Enqueue(c.Context(), worker.JobDeleteUpload, worker.DeleteUploadPayload{Path: path}, jobs.OnQueue(worker.UploadsQueue))
+		if err != nil {
+			log.Printf("cannot enqueue removal of %s: %v", path, err)
+		}
+	}
+}
+
+func (server *Server) ListJobs(c *fiber.Ctx) error {
+	var status db.NullJobStatus
+	if s := c.Query("status"); s != "" {
+		switch db.JobStatus(s) {
+		case db.JobStatusPending, db.JobStatusRunning, db.JobStatusSucceeded, db.JobStatusDead:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+		}
+		status = db.NullJobStatus{JobStatus: db.JobStatus(s), Valid: true}
+	}
+
+	queue := sql.NullString{String: c.Query("queue"), Valid: c.Query("queue") != ""}
+	kind := sql.NullString{String: c.Query("kind"), Valid: c.Query("kind") != ""}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	list, err := server.store.ListJobs(c.Context(), db.ListJobsParams{
+		Queue:      queue,
+		Status:     status,
+		Kind:       kind,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get jobs.")
+	}
+
+	total, err := server.store.CountJobs(c.Context(), db.CountJobsParams{
+		Queue:  queue,
+		Status: status,
+		Kind:   kind,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count jobs.")
+	}
+
+	rsp := make([]JobResponse, 0, len(list))
+	for _, job := range list {
+		rsp = append(rsp, newJobResponse(job))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"jobs":  rsp,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+// GetJobStats counts the jobs of every queue by status.
+func (server *Server) GetJobStats(c *fiber.Ctx) error {
+	rows, err := server.store.GetJobStats(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get job stats.")
+	}
+
+	queues := map[string]map[string]int64{}
+	for _, row := range rows {
+		if queues[row.Queue] == nil {
+			queues[row.Queue] = map[string]int64{}
+		}
+		queues[row.Queue][string(row.Status)] = row.Count
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"queues": queues})
+}
+
+func (server *Server) getJobParam(c *fiber.Ctx) (db.Job, error) {
+	jobId, err := strconv.Atoi(c.Params("job_id"))
+	if err != nil {
+		return db.Job{}, fiber.NewError(fiber.StatusBadRequest, "invalid job_id.")
+	}
+
+	job, err := server.store.GetJob(c.Context(), int64(jobId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return job, fiber.NewError(fiber.StatusNotFound, "job not found.")
+		}
+
+		return job, fiber.NewError(fiber.StatusInternalServerError, "cannot get job.")
+	}
+
+	return job, nil
+}
+
+func (server *Server) GetJob(c *fiber.Ctx) error {
+	job, err := server.getJobParam(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"job":     newJobResponse(job),
+		"payload": job.Payload,
+	})
+}
+
+// RetryJob gives a dead job a fresh set of attempts.
+func (server *Server) RetryJob(c *fiber.Ctx) error {
+	job, err := server.getJobParam(c)
+	if err != nil {
+		return err
+	}
+
+	job, err = server.store.RetryDeadJob(c.Context(), job.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusConflict, "only dead jobs can be retried.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "retry job failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newJobResponse(job))
+}
@@ -0,0 +1,149 @@
+package api
+
+// thaiMessages translates the client errors handlers return, keyed by their
+// English message. Messages of the form "invalid <param>." are translated by
+// pattern and server errors get a generic message, see translateMessage.
+var thaiMessages = map[string]string{
+	// shared
+	"not found.":                         "ไม่พบข้อมูล",
+	"not authorized":                     "ไม่ได้รับอนุญาต",
+	"invalid request":                    "คำขอไม่ถูกต้อง",
+	"invalid credentials":                "ชื่อผู้ใช้หรือรหัสผ่านไม่ถูกต้อง",
+	"invalid JSON data.":                 "ข้อมูล JSON ไม่ถูกต้อง",
+	"invalid asset id.":                  "รหัสประกาศไม่ถูกต้อง",
+	"invalid Last-Event-ID.":             "Last-Event-ID ไม่ถูกต้อง",
+	"request body is too large.":         "ข้อมูลที่ส่งมามีขนาดใหญ่เกินไป",
+	"invalid user type":                  "ประเภทผู้ใช้ไม่ถูกต้อง",
+	"no data found, why are you here ?":  "ไม่พบข้อมูล",
+	"failed to read multipart form":      "ไม่สามารถอ่านข้อมูลฟอร์มได้",
+	"days must be between 1 and 365.":    "days ต้องอยู่ระหว่าง 1 ถึง 365",
+	"bucket must be day, week or month.": "bucket ต้องเป็น day, week หรือ month",
+	"from must be YYYY-MM-DD.":           "from ต้องอยู่ในรูปแบบ YYYY-MM-DD",
+	"to must be YYYY-MM-DD.":             "to ต้องอยู่ในรูปแบบ YYYY-MM-DD",
+	"since must be YYYY-MM-DD.":          "since ต้องอยู่ในรูปแบบ YYYY-MM-DD",
+	"from must not be after to.":         "from ต้องไม่อยู่หลัง to",
+	"format must be csv or json.":        "format ต้องเป็น csv หรือ json",
+
+	// users
+	"Username or email already exist": "ชื่อผู้ใช้หรืออีเมลนี้ถูกใช้แล้ว",
+	"user not found.":                 "ไม่พบผู้ใช้",
+	"no users found":                  "ไม่พบผู้ใช้",
+	"username is not provided.":       "กรุณาระบุชื่อผู้ใช้",
+	"wrong password.":                 "รหัสผ่านไม่ถูกต้อง",
+
+	// listings
+	"asset not found.":                               "ไม่พบประกาศ",
+	"no asset found.":                                "ไม่พบประกาศ",
+	"asset is already sold.":                         "ประกาศนี้ขายไปแล้ว",
+	"sold assets cannot be renewed.":                 "ประกาศที่ขายแล้วไม่สามารถต่ออายุได้",
+	"sold assets cannot be promoted.":                "ประกาศที่ขายแล้วไม่สามารถโปรโมตได้",
+	"image not found.":                               "ไม่พบรูปภาพ",
+	"no contact found.":                              "ไม่พบข้อมูลติดต่อ",
+	"price must not be negative.":                    "ราคาต้องไม่ติดลบ",
+	"min_price must not be greater than max_price.":  "min_price ต้องไม่มากกว่า max_price",
+	"reduced_within must be between 1 and 365 days.": "reduced_within ต้องอยู่ระหว่าง 1 ถึง 365 วัน",
+	"sort must be newest or recently_reduced.":       "sort ต้องเป็น newest หรือ recently_reduced",
+	"translation not found.":                         "ไม่พบคำแปล",
+	"sitemap not found.":                             "ไม่พบแผนผังเว็บไซต์",
+
+	// agencies
+	"agency not found.":                              "ไม่พบเอเจนซี่",
+	"member not found.":                              "ไม่พบสมาชิก",
+	"agency must keep at least one admin.":           "เอเจนซี่ต้องมีผู้ดูแลอย่างน้อยหนึ่งคน",
+	"agent is not a member of your agency.":          "ตัวแทนคนนี้ไม่ได้เป็นสมาชิกของเอเจนซี่ของคุณ",
+	"asset already belongs to another agency.":       "ประกาศนี้อยู่ในเอเจนซี่อื่นแล้ว",
+	"asset does not belong to an agency.":            "ประกาศนี้ไม่ได้อยู่ในเอเจนซี่ใด",
+	"license number is already registered.":          "เลขใบอนุญาตนี้ถูกลงทะเบียนแล้ว",
+	"only agency admins can assign other agents.":    "เฉพาะผู้ดูแลเอเจนซี่เท่านั้นที่มอบหมายตัวแทนคนอื่นได้",
+	"only agency admins can do this.":                "เฉพาะผู้ดูแลเอเจนซี่เท่านั้นที่ทำรายการนี้ได้",
+	"only the owner or an agency admin can do this.": "เฉพาะเจ้าของหรือผู้ดูแลเอเจนซี่เท่านั้นที่ทำรายการนี้ได้",
+	"user is already a member of an agency.":         "ผู้ใช้นี้เป็นสมาชิกของเอเจนซี่อยู่แล้ว",
+	"you are already a member of an agency.":         "คุณเป็นสมาชิกของเอเจนซี่อยู่แล้ว",
+	"user is already invited to your agency.":        "ผู้ใช้นี้ได้รับคำเชิญเข้าร่วมเอเจนซี่ของคุณแล้ว",
+	"invite not found.":                              "ไม่พบคำเชิญ",
+	"you are not a member of any agency.":            "คุณยังไม่ได้เป็นสมาชิกของเอเจนซี่ใด",
+
+	// bulk imports
+	"file is required.":          "กรุณาแนบไฟล์",
+	"file has no listings.":      "ไม่พบประกาศในไฟล์",
+	"cannot read file.":          "ไม่สามารถอ่านไฟล์ได้",
+	"cannot read images.":        "ไม่สามารถอ่านไฟล์รูปภาพได้",
+	"images must be a zip file.": "ไฟล์รูปภาพต้องเป็นไฟล์ zip",
+	"import not found.":          "ไม่พบรายการนำเข้า",
+	"mapping must be a JSON object of field to column name.": "mapping ต้องเป็น JSON object ที่จับคู่ฟิลด์กับชื่อคอลัมน์",
+
+	// inquiries, offers and viewings
+	"inquiry not found.":                                              "ไม่พบข้อความสอบถาม",
+	"cannot send an inquiry about your own asset.":                    "ไม่สามารถส่งข้อความสอบถามประกาศของตัวเองได้",
+	"offer not found.":                                                "ไม่พบข้อเสนอ",
+	"offer has expired.":                                              "ข้อเสนอนี้หมดอายุแล้ว",
+	"offer has changed, reload and try again.":                        "ข้อเสนอมีการเปลี่ยนแปลง กรุณาโหลดใหม่แล้วลองอีกครั้ง",
+	"cannot make an offer on your own asset.":                         "ไม่สามารถยื่นข้อเสนอให้ประกาศของตัวเองได้",
+	"asset is sold or already under offer.":                           "ประกาศนี้ขายแล้วหรือมีข้อเสนอที่ตกลงกันแล้ว",
+	"you already have an open offer on this asset.":                   "คุณมีข้อเสนอที่ยังเปิดอยู่สำหรับประกาศนี้แล้ว",
+	"only the buyer can withdraw an offer.":                           "เฉพาะผู้ซื้อเท่านั้นที่ถอนข้อเสนอได้",
+	"only an accepted offer can be cancelled.":                        "ยกเลิกได้เฉพาะข้อเสนอที่ตกลงกันแล้ว",
+	"waiting for the other party to respond.":                         "กำลังรอการตอบกลับจากอีกฝ่าย",
+	"viewing not found.":                                              "ไม่พบการนัดชม",
+	"viewing slot not found.":                                         "ไม่พบช่วงเวลานัดชม",
+	"viewing is cancelled.":                                           "การนัดชมนี้ถูกยกเลิกแล้ว",
+	"viewing is already cancelled.":                                   "การนัดชมนี้ถูกยกเลิกไปแล้ว",
+	"viewing slot belongs to another asset.":                          "ช่วงเวลานัดชมนี้เป็นของประกาศอื่น",
+	"viewing slot has already started.":                               "ช่วงเวลานัดชมนี้เริ่มไปแล้ว",
+	"viewing slot is booked, cancel the viewing first.":               "ช่วงเวลานี้มีการจองแล้ว กรุณายกเลิกการนัดชมก่อน",
+	"viewing slots must start in the future.":                         "ช่วงเวลานัดชมต้องเป็นเวลาในอนาคต",
+	"a viewing slot already starts at that time.":                     "มีช่วงเวลานัดชมที่เริ่มเวลานี้อยู่แล้ว",
+	"viewing slots must not overlap.":                                 "ช่วงเวลานัดชมต้องไม่ทับซ้อนกัน",
+	"cannot book a viewing of your own asset.":                        "ไม่สามารถนัดชมประกาศของตัวเองได้",
+	"only the buyer can reschedule a viewing.":                        "เฉพาะผู้ซื้อเท่านั้นที่เลื่อนนัดชมได้",
+	"this slot is already taken.":                                     "ช่วงเวลานี้ถูกจองแล้ว",
+	"this slot is taken or you already have a viewing of this asset.": "ช่วงเวลานี้ถูกจองแล้ว หรือคุณมีนัดชมประกาศนี้อยู่แล้ว",
+
+	// reviews and reports
+	"review not found.":                   "ไม่พบรีวิว",
+	"you cannot review yourself.":         "ไม่สามารถรีวิวตัวเองได้",
+	"you already reviewed this seller.":   "คุณรีวิวผู้ขายคนนี้ไปแล้ว",
+	"you already replied to this review.": "คุณตอบกลับรีวิวนี้ไปแล้ว",
+	"you can review a seller after they answered your inquiry or you attended a viewing.": "คุณรีวิวผู้ขายได้หลังจากผู้ขายตอบข้อความสอบถามหรือคุณได้เข้าชมทรัพย์แล้ว",
+	"report not found.":                                  "ไม่พบรายงาน",
+	"report is already closed.":                          "รายงานนี้ถูกปิดแล้ว",
+	"reports can only be assigned to admins.":            "มอบหมายรายงานได้เฉพาะผู้ดูแลระบบเท่านั้น",
+	"assignee not found.":                                "ไม่พบผู้รับผิดชอบ",
+	"you cannot report your own asset.":                  "ไม่สามารถรายงานประกาศของตัวเองได้",
+	"you cannot report yourself.":                        "ไม่สามารถรายงานตัวเองได้",
+	"you already reported this, we are looking into it.": "คุณรายงานเรื่องนี้แล้ว เรากำลังตรวจสอบ",
+
+	// saved searches and notifications
+	"saved search not found.":                             "ไม่พบการค้นหาที่บันทึกไว้",
+	"email notifications are not available.":              "ยังไม่เปิดให้ใช้การแจ้งเตือนทางอีเมล",
+	"webhook_url must be a public http or https address.": "webhook_url ต้องเป็นที่อยู่ http หรือ https สาธารณะ",
+
+	// promotions and payments
+	"active promotion not found.":                        "ไม่พบโปรโมชันที่ใช้งานอยู่",
+	"ends_at must be in the future and after starts_at.": "ends_at ต้องเป็นเวลาในอนาคตและอยู่หลัง starts_at",
+	"expires_at must be in the next 90 days.":            "expires_at ต้องอยู่ภายใน 90 วันข้างหน้า",
+	"order not found.":                                   "ไม่พบคำสั่งซื้อ",
+	"unknown product.":                                   "ไม่พบสินค้า",
+	"only paid orders can be refunded.":                  "คืนเงินได้เฉพาะคำสั่งซื้อที่ชำระเงินแล้วเท่านั้น",
+	"paid amount does not match the order.":              "ยอดที่ชำระไม่ตรงกับคำสั่งซื้อ",
+
+	// currencies
+	"unknown currency.":                        "ไม่รองรับสกุลเงินนี้",
+	"exchange rate not found.":                 "ไม่พบอัตราแลกเปลี่ยน",
+	"currency is used by listings.":            "สกุลเงินนี้ยังถูกใช้ในประกาศอยู่",
+	"minor_units of a currency cannot change.": "ไม่สามารถเปลี่ยนจำนวนทศนิยมของสกุลเงินได้",
+	"the base currency cannot be removed.":     "ไม่สามารถลบสกุลเงินหลักได้",
+
+	// webhooks and jobs
+	"webhook not found.":                          "ไม่พบเว็บฮุก",
+	"url must be a public http or https address.": "url ต้องเป็นที่อยู่ http หรือ https สาธารณะ",
+	"delivery not found.":                         "ไม่พบรายการส่ง",
+	"delivery is still pending.":                  "รายการส่งนี้ยังรอดำเนินการ",
+	"webhook is not active.":                      "เว็บฮุกนี้ถูกปิดใช้งานอยู่",
+	"job not found.":                              "ไม่พบงาน",
+	"only dead jobs can be retried.":              "ลองใหม่ได้เฉพาะงานที่ล้มเหลวถาวรเท่านั้น",
+	"outbox event not found.":                     "ไม่พบอีเวนต์",
+	"only dead events can be retried.":            "ลองใหม่ได้เฉพาะอีเวนต์ที่ล้มเหลวถาวรเท่านั้น",
+
+	serverErrorKey: "เกิดข้อผิดพลาดในระบบ กรุณาลองใหม่อีกครั้ง",
+}
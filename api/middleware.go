@@ -9,6 +9,34 @@ import (
 	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
 )
 
+const (
+	defaultBodyLimit = fiber.DefaultBodyLimit
+	// bulk imports upload a sheet together with a zip of images
+	importBodyLimit = 64 * 1024 * 1024
+)
+
+// bodyLimits raises the body limit of single routes above the default.
+var bodyLimits = map[string]int{
+	fiber.MethodPost + " /my-agency/imports": importBodyLimit,
+}
+
+// BodyLimitMiddleware rejects bodies over the limit of the route. The server
+// reads bodies up to the largest limit before routing, so this is what keeps
+// the other routes at the default.
+func (server *Server) BodyLimitMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit, ok := bodyLimits[c.Method()+" "+c.Path()]
+		if !ok {
+			limit = defaultBodyLimit
+		}
+
+		if len(c.Request().Body()) > limit {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, "request body is too large.")
+		}
+		return c.Next()
+	}
+}
+
 func (server *Server) AuthMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		tokenCookie := c.Cookies("token", "invalid token")
@@ -31,7 +59,49 @@ func (server *Server) AuthMiddleware() fiber.Handler {
 	}
 }
 
+// OptionalAuthMiddleware loads the user the same way AuthMiddleware does when
+// a valid token cookie is present, but lets anonymous visitors through.
+func (server *Server) OptionalAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenCookie := c.Cookies("token")
+		if tokenCookie == "" {
+			return c.Next()
+		}
+
+		payload, err := server.tokenMaker.VerifyToken(tokenCookie)
+		if err != nil {
+			return c.Next()
+		}
+
+		userData, err := server.store.GetUser(c.Context(), payload.Username)
+		if err != nil {
+			return c.Next()
+		}
+
+		c.Locals("user", userData)
+		return c.Next()
+	}
+}
+
+// currentUser returns the logged-in user, if any.
+func currentUser(c *fiber.Ctx) (db.User, bool) {
+	user, ok := c.Locals("user").(db.User)
+	return user, ok
+}
+
+// AssetMiddleware lets only the owner of the listing through.
 func (server *Server) AssetMiddleware() fiber.Handler {
+	return server.assetMiddleware(false)
+}
+
+// AssetEditorMiddleware also lets the listing's agent and the admins of its
+// agency through, for routes that edit the listing but do not delete it or
+// change which agency it belongs to.
+func (server *Server) AssetEditorMiddleware() fiber.Handler {
+	return server.assetMiddleware(true)
+}
+
+func (server *Server) assetMiddleware(agencyCanEdit bool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		log.Println(c.AllParams(), c.Route(), c.Path())
 		assetIdParam := c.Params("asset_id")
@@ -60,10 +130,26 @@ func (server *Server) AssetMiddleware() fiber.Handler {
 		}
 
 		if asset.Owner != user.Username {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not the owner, could not edit other's asset."})
+			if !agencyCanEdit {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not the owner, could not edit other's asset."})
+			}
+
+			// agency admins and the assigned agent manage the listing too
+			canManage, err := server.store.CanManageAsset(c.Context(), db.CanManageAssetParams{
+				Username: user.Username,
+				AssetID:  asset.ID,
+			})
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+			}
+
+			if !canManage {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not the owner, could not edit other's asset."})
+			}
 		}
 
 		c.Locals("asset_id", assetId)
+		c.Locals("asset", asset)
 		return c.Next()
 	}
 }
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+const (
+	reasonTrustedSeller = "auto-approved: trusted seller"
+	reasonSubmitted     = "submitted for review"
+	// reasonReported starts the reason of listings hidden by reports
+	reasonReported = "hidden pending review"
+)
+
+// newListingStatus decides whether a brand new listing goes live immediately.
+func (server *Server) newListingStatus(user db.User) (db.ModerationStatus, string) {
+	if server.config.AutoApproveTrustedSellers && user.IsTrusted {
+		return db.ModerationStatusApproved, reasonTrustedSeller
+	}
+
+	return db.ModerationStatusPending, reasonSubmitted
+}
+
+// reviewEdit sends an edited listing back to the moderation queue unless one of
+// the auto-approval rules lets it stay live. Rejected listings and listings
+// hidden by reports keep their status, only a moderator takes them out of it.
+func (server *Server) reviewEdit(ctx context.Context, user db.User, asset db.GetAssetByIdRow, priceOrImageChanged bool) error {
+	if asset.ModerationStatus == db.ModerationStatusRejected || hiddenByReports(asset) {
+		return nil
+	}
+
+	if asset.ModerationStatus == db.ModerationStatusApproved && !priceOrImageChanged && server.config.AutoApproveMinorEdits {
+		return nil
+	}
+
+	status, reason := server.newListingStatus(user)
+	if status == asset.ModerationStatus {
+		return nil
+	}
+
+	_, err := server.store.ModerateAssetTx(ctx, db.ModerateAssetTxParams{
+		AssetID: asset.ID,
+		Status:  status,
+		Reason:  sql.NullString{String: reason, Valid: true},
+	})
+	return err
+}
+
+func hiddenByReports(asset db.GetAssetByIdRow) bool {
+	return asset.ModerationStatus == db.ModerationStatusPending &&
+		strings.HasPrefix(asset.ModerationReason.String, reasonReported)
+}
+
+// reviewAssetEdit runs reviewEdit for handlers behind AssetMiddleware.
+func (server *Server) reviewAssetEdit(c *fiber.Ctx, priceOrImageChanged bool) error {
+	user := c.Locals("user").(db.User)
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	if err := server.reviewEdit(c.Context(), user, asset, priceOrImageChanged); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot submit asset for review.")
+	}
+
+	return nil
+}
+
+type ModerationItemResponse struct {
+	AssetID             int64     `json:"asset_id"`
+	Owner               string    `json:"owner"`
+	Price               int64     `json:"price"`
+	Detail              string    `json:"detail"`
+	ModerationStatus    string    `json:"moderation_status"`
+	ModerationReason    *string   `json:"moderation_reason"`
+	ModeratedBy         *string   `json:"moderated_by"`
+	ModerationUpdatedAt time.Time `json:"moderation_updated_at"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type ModerationLogResponse struct {
+	ID        int64     `json:"id"`
+	Moderator *string   `json:"moderator"`
+	Status    string    `json:"status"`
+	Reason    *string   `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newModerationItemResponse(asset db.Asset) ModerationItemResponse {
+	return ModerationItemResponse{
+		AssetID:             asset.ID,
+		Owner:               asset.Owner,
+		Price:               asset.Price,
+		Detail:              asset.Detail,
+		ModerationStatus:    string(asset.ModerationStatus),
+		ModerationReason:    nullString(asset.ModerationReason),
+		ModeratedBy:         nullString(asset.ModeratedBy),
+		ModerationUpdatedAt: asset.ModerationUpdatedAt,
+		CreatedAt:           asset.CreatedAt,
+	}
+}
+
+func newModerationLogResponses(logs []db.AssetModerationLog) []ModerationLogResponse {
+	rsp := make([]ModerationLogResponse, 0, len(logs))
+	for _, log := range logs {
+		rsp = append(rsp, ModerationLogResponse{
+			ID:        log.ID,
+			Moderator: nullString(log.Moderator),
+			Status:    string(log.Status),
+			Reason:    nullString(log.Reason),
+			CreatedAt: log.CreatedAt,
+		})
+	}
+	return rsp
+}
+
+func (server *Server) GetModerationQueue(c *fiber.Ctx) error {
+	status := db.ModerationStatus(c.Query("status", string(db.ModerationStatusPending)))
+	switch status {
+	case db.ModerationStatusPending, db.ModerationStatusApproved, db.ModerationStatusRejected:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+	}
+
+	owner := strings.TrimSpace(c.Query("owner"))
+
+	var submittedAfter sql.NullTime
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "since must be YYYY-MM-DD.")
+		}
+		submittedAfter = sql.NullTime{Time: t, Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	assets, err := server.store.GetModerationQueue(c.Context(), db.GetModerationQueueParams{
+		ModerationStatus: status,
+		Owner:            sql.NullString{String: owner, Valid: owner != ""},
+		SubmittedAfter:   submittedAfter,
+		PageLimit:        int32(limit),
+		PageOffset:       int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get moderation queue.")
+	}
+
+	total, err := server.store.CountModerationQueue(c.Context(), db.CountModerationQueueParams{
+		ModerationStatus: status,
+		Owner:            sql.NullString{String: owner, Valid: owner != ""},
+		SubmittedAfter:   submittedAfter,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count moderation queue.")
+	}
+
+	items := make([]ModerationItemResponse, 0, len(assets))
+	for _, asset := range assets {
+		items = append(items, newModerationItemResponse(asset))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"items": items,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	})
+}
+
+func (server *Server) GetModerationItem(c *fiber.Ctx) error {
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	logs, err := server.store.GetModerationLogs(c.Context(), int64(assetId))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get moderation history.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"asset":   newAssetResponse(asset),
+		"history": newModerationLogResponses(logs),
+	})
+}
+
+type ModerationDecisionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type RejectAssetRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+func (server *Server) ApproveAsset(c *fiber.Ctx) error {
+	var req ModerationDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	return server.moderateAsset(c, db.ModerationStatusApproved, req.Reason)
+}
+
+func (server *Server) RejectAsset(c *fiber.Ctx) error {
+	var req RejectAssetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	return server.moderateAsset(c, db.ModerationStatusRejected, req.Reason)
+}
+
+func (server *Server) moderateAsset(c *fiber.Ctx, status db.ModerationStatus, reason string) error {
+	moderator := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	reason = strings.TrimSpace(reason)
+	_, err = server.store.ModerateAssetTx(c.Context(), db.ModerateAssetTxParams{
+		AssetID:   int64(assetId),
+		Status:    status,
+		Reason:    sql.NullString{String: reason, Valid: reason != ""},
+		Moderator: sql.NullString{String: moderator.Username, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot moderate asset.")
+	}
+
+	body := fmt.Sprintf("Your listing #%d was %s.", asset.ID, status)
+	if reason != "" {
+		body += " Reason: " + reason
+	}
+	server.notifyUser(c.Context(), asset.Owner, notify.Notification{
+		Kind:  "asset_moderated",
+		Title: "Listing " + string(status),
+		Body:  body,
+		Data:  map[string]any{"asset_id": asset.ID, "status": status, "reason": reason},
+	})
+
+	return okResponse(c, string(status)+" asset successfully.")
+}
+
+type SetUserTrustedRequest struct {
+	Trusted bool `json:"trusted"`
+}
+
+func (server *Server) SetUserTrusted(c *fiber.Ctx) error {
+	username := c.Params("username")
+
+	var req SetUserTrustedRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if _, err := server.store.GetUser(c.Context(), username); err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "user not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get user.")
+	}
+
+	err := server.store.SetUserTrusted(c.Context(), db.SetUserTrustedParams{
+		Username:  username,
+		IsTrusted: req.Trusted,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot update user.")
+	}
+
+	return okResponse(c, "update user successfully.")
+}
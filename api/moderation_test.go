@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestReviewEdit(t *testing.T) {
+	trusted := testUser(db.UserRoleUser)
+	trusted.IsTrusted = true
+
+	tests := []struct {
+		name   string
+		status db.ModerationStatus
+		reason string
+		want   db.ModerationStatus // empty when the status must not change
+	}{
+		{"pending", db.ModerationStatusPending, reasonSubmitted, db.ModerationStatusApproved},
+		{"approved", db.ModerationStatusApproved, reasonTrustedSeller, ""},
+		{"rejected", db.ModerationStatusRejected, "blurry photos", ""},
+		{"hidden by reports", db.ModerationStatusPending, reasonReported + ": reported by 3 users", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, fake := newTestServer(t, util.Config{AutoApproveTrustedSellers: true})
+
+			var got db.ModerationStatus
+			fake.Handle("UpdateAssetModeration", func(args []driver.Value) (dbtest.Result, error) {
+				got = db.ModerationStatus(args[1].(string))
+				return dbtest.Result{RowsAffected: 0}, nil
+			})
+			fake.Handle("InsertModerationLog", func(args []driver.Value) (dbtest.Result, error) {
+				return dbtest.Row(int64(1), args[0], args[1], args[2], args[3], time.Now()), nil
+			})
+
+			asset := db.GetAssetByIdRow{
+				ID:               1,
+				Owner:            trusted.Username,
+				ModerationStatus: tc.status,
+				ModerationReason: sql.NullString{String: tc.reason, Valid: true},
+			}
+			if err := server.reviewEdit(context.Background(), trusted, asset, true); err != nil {
+				t.Fatalf("reviewEdit: %v", err)
+			}
+
+			if got != tc.want {
+				t.Errorf("status set to %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCloseReportClosedMeanwhile(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	cookie := login(t, server, fake, testUser(db.UserRoleAdmin))
+
+	now := time.Now()
+	fake.Handle("GetReport", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(int64(7), "somchai", "asset", int64(1), nil, "spam", "", "open", nil, nil, now, now), nil
+	})
+	// another moderator dismissed it between the read and the update
+	fake.Handle("CloseReport", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{RowsAffected: 0}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/reports/7/resolve", nil)
+	req.AddCookie(cookie)
+	do(t, server, req, http.StatusConflict, nil)
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+const (
+	streamKeepAlive = 30 * time.Second
+	// streamReplayLimit caps how many missed notifications are resent when a
+	// client reconnects with Last-Event-ID.
+	streamReplayLimit = 100
+)
+
+type NotificationResponse struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newNotificationResponse(notification db.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        notification.ID,
+		Kind:      notification.Kind,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		ReadAt:    nullTime(notification.ReadAt),
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func newNotificationResponses(notifications []db.Notification) []NotificationResponse {
+	rsp := make([]NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		rsp = append(rsp, newNotificationResponse(notification))
+	}
+	return rsp
+}
+
+// notifyUser stores an in-app notification, which also pushes it to the user's
+// live streams. Failures are logged so they never fail the request that caused them.
+func (server *Server) notifyUser(ctx context.Context, username string, notification notify.Notification) {
+	err := server.notifier.Notify(ctx, notify.Recipient{Username: username}, notification)
+	if err != nil {
+		log.Printf("cannot notify %s about %s: %v", username, notification.Kind, err)
+	}
+}
+
+func (server *Server) ListNotifications(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	unreadOnly := c.QueryBool("unread", false)
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	notifications, err := server.store.ListNotifications(c.Context(), db.ListNotificationsParams{
+		Username:   user.Username,
+		UnreadOnly: unreadOnly,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get notifications.")
+	}
+
+	total, err := server.store.CountNotifications(c.Context(), db.CountNotificationsParams{
+		Username:   user.Username,
+		UnreadOnly: unreadOnly,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count notifications.")
+	}
+
+	unread, err := server.store.CountUnreadNotifications(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count notifications.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"notifications": newNotificationResponses(notifications),
+		"unread":        unread,
+		"page":          page,
+		"limit":         limit,
+		"total":         total,
+	})
+}
+
+func (server *Server) MarkNotificationRead(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	notificationId, err := strconv.Atoi(c.Params("notification_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid notification_id.")
+	}
+
+	_, err = server.store.MarkNotificationRead(c.Context(), db.MarkNotificationReadParams{
+		ID:       int64(notificationId),
+		Username: user.Username,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "mark notification read failed.")
+	}
+
+	return okResponse(c, "mark notification read successfully.")
+}
+
+func (server *Server) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	marked, err := server.store.MarkAllNotificationsRead(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "mark notifications read failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"marked": marked,
+	})
+}
+
+// StreamNotifications pushes new notifications as Server-Sent Events. Clients
+// that reconnect with Last-Event-ID get what they missed first.
+func (server *Server) StreamNotifications(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	var missed []db.Notification
+	if lastId := c.Get("Last-Event-ID"); lastId != "" {
+		id, err := strconv.ParseInt(lastId, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid Last-Event-ID.")
+		}
+
+		missed, err = server.store.GetNotificationsAfter(c.Context(), db.GetNotificationsAfterParams{
+			Username: user.Username,
+			ID:       id,
+			Limit:    streamReplayLimit,
+		})
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "cannot get notifications.")
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	events, unsubscribe := server.hub.Subscribe(user.Username)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		fmt.Fprint(w, "retry: 5000\n\n")
+		for _, notification := range missed {
+			writeNotificationEvent(w, notification)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case notification := <-events:
+				writeNotificationEvent(w, notification)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+
+			// the client is gone once a flush fails
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func writeNotificationEvent(w *bufio.Writer, notification db.Notification) {
+	data, err := sonic.Marshal(newNotificationResponse(notification))
+	if err != nil {
+		log.Println("cannot encode notification:", err)
+		return
+	}
+
+	fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+}
@@ -0,0 +1,499 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+const maxOfferDuration = 90 * 24 * time.Hour
+
+// OfferRequest is an offer or a counter offer. Amount is in minor units of
+// the listing's currency, like its price.
+type OfferRequest struct {
+	Amount     int64     `json:"amount" validate:"required,gt=0"`
+	Conditions string    `json:"conditions" validate:"max=2000"`
+	ExpiresAt  time.Time `json:"expires_at" validate:"required"`
+}
+
+type OfferDecisionRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+func parseOfferRequest(c *fiber.Ctx) (OfferRequest, error) {
+	var req OfferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return req, err
+	}
+
+	now := time.Now()
+	if !req.ExpiresAt.After(now) || req.ExpiresAt.After(now.Add(maxOfferDuration)) {
+		return req, fiber.NewError(fiber.StatusBadRequest, "expires_at must be in the next 90 days.")
+	}
+
+	req.Conditions = strings.TrimSpace(req.Conditions)
+	return req, nil
+}
+
+func parseOfferDecision(c *fiber.Ctx) (string, error) {
+	var req OfferDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return "", fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(req.Note), nil
+}
+
+// offerTurn is who has to answer an open offer: the seller while it is
+// pending, the buyer once the seller has countered.
+func offerTurn(offer db.Offer) string {
+	if offer.Status == db.OfferStatusCountered {
+		return offer.Buyer
+	}
+	return offer.Seller
+}
+
+func offerCounterparty(offer db.Offer, username string) string {
+	if username == offer.Buyer {
+		return offer.Seller
+	}
+	return offer.Buyer
+}
+
+func offerTxError(err error) error {
+	switch err {
+	case db.ErrOfferNotOpen:
+		return fiber.NewError(fiber.StatusConflict, "offer has changed, reload and try again.")
+	case db.ErrAssetUnavailable:
+		return fiber.NewError(fiber.StatusConflict, "asset is sold or already under offer.")
+	case db.ErrAssetSold:
+		return fiber.NewError(fiber.StatusConflict, "asset is already sold.")
+	}
+
+	if pqErr, ok := err.(*pq.Error); ok {
+		if pqErr.Code == "23505" {
+			return fiber.NewError(fiber.StatusConflict, "you already have an open offer on this asset.")
+		}
+	}
+
+	return fiber.NewError(fiber.StatusInternalServerError, "update offer failed.")
+}
+
+func (server *Server) notifyOffer(ctx context.Context, username, kind, title string, offer db.Offer) {
+	server.notifyUser(ctx, username, notify.Notification{
+		Kind:  kind,
+		Title: title,
+		Body:  fmt.Sprintf("Offer of %s on listing #%d, valid until %s.", server.formatAssetPrice(ctx, offer.AssetID, offer.Amount), offer.AssetID, offer.ExpiresAt.Format(dateLayout)),
+		Data: map[string]any{
+			"offer_id": offer.ID,
+			"asset_id": offer.AssetID,
+			"amount":   offer.Amount,
+			"status":   offer.Status,
+		},
+	})
+}
+
+// getOffer loads :offer_id for its buyer or seller.
+func (server *Server) getOffer(c *fiber.Ctx) (db.Offer, error) {
+	user := c.Locals("user").(db.User)
+
+	offerId, err := strconv.Atoi(c.Params("offer_id"))
+	if err != nil {
+		return db.Offer{}, fiber.NewError(fiber.StatusBadRequest, "invalid offer_id.")
+	}
+
+	offer, err := server.store.GetOffer(c.Context(), int64(offerId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return offer, fiber.NewError(fiber.StatusNotFound, "offer not found.")
+		}
+
+		return offer, fiber.NewError(fiber.StatusInternalServerError, "cannot get offer.")
+	}
+
+	if offer.Buyer != user.Username && offer.Seller != user.Username {
+		return offer, fiber.NewError(fiber.StatusNotFound, "offer not found.")
+	}
+
+	return offer, nil
+}
+
+// getOpenOffer loads :offer_id and makes sure it can still be answered,
+// marking it expired when its time has run out.
+func (server *Server) getOpenOffer(c *fiber.Ctx) (db.Offer, error) {
+	offer, err := server.getOffer(c)
+	if err != nil {
+		return offer, err
+	}
+
+	if offer.Status != db.OfferStatusPending && offer.Status != db.OfferStatusCountered {
+		return offer, fiber.NewError(fiber.StatusBadRequest, "offer is already "+string(offer.Status)+".")
+	}
+
+	if !offer.ExpiresAt.After(time.Now()) {
+		_, err := server.store.UpdateOfferTx(c.Context(), db.UpdateOfferTxParams{
+			OfferID:        offer.ID,
+			Actor:          "system",
+			ExpectedStatus: offer.Status,
+			Action:         db.OfferActionExpired,
+			Status:         db.OfferStatusExpired,
+		})
+		if err != nil && err != db.ErrOfferNotOpen {
+			return offer, fiber.NewError(fiber.StatusInternalServerError, "cannot expire offer.")
+		}
+
+		return offer, fiber.NewError(fiber.StatusBadRequest, "offer has expired.")
+	}
+
+	return offer, nil
+}
+
+// getOfferForTurn is getOpenOffer for the party whose turn it is.
+func (server *Server) getOfferForTurn(c *fiber.Ctx) (db.Offer, error) {
+	user := c.Locals("user").(db.User)
+
+	offer, err := server.getOpenOffer(c)
+	if err != nil {
+		return offer, err
+	}
+
+	if offerTurn(offer) != user.Username {
+		return offer, fiber.NewError(fiber.StatusForbidden, "waiting for the other party to respond.")
+	}
+
+	return offer, nil
+}
+
+func (server *Server) SubmitOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	req, err := parseOfferRequest(c)
+	if err != nil {
+		return err
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	if asset.Owner == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "cannot make an offer on your own asset.")
+	}
+
+	offer, err := server.store.CreateOfferTx(c.Context(), db.CreateOfferParams{
+		AssetID:    asset.ID,
+		Buyer:      user.Username,
+		Seller:     asset.Owner,
+		Amount:     req.Amount,
+		Conditions: req.Conditions,
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offer.Seller, "offer_submitted", user.Username+" made an offer", offer)
+
+	return c.Status(fiber.StatusCreated).JSON(offer)
+}
+
+func (server *Server) GetOffer(c *fiber.Ctx) error {
+	offer, err := server.getOffer(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := server.store.GetOfferEvents(c.Context(), offer.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get offer history.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"offer":   offer,
+		"history": events,
+	})
+}
+
+func (server *Server) MyOffers(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	offers, err := server.store.ListOffersByBuyer(c.Context(), db.ListOffersByBuyerParams{
+		Buyer:  user.Username,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get offers.")
+	}
+
+	total, err := server.store.CountOffersByBuyer(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count offers.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"offers": offers,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+// AssetOffers lists the offers received on one of the seller's assets.
+func (server *Server) AssetOffers(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	var status db.NullOfferStatus
+	if s := c.Query("status"); s != "" {
+		switch db.OfferStatus(s) {
+		case db.OfferStatusPending, db.OfferStatusCountered, db.OfferStatusAccepted, db.OfferStatusRejected,
+			db.OfferStatusDeclined, db.OfferStatusWithdrawn, db.OfferStatusExpired, db.OfferStatusCancelled:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+		}
+		status = db.NullOfferStatus{OfferStatus: db.OfferStatus(s), Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	offers, err := server.store.ListOffersByAsset(c.Context(), db.ListOffersByAssetParams{
+		AssetID:    asset.ID,
+		Status:     status,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get offers.")
+	}
+
+	total, err := server.store.CountOffersByAsset(c.Context(), db.CountOffersByAssetParams{
+		AssetID: asset.ID,
+		Status:  status,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count offers.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"offers": offers,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+func (server *Server) AcceptOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	note, err := parseOfferDecision(c)
+	if err != nil {
+		return err
+	}
+
+	offer, err := server.getOfferForTurn(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := server.store.AcceptOfferTx(c.Context(), db.AcceptOfferTxParams{
+		OfferID:        offer.ID,
+		Actor:          user.Username,
+		ExpectedStatus: offer.Status,
+		Note:           note,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offerCounterparty(offer, user.Username), "offer_accepted", user.Username+" accepted the offer", result.Offer)
+	for _, declined := range result.Declined {
+		server.notifyOffer(c.Context(), declined.Buyer, "offer_declined", "Your offer was declined", declined)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"offer":    result.Offer,
+		"declined": len(result.Declined),
+	})
+}
+
+func (server *Server) RejectOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	note, err := parseOfferDecision(c)
+	if err != nil {
+		return err
+	}
+
+	offer, err := server.getOfferForTurn(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := server.store.UpdateOfferTx(c.Context(), db.UpdateOfferTxParams{
+		OfferID:        offer.ID,
+		Actor:          user.Username,
+		ExpectedStatus: offer.Status,
+		Action:         db.OfferActionRejected,
+		Status:         db.OfferStatusRejected,
+		Note:           note,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offerCounterparty(offer, user.Username), "offer_rejected", user.Username+" rejected the offer", updated)
+
+	return c.Status(fiber.StatusOK).JSON(updated)
+}
+
+func (server *Server) CounterOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	req, err := parseOfferRequest(c)
+	if err != nil {
+		return err
+	}
+
+	offer, err := server.getOfferForTurn(c)
+	if err != nil {
+		return err
+	}
+
+	// a counter hands the turn to the other party
+	status := db.OfferStatusCountered
+	if user.Username == offer.Buyer {
+		status = db.OfferStatusPending
+	}
+
+	updated, err := server.store.UpdateOfferTx(c.Context(), db.UpdateOfferTxParams{
+		OfferID:        offer.ID,
+		Actor:          user.Username,
+		ExpectedStatus: offer.Status,
+		Action:         db.OfferActionCountered,
+		Status:         status,
+		Amount:         req.Amount,
+		Conditions:     req.Conditions,
+		ExpiresAt:      req.ExpiresAt,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offerCounterparty(offer, user.Username), "offer_countered", user.Username+" sent a counter offer", updated)
+
+	return c.Status(fiber.StatusOK).JSON(updated)
+}
+
+// WithdrawOffer lets the buyer pull an open offer at any point of the negotiation.
+func (server *Server) WithdrawOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	note, err := parseOfferDecision(c)
+	if err != nil {
+		return err
+	}
+
+	offer, err := server.getOpenOffer(c)
+	if err != nil {
+		return err
+	}
+
+	if offer.Buyer != user.Username {
+		return fiber.NewError(fiber.StatusForbidden, "only the buyer can withdraw an offer.")
+	}
+
+	updated, err := server.store.UpdateOfferTx(c.Context(), db.UpdateOfferTxParams{
+		OfferID:        offer.ID,
+		Actor:          user.Username,
+		ExpectedStatus: offer.Status,
+		Action:         db.OfferActionWithdrawn,
+		Status:         db.OfferStatusWithdrawn,
+		Note:           note,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offer.Seller, "offer_withdrawn", user.Username+" withdrew the offer", updated)
+
+	return c.Status(fiber.StatusOK).JSON(updated)
+}
+
+// CancelOffer calls off an accepted offer when the deal falls through. Either
+// party can cancel it, and the asset is open for offers again.
+func (server *Server) CancelOffer(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	note, err := parseOfferDecision(c)
+	if err != nil {
+		return err
+	}
+
+	offer, err := server.getOffer(c)
+	if err != nil {
+		return err
+	}
+
+	if offer.Status != db.OfferStatusAccepted {
+		return fiber.NewError(fiber.StatusBadRequest, "only an accepted offer can be cancelled.")
+	}
+
+	updated, err := server.store.CancelOfferTx(c.Context(), db.CancelOfferTxParams{
+		OfferID: offer.ID,
+		Actor:   user.Username,
+		Note:    note,
+	})
+	if err != nil {
+		return offerTxError(err)
+	}
+
+	server.notifyOffer(c.Context(), offerCounterparty(offer, user.Username), "offer_cancelled", user.Username+" cancelled the accepted offer", updated)
+
+	return c.Status(fiber.StatusOK).JSON(updated)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+type OutboxEventResponse struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	LastError     *string         `json:"last_error"`
+	PublishedAt   *time.Time      `json:"published_at"`
+	DeadAt        *time.Time      `json:"dead_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func newOutboxEventResponse(event db.OutboxEvent) OutboxEventResponse {
+	return OutboxEventResponse{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		Attempts:      event.Attempts,
+		LastError:     nullString(event.LastError),
+		PublishedAt:   nullTime(event.PublishedAt),
+		DeadAt:        nullTime(event.DeadAt),
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+// ListDeadOutboxEvents lists the domain events the outbox relay gave up on,
+// most recent first.
+func (server *Server) ListDeadOutboxEvents(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	list, err := server.store.ListDeadOutboxEvents(c.Context(), db.ListDeadOutboxEventsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get outbox events.")
+	}
+
+	total, err := server.store.CountDeadOutboxEvents(c.Context())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count outbox events.")
+	}
+
+	rsp := make([]OutboxEventResponse, 0, len(list))
+	for _, event := range list {
+		rsp = append(rsp, newOutboxEventResponse(event))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"events": rsp,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+// RetryOutboxEvent hands a dead event back to the relay with a fresh set of
+// attempts. It is published on the next run, after the events that were
+// relayed while it was dead.
+func (server *Server) RetryOutboxEvent(c *fiber.Ctx) error {
+	eventId, err := strconv.Atoi(c.Params("event_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid event_id.")
+	}
+
+	event, err := server.store.GetOutboxEvent(c.Context(), int64(eventId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "outbox event not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get outbox event.")
+	}
+
+	event, err = server.store.RetryDeadOutboxEvent(c.Context(), event.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusConflict, "only dead events can be retried.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "retry outbox event failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newOutboxEventResponse(event))
+}
@@ -0,0 +1,430 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+	"github.com/sangketkit01/real-estate-backend/payments"
+)
+
+const paymentCurrency = "THB"
+
+// Product is a listing feature that can be bought. Amounts are in satang.
+type Product struct {
+	Code         string          `json:"code"`
+	Feature      db.OrderFeature `json:"feature"`
+	DurationDays int32           `json:"duration_days"`
+	Amount       int64           `json:"amount"`
+	Currency     string          `json:"currency"`
+}
+
+var products = []Product{
+	{Code: "featured_7d", Feature: db.OrderFeatureFeatured, DurationDays: 7, Amount: 49900, Currency: paymentCurrency},
+	{Code: "top_of_search_7d", Feature: db.OrderFeatureTopOfSearch, DurationDays: 7, Amount: 99900, Currency: paymentCurrency},
+	{Code: "highlighted_7d", Feature: db.OrderFeatureHighlighted, DurationDays: 7, Amount: 19900, Currency: paymentCurrency},
+	{Code: "listing_extension_90d", Feature: db.OrderFeatureListingExtension, DurationDays: 90, Amount: 29900, Currency: paymentCurrency},
+}
+
+func findProduct(code string) (Product, bool) {
+	for _, product := range products {
+		if product.Code == code {
+			return product, true
+		}
+	}
+	return Product{}, false
+}
+
+type CreateOrderRequest struct {
+	Product string `json:"product" validate:"required"`
+}
+
+type OrderResponse struct {
+	ID           int64      `json:"id"`
+	Username     string     `json:"username"`
+	AssetID      int64      `json:"asset_id"`
+	Feature      string     `json:"feature"`
+	DurationDays int32      `json:"duration_days"`
+	Amount       int64      `json:"amount"`
+	Currency     string     `json:"currency"`
+	Status       string     `json:"status"`
+	Provider     string     `json:"provider"`
+	PromotionID  *int64     `json:"promotion_id"`
+	PaidAt       *time.Time `json:"paid_at"`
+	RefundedAt   *time.Time `json:"refunded_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func newOrderResponse(order db.Order) OrderResponse {
+	return OrderResponse{
+		ID:           order.ID,
+		Username:     order.Username,
+		AssetID:      order.AssetID,
+		Feature:      string(order.Feature),
+		DurationDays: order.DurationDays,
+		Amount:       order.Amount,
+		Currency:     order.Currency,
+		Status:       string(order.Status),
+		Provider:     order.Provider,
+		PromotionID:  nullInt64(order.PromotionID),
+		PaidAt:       nullTime(order.PaidAt),
+		RefundedAt:   nullTime(order.RefundedAt),
+		CreatedAt:    order.CreatedAt,
+	}
+}
+
+func newOrderResponses(orders []db.Order) []OrderResponse {
+	rsp := make([]OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		rsp = append(rsp, newOrderResponse(order))
+	}
+	return rsp
+}
+
+func (server *Server) ListProducts(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(products)
+}
+
+// CreateOrder starts a checkout for a feature on the asset. The feature is
+// turned on once the provider reports the payment through the webhook.
+func (server *Server) CreateOrder(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	var req CreateOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	product, ok := findProduct(req.Product)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "unknown product.")
+	}
+
+	if asset.Status {
+		return fiber.NewError(fiber.StatusBadRequest, "sold assets cannot be promoted.")
+	}
+
+	order, err := server.store.CreateOrder(c.Context(), db.CreateOrderParams{
+		Username:     user.Username,
+		AssetID:      asset.ID,
+		Feature:      product.Feature,
+		DurationDays: product.DurationDays,
+		Amount:       product.Amount,
+		Currency:     product.Currency,
+		Provider:     server.paymentProvider.Name(),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "create order failed.")
+	}
+
+	checkout, err := server.paymentProvider.CreateCheckout(c.Context(), payments.CheckoutRequest{
+		OrderID:     order.ID,
+		Amount:      order.Amount,
+		Currency:    order.Currency,
+		Description: fmt.Sprintf("%s for listing #%d", product.Code, asset.ID),
+	})
+	if err != nil {
+		log.Printf("cannot create checkout for order %d: %v", order.ID, err)
+		return fiber.NewError(fiber.StatusBadGateway, "cannot start payment.")
+	}
+
+	order, err = server.store.SetOrderProviderRef(c.Context(), db.SetOrderProviderRefParams{
+		ID:          order.ID,
+		ProviderRef: sql.NullString{String: checkout.Reference, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "create order failed.")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"order":        newOrderResponse(order),
+		"checkout_url": checkout.URL,
+	})
+}
+
+func (server *Server) MyOrders(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	orders, err := server.store.ListOrdersByUser(c.Context(), db.ListOrdersByUserParams{
+		Username: user.Username,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get orders.")
+	}
+
+	total, err := server.store.CountOrdersByUser(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count orders.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"orders": newOrderResponses(orders),
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+func (server *Server) GetOrder(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	orderId, err := strconv.Atoi(c.Params("order_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid order_id.")
+	}
+
+	order, err := server.store.GetOrder(c.Context(), int64(orderId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "order not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get order.")
+	}
+
+	if order.Username != user.Username {
+		return fiber.NewError(fiber.StatusNotFound, "order not found.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newOrderResponse(order))
+}
+
+func paymentEventStatus(eventType payments.EventType) (db.OrderStatus, bool) {
+	switch eventType {
+	case payments.EventPaymentSucceeded:
+		return db.OrderStatusPaid, true
+	case payments.EventPaymentFailed:
+		return db.OrderStatusFailed, true
+	case payments.EventRefunded:
+		return db.OrderStatusRefunded, true
+	}
+	return "", false
+}
+
+// handlePaymentWebhook verifies and applies a provider webhook. Redelivered
+// events are acknowledged without being applied again.
+func (server *Server) handlePaymentWebhook(ctx context.Context, payload []byte, signature string) (db.Order, error) {
+	event, err := server.paymentProvider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			return db.Order{}, fiber.NewError(fiber.StatusUnauthorized, "invalid signature.")
+		}
+
+		return db.Order{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	status, ok := paymentEventStatus(event.Type)
+	if !ok {
+		// acknowledge events we do not care about so they are not retried
+		return db.Order{}, nil
+	}
+
+	result, err := server.store.ProcessPaymentEventTx(ctx, db.ProcessPaymentEventTxParams{
+		Provider:  server.paymentProvider.Name(),
+		EventID:   event.ID,
+		EventType: string(event.Type),
+		Reference: event.Reference,
+		Amount:    event.Amount,
+		Status:    status,
+	})
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return db.Order{}, fiber.NewError(fiber.StatusNotFound, "order not found.")
+		case db.ErrOrderAmountMismatch:
+			return db.Order{}, fiber.NewError(fiber.StatusBadRequest, "paid amount does not match the order.")
+		}
+
+		log.Printf("cannot process payment event %s: %v", event.ID, err)
+		return db.Order{}, fiber.NewError(fiber.StatusInternalServerError, "cannot process payment event.")
+	}
+
+	if result.Activated {
+		server.notifyUser(ctx, result.Order.Username, notify.Notification{
+			Kind:  "order_paid",
+			Title: "Payment received",
+			Body:  fmt.Sprintf("Your %s for listing #%d is now active.", result.Order.Feature, result.Order.AssetID),
+			Data:  map[string]any{"order_id": result.Order.ID, "asset_id": result.Order.AssetID, "feature": result.Order.Feature},
+		})
+	}
+
+	return result.Order, nil
+}
+
+func (server *Server) PaymentWebhook(c *fiber.Ctx) error {
+	signature := c.Get(server.paymentProvider.SignatureHeader())
+
+	if _, err := server.handlePaymentWebhook(c.Context(), c.Body(), signature); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"received": true})
+}
+
+// MockCheckout stands in for the provider's payment page when the mock
+// provider is used: it pays (or with ?result=failed fails) the buyer's order
+// right away by delivering a signed webhook. It is only served when
+// PAYMENT_MOCK_CHECKOUT is set outside production.
+func (server *Server) MockCheckout(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	mock, ok := server.paymentProvider.(*payments.MockProvider)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, "not found.")
+	}
+
+	order, err := server.store.GetOrderByProviderRef(c.Context(), db.GetOrderByProviderRefParams{
+		Provider:    mock.Name(),
+		ProviderRef: sql.NullString{String: c.Params("reference"), Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "order not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get order.")
+	}
+
+	if order.Username != user.Username {
+		return fiber.NewError(fiber.StatusNotFound, "order not found.")
+	}
+
+	eventType := payments.EventPaymentSucceeded
+	if c.Query("result") == "failed" {
+		eventType = payments.EventPaymentFailed
+	}
+
+	payload, signature, err := mock.SignedEvent(eventType, order.ProviderRef.String, order.Amount)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot create payment event.")
+	}
+
+	order, err = server.handlePaymentWebhook(c.Context(), payload, signature)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newOrderResponse(order))
+}
+
+func (server *Server) ListOrders(c *fiber.Ctx) error {
+	var status db.NullOrderStatus
+	if s := c.Query("status"); s != "" {
+		switch db.OrderStatus(s) {
+		case db.OrderStatusPending, db.OrderStatusPaid, db.OrderStatusFailed, db.OrderStatusRefunded:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+		}
+		status = db.NullOrderStatus{OrderStatus: db.OrderStatus(s), Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	orders, err := server.store.ListOrders(c.Context(), db.ListOrdersParams{
+		Status:     status,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get orders.")
+	}
+
+	total, err := server.store.CountOrders(c.Context(), status)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count orders.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"orders": newOrderResponses(orders),
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+func (server *Server) RefundOrder(c *fiber.Ctx) error {
+	admin := c.Locals("user").(db.User)
+
+	orderId, err := strconv.Atoi(c.Params("order_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid order_id.")
+	}
+
+	order, err := server.store.GetOrder(c.Context(), int64(orderId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "order not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get order.")
+	}
+
+	if order.Status != db.OrderStatusPaid {
+		return fiber.NewError(fiber.StatusConflict, "only paid orders can be refunded.")
+	}
+
+	// claim the order first so a second refund of it stops here instead of
+	// at the provider
+	order, err = server.store.ClaimOrderRefund(c.Context(), order.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusConflict, "only paid orders can be refunded.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "refund order failed.")
+	}
+
+	if err := server.paymentProvider.Refund(c.Context(), order.ProviderRef.String, order.Amount); err != nil {
+		log.Printf("cannot refund order %d: %v", order.ID, err)
+		if err := server.store.ReleaseOrderRefund(context.Background(), order.ID); err != nil {
+			log.Printf("cannot release refund claim on order %d: %v", order.ID, err)
+		}
+		return fiber.NewError(fiber.StatusBadGateway, "provider refund failed.")
+	}
+
+	order, err = server.store.RefundOrderTx(c.Context(), db.RefundOrderTxParams{
+		OrderID:    order.ID,
+		RefundedBy: admin.Username,
+	})
+	if err != nil {
+		log.Printf("order %d was refunded by the provider but not marked refunded: %v", order.ID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "refund order failed.")
+	}
+
+	server.notifyUser(c.Context(), order.Username, notify.Notification{
+		Kind:  "order_refunded",
+		Title: "Order refunded",
+		Body:  fmt.Sprintf("Your %s order for listing #%d was refunded.", order.Feature, order.AssetID),
+		Data:  map[string]any{"order_id": order.ID, "asset_id": order.AssetID},
+	})
+
+	return c.Status(fiber.StatusOK).JSON(newOrderResponse(order))
+}
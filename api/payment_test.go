@@ -0,0 +1,70 @@
+package api
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestMockCheckoutNeedsFlag(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/mock/checkout/mock_abc", nil)
+	req.AddCookie(login(t, server, fake, testUser(db.UserRoleUser)))
+	do(t, server, req, http.StatusNotFound, nil)
+
+	server, fake = newTestServer(t, util.Config{Environment: "production", PaymentMockCheckout: true})
+
+	req = httptest.NewRequest(http.MethodGet, "/payments/mock/checkout/mock_abc", nil)
+	req.AddCookie(login(t, server, fake, testUser(db.UserRoleUser)))
+	do(t, server, req, http.StatusNotFound, nil)
+}
+
+func TestMockCheckoutRequiresOrderOwner(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{PaymentMockCheckout: true})
+
+	now := time.Now()
+	fake.Handle("GetOrderByProviderRef", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(int64(7), "someone-else", int64(42), "featured", int64(7), int64(49900), "THB",
+			"pending", "mock", "mock_abc", nil, nil, nil, now, now), nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/mock/checkout/mock_abc", nil)
+	do(t, server, req, http.StatusForbidden, nil)
+
+	// no handler for the payment queries, the order must not be paid
+	req = httptest.NewRequest(http.MethodGet, "/payments/mock/checkout/mock_abc", nil)
+	req.AddCookie(login(t, server, fake, testUser(db.UserRoleUser)))
+	do(t, server, req, http.StatusNotFound, nil)
+}
+
+func TestRefundOrderClaimsTheOrderFirst(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	admin := testUser(db.UserRoleAdmin)
+
+	now := time.Now()
+	fake.Handle("GetOrder", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(int64(7), "somchai", int64(42), "featured", int64(7), int64(49900), "THB",
+			"paid", "mock", "mock_abc", int64(3), now, nil, now, now), nil
+	})
+	// another admin claimed the order between the read and the claim
+	fake.Handle("ClaimOrderRefund", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/orders/7/refund", nil)
+	req.AddCookie(login(t, server, fake, admin))
+	do(t, server, req, http.StatusConflict, nil)
+
+	for _, call := range fake.Calls() {
+		if call == "BEGIN" {
+			t.Fatalf("the refund went ahead without the claim: %v", fake.Calls())
+		}
+	}
+}
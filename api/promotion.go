@@ -0,0 +1,227 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+type GrantPromotionRequest struct {
+	AssetID  int64      `json:"asset_id" validate:"required,gt=0"`
+	Type     string     `json:"type" validate:"required,oneof=featured top_of_search highlighted"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at" validate:"required"`
+}
+
+type PromotionResponse struct {
+	ID        int64     `json:"id"`
+	AssetID   int64     `json:"asset_id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	GrantedBy *string   `json:"granted_by"`
+	RevokedBy *string   `json:"revoked_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newPromotionResponse(promotion db.AssetPromotion) PromotionResponse {
+	return PromotionResponse{
+		ID:        promotion.ID,
+		AssetID:   promotion.AssetID,
+		Type:      string(promotion.Type),
+		Status:    string(promotion.Status),
+		StartsAt:  promotion.StartsAt,
+		EndsAt:    promotion.EndsAt,
+		GrantedBy: nullString(promotion.GrantedBy),
+		RevokedBy: nullString(promotion.RevokedBy),
+		CreatedAt: promotion.CreatedAt,
+	}
+}
+
+func newPromotionResponses(promotions []db.AssetPromotion) []PromotionResponse {
+	rsp := make([]PromotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		rsp = append(rsp, newPromotionResponse(promotion))
+	}
+	return rsp
+}
+
+// withPromotions marks the assets that currently have a running promotion.
+func (server *Server) withPromotions(c *fiber.Ctx, assets []AssetResponse) error {
+	if len(assets) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(assets))
+	for _, asset := range assets {
+		ids = append(ids, asset.ID)
+	}
+
+	promotions, err := server.store.GetLivePromotions(c.Context(), ids)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get promotions.")
+	}
+
+	typesById := make(map[int64][]string, len(promotions))
+	for _, promotion := range promotions {
+		typesById[promotion.AssetID] = append(typesById[promotion.AssetID], string(promotion.Type))
+	}
+
+	for i := range assets {
+		types := typesById[assets[i].ID]
+		assets[i].Promoted = len(types) > 0
+		assets[i].PromotionTypes = types
+	}
+
+	return nil
+}
+
+// AssetPromotions shows the owner every promotion their listing had.
+func (server *Server) AssetPromotions(c *fiber.Ctx) error {
+	assetId := c.Locals("asset_id").(int)
+
+	promotions, err := server.store.GetAssetPromotions(c.Context(), int64(assetId))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get promotions.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newPromotionResponses(promotions))
+}
+
+func (server *Server) ListPromotions(c *fiber.Ctx) error {
+	var status db.NullPromotionStatus
+	if s := c.Query("status"); s != "" {
+		switch db.PromotionStatus(s) {
+		case db.PromotionStatusActive, db.PromotionStatusExpired, db.PromotionStatusRevoked:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+		}
+		status = db.NullPromotionStatus{PromotionStatus: db.PromotionStatus(s), Valid: true}
+	}
+
+	var assetId sql.NullInt64
+	if s := c.Query("asset_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+		}
+		assetId = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	promotions, err := server.store.ListPromotions(c.Context(), db.ListPromotionsParams{
+		Status:     status,
+		AssetID:    assetId,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get promotions.")
+	}
+
+	total, err := server.store.CountPromotions(c.Context(), db.CountPromotionsParams{
+		Status:  status,
+		AssetID: assetId,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count promotions.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"promotions": newPromotionResponses(promotions),
+		"page":       page,
+		"limit":      limit,
+		"total":      total,
+	})
+}
+
+func (server *Server) GrantPromotion(c *fiber.Ctx) error {
+	admin := c.Locals("user").(db.User)
+
+	var req GrantPromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+
+	if !req.EndsAt.After(startsAt) || !req.EndsAt.After(time.Now()) {
+		return fiber.NewError(fiber.StatusBadRequest, "ends_at must be in the future and after starts_at.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), req.AssetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if asset.Status {
+		return fiber.NewError(fiber.StatusBadRequest, "sold assets cannot be promoted.")
+	}
+
+	promotion, err := server.store.CreatePromotion(c.Context(), db.CreatePromotionParams{
+		AssetID:   asset.ID,
+		Type:      db.PromotionType(req.Type),
+		StartsAt:  startsAt,
+		EndsAt:    req.EndsAt,
+		GrantedBy: sql.NullString{String: admin.Username, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "grant promotion failed.")
+	}
+
+	server.notifyUser(c.Context(), asset.Owner, notify.Notification{
+		Kind:  "promotion_granted",
+		Title: "Your listing is promoted",
+		Body:  fmt.Sprintf("Listing #%d is %s until %s.", asset.ID, promotion.Type, promotion.EndsAt.Format(dateLayout)),
+		Data:  map[string]any{"asset_id": asset.ID, "promotion_id": promotion.ID, "type": promotion.Type},
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(newPromotionResponse(promotion))
+}
+
+func (server *Server) RevokePromotion(c *fiber.Ctx) error {
+	admin := c.Locals("user").(db.User)
+
+	promotionId, err := strconv.Atoi(c.Params("promotion_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid promotion_id.")
+	}
+
+	promotion, err := server.store.RevokePromotion(c.Context(), db.RevokePromotionParams{
+		ID:        int64(promotionId),
+		RevokedBy: sql.NullString{String: admin.Username, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "active promotion not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "revoke promotion failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newPromotionResponse(promotion))
+}
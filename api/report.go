@@ -0,0 +1,413 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+type ReportRequest struct {
+	Category string `json:"category" validate:"required,oneof=scam duplicate sold abusive other"`
+	Comment  string `json:"comment" validate:"max=2000"`
+}
+
+type ReportResponse struct {
+	ID           int64     `json:"id"`
+	Reporter     string    `json:"reporter"`
+	TargetType   string    `json:"target_type"`
+	AssetID      *int64    `json:"asset_id"`
+	ReportedUser *string   `json:"reported_user"`
+	Category     string    `json:"category"`
+	Comment      string    `json:"comment"`
+	Status       string    `json:"status"`
+	Assignee     *string   `json:"assignee"`
+	Resolution   *string   `json:"resolution"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newReportResponse(report db.Report) ReportResponse {
+	return ReportResponse{
+		ID:           report.ID,
+		Reporter:     report.Reporter,
+		TargetType:   string(report.TargetType),
+		AssetID:      nullInt64(report.AssetID),
+		ReportedUser: nullString(report.ReportedUser),
+		Category:     string(report.Category),
+		Comment:      report.Comment,
+		Status:       string(report.Status),
+		Assignee:     nullString(report.Assignee),
+		Resolution:   nullString(report.Resolution),
+		CreatedAt:    report.CreatedAt,
+		UpdatedAt:    report.UpdatedAt,
+	}
+}
+
+func parseReportRequest(c *fiber.Ctx) (ReportRequest, error) {
+	var req ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return req, err
+	}
+
+	req.Comment = strings.TrimSpace(req.Comment)
+	return req, nil
+}
+
+func (server *Server) createReport(c *fiber.Ctx, arg db.CreateReportParams) (db.Report, error) {
+	report, err := server.store.CreateReport(c.Context(), arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return report, fiber.NewError(fiber.StatusConflict, "you already reported this, we are looking into it.")
+			}
+		}
+
+		return report, fiber.NewError(fiber.StatusInternalServerError, "cannot create report.")
+	}
+
+	return report, nil
+}
+
+func (server *Server) ReportAsset(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	if asset.Owner == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "you cannot report your own asset.")
+	}
+
+	req, err := parseReportRequest(c)
+	if err != nil {
+		return err
+	}
+
+	report, err := server.createReport(c, db.CreateReportParams{
+		Reporter:   user.Username,
+		TargetType: db.ReportTargetAsset,
+		AssetID:    sql.NullInt64{Int64: asset.ID, Valid: true},
+		Category:   db.ReportCategory(req.Category),
+		Comment:    req.Comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := server.hideReportedAsset(c.Context(), asset); err != nil {
+		log.Printf("auto-hide reported asset %d: %v\n", asset.ID, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"report": newReportResponse(report)})
+}
+
+func (server *Server) ReportUser(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	username := c.Params("username")
+	if username == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "you cannot report yourself.")
+	}
+
+	if _, err := server.store.GetUser(c.Context(), username); err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "user not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get user.")
+	}
+
+	req, err := parseReportRequest(c)
+	if err != nil {
+		return err
+	}
+
+	report, err := server.createReport(c, db.CreateReportParams{
+		Reporter:     user.Username,
+		TargetType:   db.ReportTargetUser,
+		ReportedUser: sql.NullString{String: username, Valid: true},
+		Category:     db.ReportCategory(req.Category),
+		Comment:      req.Comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"report": newReportResponse(report)})
+}
+
+// hideReportedAsset pulls a live listing back into the moderation queue once
+// enough different users have reported it.
+func (server *Server) hideReportedAsset(ctx context.Context, asset db.GetAssetByIdRow) error {
+	threshold := server.config.ReportAutoHideThreshold
+	if threshold <= 0 || asset.ModerationStatus != db.ModerationStatusApproved {
+		return nil
+	}
+
+	count, err := server.store.CountOpenReportsByAsset(ctx, sql.NullInt64{Int64: asset.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	if count < int64(threshold) {
+		return nil
+	}
+
+	_, err = server.store.ModerateAssetTx(ctx, db.ModerateAssetTxParams{
+		AssetID: asset.ID,
+		Status:  db.ModerationStatusPending,
+		Reason:  sql.NullString{String: fmt.Sprintf("%s: reported by %d users", reasonReported, count), Valid: true},
+	})
+	return err
+}
+
+func (server *Server) ListReports(c *fiber.Ctx) error {
+	var status db.NullReportStatus
+	if s := c.Query("status"); s != "" {
+		status = db.NullReportStatus{ReportStatus: db.ReportStatus(s), Valid: true}
+	}
+
+	var category db.NullReportCategory
+	if s := c.Query("category"); s != "" {
+		category = db.NullReportCategory{ReportCategory: db.ReportCategory(s), Valid: true}
+	}
+
+	var targetType db.NullReportTarget
+	if s := c.Query("target_type"); s != "" {
+		targetType = db.NullReportTarget{ReportTarget: db.ReportTarget(s), Valid: true}
+	}
+
+	var assetId sql.NullInt64
+	if s := c.Query("asset_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+		}
+		assetId = sql.NullInt64{Int64: int64(id), Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	reports, err := server.store.ListReports(c.Context(), db.ListReportsParams{
+		Status:     status,
+		Category:   category,
+		TargetType: targetType,
+		AssetID:    assetId,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		if _, ok := err.(*pq.Error); ok {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid filter.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get reports.")
+	}
+
+	total, err := server.store.CountReports(c.Context(), db.CountReportsParams{
+		Status:     status,
+		Category:   category,
+		TargetType: targetType,
+		AssetID:    assetId,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count reports.")
+	}
+
+	rsp := make([]ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		rsp = append(rsp, newReportResponse(report))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reports": rsp,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
+func (server *Server) GetReportCountsByAsset(c *fiber.Ctx) error {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	counts, err := server.store.GetReportCountsByAsset(c.Context(), db.GetReportCountsByAssetParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count reports.")
+	}
+
+	rsp := make([]fiber.Map, 0, len(counts))
+	for _, count := range counts {
+		rsp = append(rsp, fiber.Map{
+			"asset_id":         count.AssetID.Int64,
+			"total_count":      count.TotalCount,
+			"open_count":       count.OpenCount,
+			"last_reported_at": count.LastReportedAt,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"assets": rsp,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (server *Server) getReport(c *fiber.Ctx) (db.Report, error) {
+	reportId, err := strconv.Atoi(c.Params("report_id"))
+	if err != nil {
+		return db.Report{}, fiber.NewError(fiber.StatusBadRequest, "invalid report_id.")
+	}
+
+	report, err := server.store.GetReport(c.Context(), int64(reportId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return report, fiber.NewError(fiber.StatusNotFound, "report not found.")
+		}
+
+		return report, fiber.NewError(fiber.StatusInternalServerError, "cannot get report.")
+	}
+
+	return report, nil
+}
+
+type AssignReportRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+func (server *Server) AssignReport(c *fiber.Ctx) error {
+	admin := c.Locals("user").(db.User)
+
+	report, err := server.getReport(c)
+	if err != nil {
+		return err
+	}
+
+	var req AssignReportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	assignee := strings.TrimSpace(req.Assignee)
+	if assignee == "" {
+		assignee = admin.Username
+	}
+
+	assigneeUser, err := server.store.GetUser(c.Context(), assignee)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "assignee not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get assignee.")
+	}
+
+	if assigneeUser.Roles != db.UserRoleAdmin {
+		return fiber.NewError(fiber.StatusBadRequest, "reports can only be assigned to admins.")
+	}
+
+	assigned, err := server.store.AssignReport(c.Context(), db.AssignReportParams{
+		ID:       report.ID,
+		Assignee: sql.NullString{String: assignee, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot assign report.")
+	}
+
+	// closed in the meantime, assigning would reopen it
+	if assigned == 0 {
+		return fiber.NewError(fiber.StatusConflict, "report is already closed.")
+	}
+
+	return okResponse(c, "assign report successfully.")
+}
+
+type CloseReportRequest struct {
+	Resolution string `json:"resolution"`
+}
+
+func (server *Server) ResolveReport(c *fiber.Ctx) error {
+	return server.closeReport(c, db.ReportStatusResolved)
+}
+
+func (server *Server) DismissReport(c *fiber.Ctx) error {
+	return server.closeReport(c, db.ReportStatusDismissed)
+}
+
+func (server *Server) closeReport(c *fiber.Ctx, status db.ReportStatus) error {
+	report, err := server.getReport(c)
+	if err != nil {
+		return err
+	}
+
+	if report.Status == db.ReportStatusResolved || report.Status == db.ReportStatusDismissed {
+		return fiber.NewError(fiber.StatusConflict, "report is already closed.")
+	}
+
+	var req CloseReportRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	resolution := strings.TrimSpace(req.Resolution)
+	closed, err := server.store.CloseReport(c.Context(), db.CloseReportParams{
+		ID:         report.ID,
+		Status:     status,
+		Resolution: sql.NullString{String: resolution, Valid: resolution != ""},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot close report.")
+	}
+
+	// another moderator closed it since it was read
+	if closed == 0 {
+		return fiber.NewError(fiber.StatusConflict, "report is already closed.")
+	}
+
+	return okResponse(c, string(status)+" report successfully.")
+}
@@ -0,0 +1,412 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+type ReviewRequest struct {
+	Rating  int32  `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" validate:"required,max=2000"`
+}
+
+type ReviewResponse struct {
+	ID                 int64      `json:"id"`
+	Seller             string     `json:"seller"`
+	Reviewer           string     `json:"reviewer"`
+	ReviewerName       string     `json:"reviewer_name,omitempty"`
+	ReviewerProfileUrl *string    `json:"reviewer_profile_url,omitempty"`
+	Rating             int32      `json:"rating"`
+	Comment            string     `json:"comment"`
+	Reply              *string    `json:"reply"`
+	RepliedAt          *time.Time `json:"replied_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+
+	// only shown to admins
+	Status           string  `json:"status,omitempty"`
+	ModeratedBy      *string `json:"moderated_by,omitempty"`
+	ModerationReason *string `json:"moderation_reason,omitempty"`
+}
+
+type SellerProfileResponse struct {
+	Username      string    `json:"username"`
+	Name          string    `json:"name"`
+	ProfileUrl    *string   `json:"profile_url"`
+	MemberSince   time.Time `json:"member_since"`
+	ResponseRate  *float64  `json:"response_rate"`
+	ActiveCount   int64     `json:"active_count"`
+	SoldCount     int64     `json:"sold_count"`
+	ReviewCount   int64     `json:"review_count"`
+	AverageRating float64   `json:"average_rating"`
+}
+
+func newReviewResponse(review db.SellerReview) ReviewResponse {
+	return ReviewResponse{
+		ID:               review.ID,
+		Seller:           review.Seller,
+		Reviewer:         review.Reviewer,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		Reply:            nullString(review.Reply),
+		RepliedAt:        nullTime(review.RepliedAt),
+		CreatedAt:        review.CreatedAt,
+		Status:           string(review.Status),
+		ModeratedBy:      nullString(review.ModeratedBy),
+		ModerationReason: nullString(review.ModerationReason),
+	}
+}
+
+func newPublicReviewResponses(reviews []db.ListSellerReviewsRow) []ReviewResponse {
+	rsp := make([]ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		rsp = append(rsp, ReviewResponse{
+			ID:                 review.ID,
+			Seller:             review.Seller,
+			Reviewer:           review.Reviewer,
+			ReviewerName:       review.ReviewerName,
+			ReviewerProfileUrl: nullString(review.ReviewerProfileUrl),
+			Rating:             review.Rating,
+			Comment:            review.Comment,
+			Reply:              nullString(review.Reply),
+			RepliedAt:          nullTime(review.RepliedAt),
+			CreatedAt:          review.CreatedAt,
+		})
+	}
+	return rsp
+}
+
+func (server *Server) getSeller(c *fiber.Ctx) (db.User, error) {
+	username := strings.TrimSpace(c.Params("username"))
+	if username == "" {
+		return db.User{}, fiber.NewError(fiber.StatusBadRequest, "username is not provided.")
+	}
+
+	user, err := server.store.GetUser(c.Context(), username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return user, fiber.NewError(fiber.StatusNotFound, "user not found.")
+		}
+
+		return user, fiber.NewError(fiber.StatusInternalServerError, "cannot get user.")
+	}
+
+	return user, nil
+}
+
+func (server *Server) listSellerReviews(c *fiber.Ctx, seller string) (fiber.Map, error) {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 10
+	offset := (page - 1) * limit
+
+	reviews, err := server.store.ListSellerReviews(c.Context(), db.ListSellerReviewsParams{
+		Seller: seller,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "cannot get reviews.")
+	}
+
+	summary, err := server.store.GetSellerRatingSummary(c.Context(), seller)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "cannot get rating.")
+	}
+
+	return fiber.Map{
+		"reviews": newPublicReviewResponses(reviews),
+		"page":    page,
+		"limit":   limit,
+		"total":   summary.ReviewCount,
+	}, nil
+}
+
+// GetSellerProfile is the public profile of a seller: who they are, how
+// they answer buyers, how much they sell and what buyers say about them.
+func (server *Server) GetSellerProfile(c *fiber.Ctx) error {
+	seller, err := server.getSeller(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := server.store.GetSellerRatingSummary(c.Context(), seller.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get rating.")
+	}
+
+	stats, err := server.store.GetSellerResponseStats(c.Context(), seller.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get response rate.")
+	}
+
+	counts, err := server.store.GetSellerListingCounts(c.Context(), seller.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
+	}
+
+	profile := SellerProfileResponse{
+		Username:      seller.Username,
+		Name:          seller.Name,
+		ProfileUrl:    nullString(seller.ProfileUrl),
+		MemberSince:   seller.CreatedAt,
+		ActiveCount:   counts.ActiveCount,
+		SoldCount:     counts.SoldCount,
+		ReviewCount:   summary.ReviewCount,
+		AverageRating: math.Round(summary.AverageRating*10) / 10,
+	}
+
+	if stats.InquiryCount > 0 {
+		rate := math.Round(float64(stats.RepliedCount)*1000/float64(stats.InquiryCount)) / 10
+		profile.ResponseRate = &rate
+	}
+
+	reviews, err := server.listSellerReviews(c, seller.Username)
+	if err != nil {
+		return err
+	}
+
+	reviews["profile"] = profile
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+func (server *Server) GetSellerReviews(c *fiber.Ctx) error {
+	seller, err := server.getSeller(c)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := server.listSellerReviews(c, seller.Username)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+func (server *Server) CreateSellerReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	seller, err := server.getSeller(c)
+	if err != nil {
+		return err
+	}
+
+	if seller.Username == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "you cannot review yourself.")
+	}
+
+	var req ReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	// only buyers the seller answered or showed around may review them
+	completed, err := server.store.HasCompletedInteraction(c.Context(), db.HasCompletedInteractionParams{
+		Seller: seller.Username,
+		Buyer:  user.Username,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot check interaction.")
+	}
+
+	if !completed {
+		return fiber.NewError(fiber.StatusForbidden, "you can review a seller after they answered your inquiry or you attended a viewing.")
+	}
+
+	review, err := server.store.CreateSellerReview(c.Context(), db.CreateSellerReviewParams{
+		Seller:   seller.Username,
+		Reviewer: user.Username,
+		Rating:   req.Rating,
+		Comment:  strings.TrimSpace(req.Comment),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "you already reviewed this seller.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "create review failed.")
+	}
+
+	server.notifyUser(c.Context(), seller.Username, notify.Notification{
+		Kind:  "review",
+		Title: "New review",
+		Body:  fmt.Sprintf("%s rated you %d out of 5.", user.Username, review.Rating),
+		Data:  map[string]any{"review_id": review.ID, "rating": review.Rating},
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(newReviewResponse(review))
+}
+
+// ReplySellerReview lets the reviewed seller answer a review, once.
+func (server *Server) ReplySellerReview(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	reviewId, err := strconv.Atoi(c.Params("review_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid review_id.")
+	}
+
+	review, err := server.store.GetSellerReview(c.Context(), int64(reviewId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "review not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get review.")
+	}
+
+	if review.Seller != user.Username || review.Status != db.ReviewStatusVisible {
+		return fiber.NewError(fiber.StatusNotFound, "review not found.")
+	}
+
+	var req ReviewReplyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	req.Reply = strings.TrimSpace(req.Reply)
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	replied, err := server.store.ReplySellerReview(c.Context(), db.ReplySellerReviewParams{
+		ID:     review.ID,
+		Seller: user.Username,
+		Reply:  sql.NullString{String: req.Reply, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "reply review failed.")
+	}
+
+	if replied == 0 {
+		return fiber.NewError(fiber.StatusConflict, "you already replied to this review.")
+	}
+
+	server.notifyUser(c.Context(), review.Reviewer, notify.Notification{
+		Kind:  "review_reply",
+		Title: "Seller replied to your review",
+		Body:  fmt.Sprintf("%s replied to your review.", user.Username),
+		Data:  map[string]any{"review_id": review.ID, "seller": user.Username},
+	})
+
+	return okResponse(c, "reply review successfully.")
+}
+
+func (server *Server) ListReviewsForModeration(c *fiber.Ctx) error {
+	var status db.NullReviewStatus
+	if s := c.Query("status"); s != "" {
+		switch db.ReviewStatus(s) {
+		case db.ReviewStatusVisible, db.ReviewStatusHidden:
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid status.")
+		}
+		status = db.NullReviewStatus{ReviewStatus: db.ReviewStatus(s), Valid: true}
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	reviews, err := server.store.ListReviewsForModeration(c.Context(), db.ListReviewsForModerationParams{
+		Status:     status,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get reviews.")
+	}
+
+	total, err := server.store.CountReviewsForModeration(c.Context(), status)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count reviews.")
+	}
+
+	rsp := make([]ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		rsp = append(rsp, newReviewResponse(review))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"reviews": rsp,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
+func (server *Server) HideReview(c *fiber.Ctx) error {
+	var req RejectAssetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	return server.moderateReview(c, db.ReviewStatusHidden, req.Reason)
+}
+
+func (server *Server) RestoreReview(c *fiber.Ctx) error {
+	var req ModerationDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	return server.moderateReview(c, db.ReviewStatusVisible, req.Reason)
+}
+
+func (server *Server) moderateReview(c *fiber.Ctx, status db.ReviewStatus, reason string) error {
+	moderator := c.Locals("user").(db.User)
+
+	reviewId, err := strconv.Atoi(c.Params("review_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid review_id.")
+	}
+
+	reason = strings.TrimSpace(reason)
+	review, err := server.store.SetSellerReviewStatus(c.Context(), db.SetSellerReviewStatusParams{
+		ID:               int64(reviewId),
+		Status:           status,
+		ModeratedBy:      sql.NullString{String: moderator.Username, Valid: true},
+		ModerationReason: sql.NullString{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "review not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot moderate review.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newReviewResponse(review))
+}
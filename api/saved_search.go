@@ -0,0 +1,187 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+)
+
+type SavedSearchRequest struct {
+	Name       string         `json:"name" validate:"required,max=100"`
+	Filters    db.AssetFilter `json:"filters"`
+	Frequency  string         `json:"frequency" validate:"required,oneof=instant daily weekly"`
+	Channel    string         `json:"channel" validate:"required,oneof=in_app email webhook"`
+	WebhookUrl string         `json:"webhook_url" validate:"required_if=Channel webhook,omitempty,url"`
+}
+
+type SavedSearchResponse struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	Filters       db.AssetFilter `json:"filters"`
+	Frequency     string         `json:"frequency"`
+	Channel       string         `json:"channel"`
+	WebhookUrl    *string        `json:"webhook_url"`
+	LastCheckedAt time.Time      `json:"last_checked_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+func newSavedSearchResponse(search db.SavedSearch) SavedSearchResponse {
+	var filters db.AssetFilter
+	_ = json.Unmarshal(search.Filters, &filters)
+
+	return SavedSearchResponse{
+		ID:            search.ID,
+		Name:          search.Name,
+		Filters:       filters,
+		Frequency:     string(search.Frequency),
+		Channel:       string(search.Channel),
+		WebhookUrl:    nullString(search.WebhookUrl),
+		LastCheckedAt: search.LastCheckedAt,
+		CreatedAt:     search.CreatedAt,
+		UpdatedAt:     search.UpdatedAt,
+	}
+}
+
+// parseSavedSearchRequest validates the body and encodes the filters for storage.
+func (server *Server) parseSavedSearchRequest(c *fiber.Ctx) (SavedSearchRequest, []byte, error) {
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return req, nil, err
+	}
+
+	if err := validateAssetFilter(req.Filters); err != nil {
+		return req, nil, err
+	}
+
+	if req.Channel == string(db.NotificationChannelEmail) && server.config.SMTPHost == "" {
+		return req, nil, fiber.NewError(fiber.StatusBadRequest, "email notifications are not available.")
+	}
+
+	if req.Channel != string(db.NotificationChannelWebhook) {
+		req.WebhookUrl = ""
+	} else if err := safehttp.CheckURL(c.Context(), req.WebhookUrl); err != nil {
+		return req, nil, fiber.NewError(fiber.StatusBadRequest, "webhook_url must be a public http or https address.")
+	}
+
+	filters, err := json.Marshal(req.Filters)
+	if err != nil {
+		return req, nil, fiber.NewError(fiber.StatusBadRequest, "invalid filters.")
+	}
+
+	return req, filters, nil
+}
+
+// getOwnSavedSearch loads :search_id and makes sure it belongs to the current user.
+func (server *Server) getOwnSavedSearch(c *fiber.Ctx) (db.SavedSearch, error) {
+	user := c.Locals("user").(db.User)
+
+	searchId, err := strconv.Atoi(c.Params("search_id"))
+	if err != nil {
+		return db.SavedSearch{}, fiber.NewError(fiber.StatusBadRequest, "invalid search_id.")
+	}
+
+	search, err := server.store.GetSavedSearch(c.Context(), int64(searchId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return search, fiber.NewError(fiber.StatusNotFound, "saved search not found.")
+		}
+
+		return search, fiber.NewError(fiber.StatusInternalServerError, "cannot get saved search.")
+	}
+
+	if search.Username != user.Username {
+		return search, fiber.NewError(fiber.StatusNotFound, "saved search not found.")
+	}
+
+	return search, nil
+}
+
+func (server *Server) CreateSavedSearch(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	req, filters, err := server.parseSavedSearchRequest(c)
+	if err != nil {
+		return err
+	}
+
+	search, err := server.store.CreateSavedSearch(c.Context(), db.CreateSavedSearchParams{
+		Username:   user.Username,
+		Name:       req.Name,
+		Filters:    filters,
+		Frequency:  db.AlertFrequency(req.Frequency),
+		Channel:    db.NotificationChannel(req.Channel),
+		WebhookUrl: sql.NullString{String: req.WebhookUrl, Valid: req.WebhookUrl != ""},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "create saved search failed.")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newSavedSearchResponse(search))
+}
+
+func (server *Server) MySavedSearches(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	searches, err := server.store.GetSavedSearchesByUsername(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get saved searches.")
+	}
+
+	rsp := make([]SavedSearchResponse, 0, len(searches))
+	for _, search := range searches {
+		rsp = append(rsp, newSavedSearchResponse(search))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"saved_searches": rsp,
+	})
+}
+
+func (server *Server) UpdateSavedSearch(c *fiber.Ctx) error {
+	search, err := server.getOwnSavedSearch(c)
+	if err != nil {
+		return err
+	}
+
+	req, filters, err := server.parseSavedSearchRequest(c)
+	if err != nil {
+		return err
+	}
+
+	err = server.store.UpdateSavedSearch(c.Context(), db.UpdateSavedSearchParams{
+		ID:         search.ID,
+		Name:       req.Name,
+		Filters:    filters,
+		Frequency:  db.AlertFrequency(req.Frequency),
+		Channel:    db.NotificationChannel(req.Channel),
+		WebhookUrl: sql.NullString{String: req.WebhookUrl, Valid: req.WebhookUrl != ""},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "update saved search failed.")
+	}
+
+	return okResponse(c, "update saved search successfully.")
+}
+
+func (server *Server) DeleteSavedSearch(c *fiber.Ctx) error {
+	search, err := server.getOwnSavedSearch(c)
+	if err != nil {
+		return err
+	}
+
+	if err := server.store.DeleteSavedSearch(c.Context(), search.ID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "delete saved search failed.")
+	}
+
+	return okResponse(c, "delete saved search successfully.")
+}
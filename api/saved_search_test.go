@@ -0,0 +1,75 @@
+package api
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestCreateSavedSearchRejectsPrivateWebhooks(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	cookie := login(t, server, fake, testUser(db.UserRoleUser))
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"http://[::1]/hook",
+	} {
+		body := `{"name":"condos","frequency":"instant","channel":"webhook","webhook_url":"` + url + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/saved-search", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookie)
+
+		// no handler for CreateSavedSearch, the request must not get that far
+		do(t, server, req, http.StatusBadRequest, nil)
+	}
+}
+
+func TestSavedSearchKeepsReducedWithin(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	cookie := login(t, server, fake, testUser(db.UserRoleUser))
+
+	for _, days := range []string{"0", "366"} {
+		body := `{"name":"price drops","filters":{"reduced_within":` + days + `},"frequency":"daily","channel":"in_app"}`
+		req := httptest.NewRequest(http.MethodPost, "/saved-search", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookie)
+
+		do(t, server, req, http.StatusBadRequest, nil)
+	}
+
+	var stored []byte
+	fake.Handle("CreateSavedSearch", func(args []driver.Value) (dbtest.Result, error) {
+		stored = args[2].([]byte)
+		now := time.Now()
+		return dbtest.Row(int64(1), args[0], args[1], args[2], args[3], args[4], nil, now, now, now), nil
+	})
+
+	body := `{"name":"price drops","filters":{"province":"Bangkok","reduced_within":7},"frequency":"daily","channel":"in_app"}`
+	req := httptest.NewRequest(http.MethodPost, "/saved-search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+	do(t, server, req, http.StatusCreated, nil)
+
+	var filter db.AssetFilter
+	if err := json.Unmarshal(stored, &filter); err != nil {
+		t.Fatalf("stored filters %s: %v", stored, err)
+	}
+	if filter.ReducedWithin == nil || *filter.ReducedWithin != 7 {
+		t.Errorf("stored filters %s, want reduced_within 7", stored)
+	}
+
+	since := filter.NullReducedSince()
+	if want := time.Now().AddDate(0, 0, -7); !since.Valid || since.Time.Sub(want).Abs() > time.Minute {
+		t.Errorf("NullReducedSince() = %v, want about %s", since, want)
+	}
+}
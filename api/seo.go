@@ -0,0 +1,300 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sangketkit01/real-estate-backend/currency"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const (
+	// search engines accept up to 50,000 urls per sitemap
+	sitemapPageSize = 10000
+	sitemapXMLNS    = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+	metaDescriptionLength = 160
+)
+
+// listingURL is the page of a listing on the website.
+func (server *Server) listingURL(slug string) string {
+	return strings.TrimSuffix(server.config.SiteURL, "/") + "/listing/" + url.PathEscape(slug)
+}
+
+// listingTitle names a listing without a title for search results and link
+// previews.
+func listingTitle(propertyType db.PropertyType, province string) string {
+	name := string(propertyType)
+	if propertyType == db.PropertyTypeOther {
+		name = "property"
+	}
+	return fmt.Sprintf("%s%s for sale in %s", strings.ToUpper(name[:1]), name[1:], province)
+}
+
+// metaDescription squeezes the detail into one line short enough for search
+// results.
+func metaDescription(detail string) string {
+	description := strings.Join(strings.Fields(detail), " ")
+	if utf8.RuneCountInString(description) <= metaDescriptionLength {
+		return description
+	}
+
+	runes := []rune(description)[:metaDescriptionLength-1]
+	if i := strings.LastIndex(string(runes), " "); i > metaDescriptionLength/2 {
+		return string(runes)[:i] + "…"
+	}
+	return string(runes) + "…"
+}
+
+// resolveSlug finds the asset of a current or former slug. When the slug is
+// an old one, it redirects to the current slug and returns ok false.
+func (server *Server) resolveSlug(c *fiber.Ctx, suffix string) (db.ResolveAssetSlugRow, bool, error) {
+	slug, err := url.PathUnescape(c.Params("slug"))
+	if err != nil {
+		return db.ResolveAssetSlugRow{}, false, fiber.NewError(fiber.StatusBadRequest, "invalid slug.")
+	}
+
+	resolved, err := server.store.ResolveAssetSlug(c.Context(), slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return resolved, false, fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return resolved, false, fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if resolved.Slug != slug {
+		location := "/listing/" + url.PathEscape(resolved.Slug) + suffix
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			location += "?" + query
+		}
+		return resolved, false, c.Redirect(location, fiber.StatusMovedPermanently)
+	}
+
+	return resolved, true, nil
+}
+
+// GetAssetBySlug is GetAssetById for the readable listing address.
+func (server *Server) GetAssetBySlug(c *fiber.Ctx) error {
+	resolved, ok, err := server.resolveSlug(c, "")
+	if !ok {
+		return err
+	}
+
+	return server.showAsset(c, resolved.ID)
+}
+
+// GetAssetSEO returns what the website puts in the head of a listing page:
+// Open Graph and Twitter card tags and a schema.org RealEstateListing.
+func (server *Server) GetAssetSEO(c *fiber.Ctx) error {
+	resolved, ok, err := server.resolveSlug(c, "/seo")
+	if !ok {
+		return err
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), resolved.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	images, err := server.store.GetAssetImages(c.Context(), asset.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset images.")
+	}
+
+	imageUrls := make([]string, 0, len(images))
+	for _, image := range images {
+		imageUrls = append(imageUrls, server.uploadURL(image.ImageUrl))
+	}
+
+	rate, err := server.store.GetExchangeRate(c.Context(), asset.Currency)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get exchange rate.")
+	}
+	// crawlers read the price as a decimal, not in minor units
+	price := currency.FormatAmount(asset.Price, int32(rate.MinorUnits))
+
+	localized := []AssetResponse{newAssetResponse(asset)}
+	if err := server.withTranslations(c, localized); err != nil {
+		return err
+	}
+
+	canonical := server.listingURL(asset.Slug)
+	title := listingTitle(asset.PropertyType, asset.Province)
+	if localized[0].Title != nil {
+		title = *localized[0].Title
+	}
+	description := metaDescription(localized[0].Detail)
+
+	openGraph := fiber.Map{
+		"og:type":                "website",
+		"og:url":                 canonical,
+		"og:title":               title,
+		"og:description":         description,
+		"product:price:amount":   price,
+		"product:price:currency": asset.Currency,
+	}
+	twitter := fiber.Map{
+		"twitter:card":        "summary",
+		"twitter:title":       title,
+		"twitter:description": description,
+	}
+	if len(imageUrls) > 0 {
+		openGraph["og:image"] = imageUrls[0]
+		twitter["twitter:card"] = "summary_large_image"
+		twitter["twitter:image"] = imageUrls[0]
+	}
+
+	availability := "https://schema.org/InStock"
+	if asset.Status {
+		availability = "https://schema.org/SoldOut"
+	}
+
+	jsonLD := fiber.Map{
+		"@context":    "https://schema.org",
+		"@type":       "RealEstateListing",
+		"url":         canonical,
+		"name":        title,
+		"description": localized[0].Detail,
+		"inLanguage":  localized[0].Locale,
+		"datePosted":  asset.CreatedAt.Format(dateLayout),
+		"image":       imageUrls,
+		"offers": fiber.Map{
+			"@type":         "Offer",
+			"price":         price,
+			"priceCurrency": asset.Currency,
+			"availability":  availability,
+		},
+		"contentLocation": fiber.Map{
+			"@type": "Place",
+			"address": fiber.Map{
+				"@type":          "PostalAddress",
+				"addressRegion":  asset.Province,
+				"addressCountry": "TH",
+			},
+		},
+	}
+
+	contentLanguage(c, localized[0])
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"slug":          asset.Slug,
+		"locale":        localized[0].Locale,
+		"canonical_url": canonical,
+		"title":         title,
+		"description":   description,
+		"open_graph":    openGraph,
+		"twitter":       twitter,
+		"json_ld":       jsonLD,
+	})
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	XMLNS    string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+func sendXML(c *fiber.Ctx, doc any) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot write xml.")
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+func (server *Server) sitemapPages(c *fiber.Ctx) (int, error) {
+	total, err := server.store.CountSitemapAssets(c.Context())
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
+	}
+
+	pages := int((total + sitemapPageSize - 1) / sitemapPageSize)
+	return max(pages, 1), nil
+}
+
+// Sitemap is the sitemap index, it points to one sitemap per page of active
+// listings.
+func (server *Server) Sitemap(c *fiber.Ctx) error {
+	pages, err := server.sitemapPages(c)
+	if err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(server.config.PublicBaseURL, "/")
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{Loc: fmt.Sprintf("%s/sitemaps/listings-%d.xml", base, page)})
+	}
+
+	return sendXML(c, index)
+}
+
+// ListingSitemap serves /sitemaps/listings-N.xml.
+func (server *Server) ListingSitemap(c *fiber.Ctx) error {
+	name := c.Params("file")
+	page, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "listings-"), ".xml"))
+	if err != nil || page < 1 || !strings.HasPrefix(name, "listings-") || !strings.HasSuffix(name, ".xml") {
+		return fiber.NewError(fiber.StatusNotFound, "sitemap not found.")
+	}
+
+	pages, err := server.sitemapPages(c)
+	if err != nil {
+		return err
+	}
+
+	if page > pages {
+		return fiber.NewError(fiber.StatusNotFound, "sitemap not found.")
+	}
+
+	assets, err := server.store.ListSitemapAssets(c.Context(), db.ListSitemapAssetsParams{
+		Limit:  sitemapPageSize,
+		Offset: int32((page - 1) * sitemapPageSize),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get assets.")
+	}
+
+	set := sitemapURLSet{XMLNS: sitemapXMLNS, URLs: make([]sitemapURL, 0, len(assets))}
+	for _, asset := range assets {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     server.listingURL(asset.Slug),
+			LastMod: asset.UpdatedAt.Format(dateLayout),
+		})
+	}
+
+	return sendXML(c, set)
+}
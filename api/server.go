@@ -1,13 +1,18 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/jobs"
+	"github.com/sangketkit01/real-estate-backend/notify"
+	"github.com/sangketkit01/real-estate-backend/payments"
 	"github.com/sangketkit01/real-estate-backend/util"
+	"github.com/sangketkit01/real-estate-backend/worker"
 )
 
 type Server struct {
@@ -16,18 +21,38 @@ type Server struct {
 	config     util.Config
 	tokenMaker util.Maker
 	isSecure   bool
+	statsCache *statsCache
+	feedCache  feedCache
+	notifier   notify.Notifier
+	hub        *notify.Hub
+	views      *worker.ViewTracker
+	jobs       *jobs.Client
+
+	paymentProvider payments.Provider
 }
 
-func NewServer(store *db.Store, config util.Config) (*Server, error) {
+func NewServer(store *db.Store, config util.Config, hub *notify.Hub, views *worker.ViewTracker) (*Server, error) {
 	tokenMaker, err := util.NewJWTMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	paymentProvider, err := payments.NewProvider(config.PaymentProvider, config.PaymentWebhookSecret, config.PublicBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create payment provider: %w", err)
+	}
+
 	server := &Server{
 		store:      store,
 		config:     config,
 		tokenMaker: tokenMaker,
+		statsCache: newStatsCache(config.StatsCacheTTL),
+		notifier:   notify.NewInAppNotifier(store),
+		hub:        hub,
+		views:      views,
+		jobs:       jobs.NewClient(store),
+
+		paymentProvider: paymentProvider,
 	}
 
 	server.isSecure = config.Environment == "production"
@@ -44,11 +69,20 @@ func (server *Server) Start() error {
 	return server.router.Listen(":8080")
 }
 
+// Shutdown stops taking connections and waits for the open ones until ctx is
+// done. Start returns once it is called.
+func (server *Server) Shutdown(ctx context.Context) error {
+	return server.router.ShutdownWithContext(ctx)
+}
+
 func (server *Server) setUpRoute() error {
 	server.router = fiber.New(fiber.Config{
 		ErrorHandler: errorHandler,
 		JSONEncoder:  sonic.Marshal,
 		JSONDecoder:  sonic.Unmarshal,
+		// the largest limit of any route, BodyLimitMiddleware holds every
+		// other route to the default
+		BodyLimit: importBodyLimit,
 	})
 
 	server.router.Static("/static", "/uploads")
@@ -62,6 +96,9 @@ func (server *Server) setUpRoute() error {
 		MaxAge:           12 * 60 * 60,
 	}))
 
+	server.router.Use(server.LocaleMiddleware())
+	server.router.Use(server.BodyLimitMiddleware())
+
 	server.setupPublicRoutes(server.router)
 	server.setupProtectedRoutes(server.router)
 	server.setupAdminRoute(server.router)
@@ -70,12 +107,26 @@ func (server *Server) setUpRoute() error {
 }
 
 func (server *Server) setupPublicRoutes(router *fiber.App) {
-	router.Get("/", server.GetAllAssets)
+	router.Get("/", server.OptionalAuthMiddleware(), server.GetAllAssets)
 	router.Post("/create-user", server.CreateUser)
 	router.Post("/login-user", server.LoginUser)
 
-	router.Get("/watch/:asset_id", server.GetAssetById)
-	router.Get("/user/:username", server.GetAssetsByUsername)
+	router.Get("/watch/:asset_id", server.OptionalAuthMiddleware(), server.GetAssetById)
+	router.Get("/watch/:asset_id/viewing-slots", server.GetAvailableViewingSlots)
+	router.Get("/watch/:asset_id/translations", server.GetAssetTranslations)
+	router.Get("/exchange-rates", server.ListExchangeRates)
+	router.Get("/listing/:slug", server.OptionalAuthMiddleware(), server.GetAssetBySlug)
+	router.Get("/listing/:slug/seo", server.GetAssetSEO)
+	router.Get("/user/:username", server.OptionalAuthMiddleware(), server.GetAssetsByUsername)
+	router.Get("/user/:username/profile", server.GetSellerProfile)
+	router.Get("/user/:username/reviews", server.GetSellerReviews)
+	router.Get("/agency/:agency_id", server.OptionalAuthMiddleware(), server.GetAgencyPage)
+	router.Get("/feed/properties.xml", server.PropertyFeed)
+	router.Get("/sitemap.xml", server.Sitemap)
+	router.Get("/sitemaps/:file", server.ListingSitemap)
+
+	router.Get("/payments/products", server.ListProducts)
+	router.Post("/payments/webhook", server.PaymentWebhook)
 }
 
 func (server *Server) setupProtectedRoutes(router *fiber.App) {
@@ -88,37 +139,178 @@ func (server *Server) setupProtectedRoutes(router *fiber.App) {
 	authGroup.Post("/update-password", server.UpdateUserPassword)
 
 	authGroup.Get("/my-asset", server.AllMyAssets)
+	authGroup.Get("/my-asset/export", server.ExportMyAssets)
+
+	authGroup.Get("/my-favorite", server.MyFavorites)
+	authGroup.Post("/favorite/:asset_id", server.AddFavorite)
+	authGroup.Delete("/favorite/:asset_id", server.RemoveFavorite)
+
+	authGroup.Get("/notifications", server.ListNotifications)
+	authGroup.Get("/notifications/stream", server.StreamNotifications)
+	authGroup.Put("/notifications/read", server.MarkAllNotificationsRead)
+	authGroup.Put("/notifications/:notification_id/read", server.MarkNotificationRead)
+
+	authGroup.Get("/my-inquiry", server.MyInquiries)
+	authGroup.Get("/my-inquiry/assets", server.MyInquiryInbox)
+	authGroup.Post("/inquiry/asset/:asset_id", server.StartInquiry)
+	authGroup.Get("/inquiry/:thread_id", server.GetInquiryMessages)
+	authGroup.Post("/inquiry/:thread_id", server.SendInquiryMessage)
+	authGroup.Put("/inquiry/:thread_id/read", server.MarkInquiryRead)
+
+	authGroup.Get("/my-offer", server.MyOffers)
+	authGroup.Post("/offer/asset/:asset_id", server.SubmitOffer)
+	authGroup.Get("/offer/:offer_id", server.GetOffer)
+	authGroup.Post("/offer/:offer_id/accept", server.AcceptOffer)
+	authGroup.Post("/offer/:offer_id/reject", server.RejectOffer)
+	authGroup.Post("/offer/:offer_id/counter", server.CounterOffer)
+	authGroup.Post("/offer/:offer_id/withdraw", server.WithdrawOffer)
+	authGroup.Post("/offer/:offer_id/cancel", server.CancelOffer)
+
+	authGroup.Get("/my-viewing", server.MyViewings)
+	authGroup.Get("/my-viewing-calendar", server.SellerCalendar)
+	authGroup.Post("/viewing/slot/:slot_id", server.BookViewing)
+	authGroup.Put("/viewing/:viewing_id", server.RescheduleViewing)
+	authGroup.Delete("/viewing/:viewing_id", server.CancelViewing)
+	authGroup.Get("/viewing/:viewing_id/ics", server.DownloadViewingCalendar)
+
+	authGroup.Get("/my-saved-search", server.MySavedSearches)
+	authGroup.Post("/saved-search", server.CreateSavedSearch)
+	authGroup.Put("/saved-search/:search_id", server.UpdateSavedSearch)
+	authGroup.Delete("/saved-search/:search_id", server.DeleteSavedSearch)
+
+	authGroup.Post("/agency", server.CreateAgency)
+	authGroup.Get("/my-agency", server.GetMyAgency)
+	authGroup.Put("/my-agency", server.UpdateMyAgency)
+	authGroup.Get("/my-agency/assets", server.MyAgencyAssets)
+	authGroup.Get("/my-agency/assets/export", server.ExportAgencyAssets)
+	authGroup.Post("/my-agency/members", server.InviteAgencyMember)
+	authGroup.Get("/my-agency/invites", server.ListAgencyInvites)
+	authGroup.Delete("/my-agency/invites/:invite_id", server.CancelAgencyInvite)
+	authGroup.Put("/my-agency/members/:username", server.UpdateAgencyMemberRole)
+	authGroup.Delete("/my-agency/members/:username", server.RemoveAgencyMember)
+	authGroup.Get("/agency-invites", server.MyAgencyInvites)
+	authGroup.Post("/agency-invites/:invite_id/accept", server.AcceptAgencyInvite)
+	authGroup.Delete("/agency-invites/:invite_id", server.DeclineAgencyInvite)
+	authGroup.Post("/my-agency/imports", server.ImportAssets)
+	authGroup.Get("/my-agency/imports", server.ListAssetImports)
+	authGroup.Get("/my-agency/imports/:import_id", server.GetAssetImport)
+
+	authGroup.Post("/user/:username/reviews", server.CreateSellerReview)
+	authGroup.Post("/review/:review_id/reply", server.ReplySellerReview)
+
+	authGroup.Get("/webhooks", server.MyWebhooks)
+	authGroup.Post("/webhooks", server.CreateWebhook)
+	authGroup.Put("/webhooks/:webhook_id", server.UpdateWebhook)
+	authGroup.Delete("/webhooks/:webhook_id", server.DeleteWebhook)
+	authGroup.Get("/webhooks/:webhook_id/deliveries", server.WebhookDeliveries)
+	authGroup.Get("/webhooks/:webhook_id/deliveries/:delivery_id", server.GetWebhookDelivery)
+	authGroup.Post("/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", server.RedeliverWebhook)
+
+	authGroup.Get("/my-order", server.MyOrders)
+	authGroup.Get("/order/:order_id", server.GetOrder)
+	if server.config.PaymentMockCheckout && server.config.Environment != "production" {
+		authGroup.Get("/payments/mock/checkout/:reference", server.MockCheckout)
+	}
+
+	authGroup.Post("/report/asset/:asset_id", server.ReportAsset)
+	authGroup.Post("/report/user/:username", server.ReportUser)
 
 	assetGroup := authGroup.Group("/asset")
 
-	assetGroup.Get("/my-asset-detail/:asset_id", server.AssetMiddleware(), server.EditAsset)
+	assetGroup.Get("/my-asset-detail/:asset_id", server.AssetEditorMiddleware(), server.EditAsset)
+
+	assetGroup.Get("/:asset_id/inquiries", server.AssetEditorMiddleware(), server.AssetInquiries)
+	assetGroup.Get("/:asset_id/analytics", server.AssetEditorMiddleware(), server.GetAssetAnalytics)
+
+	assetGroup.Get("/:asset_id/offers", server.AssetEditorMiddleware(), server.AssetOffers)
+	assetGroup.Get("/:asset_id/viewing-slots", server.AssetEditorMiddleware(), server.ListAssetViewingSlots)
+	assetGroup.Post("/:asset_id/viewing-slots", server.AssetEditorMiddleware(), server.CreateViewingSlots)
+	assetGroup.Delete("/:asset_id/viewing-slots/:slot_id", server.AssetEditorMiddleware(), server.DeleteViewingSlot)
+
+	assetGroup.Get("/:asset_id/promotions", server.AssetEditorMiddleware(), server.AssetPromotions)
+	assetGroup.Post("/:asset_id/orders", server.AssetMiddleware(), server.CreateOrder)
+	assetGroup.Post("/:asset_id/renew", server.AssetEditorMiddleware(), server.RenewAsset)
+	assetGroup.Post("/:asset_id/sold", server.AssetMiddleware(), server.MarkAssetSold)
 
-	assetGroup.Put("/:asset_id", server.AssetMiddleware(), server.UpdateAsset)
+	assetGroup.Put("/:asset_id/agency", server.AssetEditorMiddleware(), server.AssignAssetAgency)
+	assetGroup.Delete("/:asset_id/agency", server.AssetMiddleware(), server.RemoveAssetAgency)
+
+	assetGroup.Put("/:asset_id/translations/:locale", server.AssetEditorMiddleware(), server.UpsertAssetTranslation)
+	assetGroup.Delete("/:asset_id/translations/:locale", server.AssetEditorMiddleware(), server.DeleteAssetTranslation)
+
+	assetGroup.Put("/:asset_id", server.AssetEditorMiddleware(), server.UpdateAsset)
 	assetGroup.Delete("/:asset_id", server.AssetMiddleware(), server.DeleteAsset)
 
-	assetGroup.Post("/:asset_id/add-contact", server.AssetMiddleware(), server.AddNewContact)
-	assetGroup.Put("/:asset_id/:contact_id", server.AssetMiddleware(), server.UpdateContact)
-	assetGroup.Delete("/:asset_id/:contact_id", server.AssetMiddleware(), server.DeleteContact)
+	assetGroup.Post("/:asset_id/add-contact", server.AssetEditorMiddleware(), server.AddNewContact)
+	assetGroup.Put("/:asset_id/:contact_id", server.AssetEditorMiddleware(), server.UpdateContact)
+	assetGroup.Delete("/:asset_id/:contact_id", server.AssetEditorMiddleware(), server.DeleteContact)
 
-	assetGroup.Post("/:asset_id/add-image", server.AssetMiddleware(), server.AddNewImage)
-	assetGroup.Delete("/:asset_id/:image_id", server.AssetMiddleware(), server.DeleteImage)
+	assetGroup.Post("/:asset_id/add-image", server.AssetEditorMiddleware(), server.AddNewImage)
+	assetGroup.Delete("/:asset_id/:image_id", server.AssetEditorMiddleware(), server.DeleteImage)
 }
 
 func (server *Server) setupAdminRoute(router *fiber.App) {
 	adminGroup := router.Group("/admin", server.AuthMiddleware(), server.AdminMiddleware())
+	adminGroup.Get("/stats", server.GetAdminStats)
+
 	adminGroup.Get("/users", server.GetAllUsers)
+	adminGroup.Put("/users/:username/trusted", server.SetUserTrusted)
+
+	adminGroup.Get("/moderation", server.GetModerationQueue)
+	adminGroup.Get("/moderation/:asset_id", server.GetModerationItem)
+	adminGroup.Post("/moderation/:asset_id/approve", server.ApproveAsset)
+	adminGroup.Post("/moderation/:asset_id/reject", server.RejectAsset)
+
+	adminGroup.Get("/agencies", server.ListAgencies)
+	adminGroup.Put("/agencies/:agency_id/verified", server.SetAgencyVerified)
+
+	adminGroup.Get("/promotions", server.ListPromotions)
+	adminGroup.Post("/promotions", server.GrantPromotion)
+	adminGroup.Post("/promotions/:promotion_id/revoke", server.RevokePromotion)
+
+	adminGroup.Get("/orders", server.ListOrders)
+	adminGroup.Post("/orders/:order_id/refund", server.RefundOrder)
+
+	adminGroup.Get("/reviews", server.ListReviewsForModeration)
+	adminGroup.Post("/reviews/:review_id/hide", server.HideReview)
+	adminGroup.Post("/reviews/:review_id/restore", server.RestoreReview)
+
+	adminGroup.Get("/jobs", server.ListJobs)
+	adminGroup.Get("/jobs/stats", server.GetJobStats)
+	adminGroup.Get("/jobs/:job_id", server.GetJob)
+	adminGroup.Post("/jobs/:job_id/retry", server.RetryJob)
+
+	adminGroup.Get("/outbox/dead", server.ListDeadOutboxEvents)
+	adminGroup.Post("/outbox/:event_id/retry", server.RetryOutboxEvent)
+
+	adminGroup.Post("/exchange-rates/import", server.ImportExchangeRates)
+	adminGroup.Put("/exchange-rates/:currency", server.SetExchangeRate)
+	adminGroup.Delete("/exchange-rates/:currency", server.DeleteExchangeRate)
+
+	adminGroup.Get("/reports", server.ListReports)
+	adminGroup.Get("/reports/assets", server.GetReportCountsByAsset)
+	adminGroup.Put("/reports/:report_id/assign", server.AssignReport)
+	adminGroup.Post("/reports/:report_id/resolve", server.ResolveReport)
+	adminGroup.Post("/reports/:report_id/dismiss", server.DismissReport)
 }
 
 func errorHandler(c *fiber.Ctx, err error) error {
 	code := fiber.StatusInternalServerError
 	msg := "Internal Server Error"
 
+	if message, fields, ok := translateValidation(c, err); ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  message,
+			"fields": fields,
+		})
+	}
+
 	if e, ok := err.(*fiber.Error); ok {
 		code = e.Code
 		msg = e.Message
 	}
 
 	return c.Status(code).JSON(fiber.Map{
-		"error": msg,
+		"error": translateMessage(c, code, msg),
 	})
 }
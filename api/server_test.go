@@ -0,0 +1,97 @@
+package api
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func newTestServer(t *testing.T, config util.Config) (*Server, *dbtest.DB) {
+	t.Helper()
+
+	if config.TokenSymmetricKey == "" {
+		config.TokenSymmetricKey = "12345678901234567890123456789012"
+	}
+	if config.PaymentProvider == "" {
+		config.PaymentProvider = "mock"
+	}
+
+	fake, conn := dbtest.New(t)
+	server, err := NewServer(db.NewStore(conn), config, nil, nil)
+	if err != nil {
+		t.Fatalf("cannot create server: %v", err)
+	}
+	return server, fake
+}
+
+// userRow is a users row in the column order of SELECT * FROM users.
+func userRow(user db.User) []driver.Value {
+	var profileUrl driver.Value
+	if user.ProfileUrl.Valid {
+		profileUrl = user.ProfileUrl.String
+	}
+
+	return []driver.Value{
+		user.Username,
+		user.Name,
+		user.Email,
+		user.Phone,
+		user.Password,
+		profileUrl,
+		user.CreatedAt,
+		user.UpdatedAt,
+		string(user.Roles),
+		user.IsTrusted,
+	}
+}
+
+// login makes GetUser return user and returns a token cookie for them.
+func login(t *testing.T, server *Server, fake *dbtest.DB, user db.User) *http.Cookie {
+	t.Helper()
+
+	fake.Handle("GetUser", func(args []driver.Value) (dbtest.Result, error) {
+		if args[0] != user.Username {
+			return dbtest.Result{}, nil
+		}
+		return dbtest.Row(userRow(user)...), nil
+	})
+
+	token, _, err := server.tokenMaker.CreateToken(user.Username, time.Minute)
+	if err != nil {
+		t.Fatalf("cannot create token: %v", err)
+	}
+	return &http.Cookie{Name: "token", Value: token}
+}
+
+// do sends req to the server and decodes the JSON response into v.
+func do(t *testing.T, server *Server, req *http.Request, wantStatus int, v any) {
+	t.Helper()
+
+	rsp, err := server.router.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		t.Fatalf("cannot read response: %v", err)
+	}
+
+	if rsp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: status %d, want %d: %s", req.Method, req.URL, rsp.StatusCode, wantStatus, body)
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(body, v); err != nil {
+			t.Fatalf("cannot decode %s: %v", body, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const dateLayout = "2006-01-02"
+
+// statsCache keeps computed dashboard statistics for a short while, the
+// aggregates scan whole tables and admins tend to reload the page a lot.
+type statsCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]statsCacheEntry
+}
+
+type statsCacheEntry struct {
+	data      fiber.Map
+	expiresAt time.Time
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{
+		ttl:     ttl,
+		entries: make(map[string]statsCacheEntry),
+	}
+}
+
+func (cache *statsCache) get(key string) (fiber.Map, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.data, true
+}
+
+func (cache *statsCache) set(key string, data fiber.Map) {
+	if cache.ttl <= 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, k)
+		}
+	}
+
+	cache.entries[key] = statsCacheEntry{data: data, expiresAt: now.Add(cache.ttl)}
+}
+
+// parseDateRange reads ?from= and ?to= as inclusive dates and returns a
+// half-open [from, to) time range, defaulting to the last 30 days.
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	to := today
+	from := today.AddDate(0, 0, -29)
+
+	var err error
+	if s := c.Query("to"); s != "" {
+		to, err = time.Parse(dateLayout, s)
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "to must be YYYY-MM-DD.")
+		}
+	}
+
+	if s := c.Query("from"); s != "" {
+		from, err = time.Parse(dateLayout, s)
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "from must be YYYY-MM-DD.")
+		}
+	}
+
+	if from.After(to) {
+		return from, to, fiber.NewError(fiber.StatusBadRequest, "from must not be after to.")
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func (server *Server) GetAdminStats(c *fiber.Ctx) error {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		return err
+	}
+
+	bucket := c.Query("bucket", "day")
+	switch bucket {
+	case "day", "week", "month":
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "bucket must be day, week or month.")
+	}
+
+	top, err := strconv.Atoi(c.Query("top", "10"))
+	if err != nil || top <= 0 || top > 100 {
+		top = 10
+	}
+
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", int(server.config.StatsCacheTTL.Seconds())))
+
+	key := fmt.Sprintf("%s|%s|%s|%d", from.Format(dateLayout), to.Format(dateLayout), bucket, top)
+	if stats, ok := server.statsCache.get(key); ok {
+		return c.Status(fiber.StatusOK).JSON(stats)
+	}
+
+	newUsers, err := server.store.GetNewUsersPerBucket(c.Context(), db.GetNewUsersPerBucketParams{
+		BucketSize: bucket,
+		FromTime:   from,
+		ToTime:     to,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get user stats.")
+	}
+
+	newAssets, err := server.store.GetNewAssetsPerBucket(c.Context(), db.GetNewAssetsPerBucketParams{
+		BucketSize: bucket,
+		FromTime:   from,
+		ToTime:     to,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset stats.")
+	}
+
+	statusCounts, err := server.store.GetAssetStatusCounts(c.Context(), db.GetAssetStatusCountsParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count assets.")
+	}
+
+	priceStats, err := server.store.GetPriceStats(c.Context(), db.GetPriceStatsParams{
+		FromTime: from,
+		ToTime:   to,
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get price stats.")
+	}
+
+	topSellers, err := server.store.GetTopSellers(c.Context(), db.GetTopSellersParams{
+		FromTime:    from,
+		ToTime:      to,
+		SellerLimit: int32(top),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get top sellers.")
+	}
+
+	stats := fiber.Map{
+		"from":         from.Format(dateLayout),
+		"to":           to.AddDate(0, 0, -1).Format(dateLayout),
+		"bucket":       bucket,
+		"new_users":    newUsers,
+		"new_assets":   newAssets,
+		"asset_status": statusCounts,
+		"price_stats":  priceStats,
+		"top_sellers":  topSellers,
+		"generated_at": time.Now(),
+	}
+	server.statsCache.set(key, stats)
+
+	return c.Status(fiber.StatusOK).JSON(stats)
+}
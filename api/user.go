@@ -25,7 +25,7 @@ func (server *Server) GetUserData(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusForbidden, "invalid user type")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(user)
+	return c.Status(fiber.StatusOK).JSON(newUserResponse(user))
 }
 
 type LoginUserRequest struct {
@@ -40,9 +40,8 @@ func (server *Server) LoginUser(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request")
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
 	user, err := server.store.LoginUser(ctx.Context(), req.Username)
@@ -110,9 +109,8 @@ func (server *Server) CreateUser(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
 	hashedPassword, err := util.HashedPassword(req.Password)
@@ -128,7 +126,7 @@ func (server *Server) CreateUser(ctx *fiber.Ctx) error {
 		Password: hashedPassword,
 	}
 
-	user, err := server.store.CreateUser(ctx.Context(), arg)
+	user, err := server.store.CreateUserTx(ctx.Context(), arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == "23505" {
@@ -173,9 +171,8 @@ func (server *Server) UpdateUser(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
 	nameValid := strings.TrimSpace(req.Name) != ""
@@ -213,7 +210,7 @@ func (server *Server) UpdateUser(c *fiber.Ctx) error {
 		Username:   user.Username,
 	}
 
-	if err := server.store.UpdateUser(c.Context(), arg); err != nil {
+	if err := server.store.UpdateUserTx(c.Context(), arg); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "update failed.")
 	}
 
@@ -234,9 +231,8 @@ func (server *Server) UpdateUserPassword(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	validator := validator.New()
-	if err := validator.Struct(req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	if err := validate.Struct(req); err != nil {
+		return err
 	}
 
 	hashedPassword, err := server.store.GetUserPassword(c.Context(), user.Username)
@@ -262,7 +258,7 @@ func (server *Server) UpdateUserPassword(c *fiber.Ctx) error {
 		Username: user.Username,
 	}
 
-	if err = server.store.UpdateUserPassword(c.Context(), arg); err != nil {
+	if err = server.store.UpdateUserPasswordTx(c.Context(), arg); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "update password failed.")
 	}
 
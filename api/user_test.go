@@ -0,0 +1,69 @@
+package api
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestGetUserDataHidesPassword(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	user := testUser(db.UserRoleUser)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(login(t, server, fake, user))
+
+	var rsp map[string]json.RawMessage
+	do(t, server, req, http.StatusOK, &rsp)
+
+	requireNoKeys(t, rsp, "password")
+	if string(rsp["username"]) != `"`+user.Username+`"` {
+		t.Errorf("username = %s, want %q", rsp["username"], user.Username)
+	}
+}
+
+func TestGetUserDataRequiresLogin(t *testing.T) {
+	server, _ := newTestServer(t, util.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	do(t, server, req, http.StatusForbidden, nil)
+}
+
+func TestAdminGetAllUsersHidesPasswords(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	admin := testUser(db.UserRoleAdmin)
+	admin.Username = "admin"
+
+	other := testUser(db.UserRoleUser)
+	fake.Handle("GetAllUsers", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{Rows: [][]driver.Value{userRow(admin), userRow(other)}}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	req.AddCookie(login(t, server, fake, admin))
+
+	var rsp []map[string]json.RawMessage
+	do(t, server, req, http.StatusOK, &rsp)
+
+	if len(rsp) != 2 {
+		t.Fatalf("got %d users, want 2", len(rsp))
+	}
+	for _, user := range rsp {
+		requireNoKeys(t, user, "password")
+	}
+}
+
+func TestAdminGetAllUsersRequiresAdmin(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+	req.AddCookie(login(t, server, fake, testUser(db.UserRoleUser)))
+
+	do(t, server, req, http.StatusUnauthorized, nil)
+}
@@ -0,0 +1,460 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+type ViewingSlotRequest struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+type CreateViewingSlotsRequest struct {
+	Slots []ViewingSlotRequest `json:"slots" validate:"required,min=1,max=50,dive"`
+}
+
+type BookViewingRequest struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+type RescheduleViewingRequest struct {
+	SlotID int64 `json:"slot_id" validate:"required"`
+}
+
+type ViewingResponse struct {
+	ID          int64     `json:"id"`
+	SlotID      int64     `json:"slot_id"`
+	AssetID     int64     `json:"asset_id"`
+	Buyer       string    `json:"buyer"`
+	Seller      string    `json:"seller"`
+	Status      string    `json:"status"`
+	Note        string    `json:"note"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	CalendarUrl string    `json:"calendar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func viewingCalendarUrl(viewingId int64) string {
+	return fmt.Sprintf("/viewing/%d/ics", viewingId)
+}
+
+func newViewingResponse(viewing db.GetViewingDetailRow) ViewingResponse {
+	return ViewingResponse{
+		ID:          viewing.ID,
+		SlotID:      viewing.SlotID,
+		AssetID:     viewing.AssetID,
+		Buyer:       viewing.Buyer,
+		Seller:      viewing.Seller,
+		Status:      string(viewing.Status),
+		Note:        viewing.Note,
+		StartsAt:    viewing.StartsAt,
+		EndsAt:      viewing.EndsAt,
+		CalendarUrl: viewingCalendarUrl(viewing.ID),
+		CreatedAt:   viewing.CreatedAt,
+		UpdatedAt:   viewing.UpdatedAt,
+	}
+}
+
+func newViewingEvent(viewing db.GetViewingDetailRow) util.CalendarEvent {
+	return util.CalendarEvent{
+		UID:         fmt.Sprintf("viewing-%d@real-estate-backend", viewing.ID),
+		Summary:     fmt.Sprintf("Property viewing #%d", viewing.AssetID),
+		Description: fmt.Sprintf("%s\n\nSeller: %s\nBuyer: %s", viewing.AssetDetail, viewing.Seller, viewing.Buyer),
+		Location:    viewing.Province,
+		Start:       viewing.StartsAt,
+		End:         viewing.EndsAt,
+		Cancelled:   viewing.Status == db.ViewingStatusCancelled,
+	}
+}
+
+// notifyViewing tells one side of a viewing what the other side did, with a
+// link to the updated calendar file.
+func (server *Server) notifyViewing(ctx context.Context, username, kind, title string, viewing db.GetViewingDetailRow) {
+	server.notifyUser(ctx, username, notify.Notification{
+		Kind:  kind,
+		Title: title,
+		Body: fmt.Sprintf("Viewing of listing #%d on %s.",
+			viewing.AssetID, viewing.StartsAt.Format("Mon 2 Jan 2006 15:04 MST")),
+		Data: map[string]any{
+			"viewing_id":   viewing.ID,
+			"asset_id":     viewing.AssetID,
+			"starts_at":    viewing.StartsAt,
+			"ends_at":      viewing.EndsAt,
+			"calendar_url": viewingCalendarUrl(viewing.ID),
+		},
+	})
+}
+
+// getViewing loads :viewing_id for its buyer or seller.
+func (server *Server) getViewing(c *fiber.Ctx) (db.GetViewingDetailRow, error) {
+	user := c.Locals("user").(db.User)
+
+	viewingId, err := strconv.Atoi(c.Params("viewing_id"))
+	if err != nil {
+		return db.GetViewingDetailRow{}, fiber.NewError(fiber.StatusBadRequest, "invalid viewing_id.")
+	}
+
+	viewing, err := server.store.GetViewingDetail(c.Context(), int64(viewingId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return viewing, fiber.NewError(fiber.StatusNotFound, "viewing not found.")
+		}
+
+		return viewing, fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing.")
+	}
+
+	if viewing.Buyer != user.Username && viewing.Seller != user.Username {
+		return viewing, fiber.NewError(fiber.StatusNotFound, "viewing not found.")
+	}
+
+	return viewing, nil
+}
+
+// getBookableSlot loads a slot and checks it can still be booked.
+func (server *Server) getBookableSlot(ctx context.Context, slotId int64) (db.ViewingSlot, error) {
+	slot, err := server.store.GetViewingSlot(ctx, slotId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return slot, fiber.NewError(fiber.StatusNotFound, "viewing slot not found.")
+		}
+
+		return slot, fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing slot.")
+	}
+
+	if !slot.StartsAt.After(time.Now()) {
+		return slot, fiber.NewError(fiber.StatusBadRequest, "viewing slot has already started.")
+	}
+
+	return slot, nil
+}
+
+func (server *Server) CreateViewingSlots(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	var req CreateViewingSlotsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	args := make([]db.CreateViewingSlotParams, 0, len(req.Slots))
+	for _, slot := range req.Slots {
+		if !slot.StartsAt.After(time.Now()) {
+			return fiber.NewError(fiber.StatusBadRequest, "viewing slots must start in the future.")
+		}
+
+		args = append(args, db.CreateViewingSlotParams{
+			AssetID:  asset.ID,
+			StartsAt: slot.StartsAt,
+			EndsAt:   slot.EndsAt,
+		})
+	}
+
+	slots, err := server.store.CreateViewingSlotsTx(c.Context(), args)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "a viewing slot already starts at that time.")
+			}
+			// exclusion_violation from viewing_slots_no_overlap
+			if pqErr.Code == "23P01" {
+				return fiber.NewError(fiber.StatusConflict, "viewing slots must not overlap.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "create viewing slots failed.")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"slots": slots,
+	})
+}
+
+// ListAssetViewingSlots shows the owner every upcoming slot with its booking.
+func (server *Server) ListAssetViewingSlots(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	slots, err := server.store.ListViewingSlotsByAsset(c.Context(), asset.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing slots.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"slots": slots,
+	})
+}
+
+func (server *Server) DeleteViewingSlot(c *fiber.Ctx) error {
+	asset := c.Locals("asset").(db.GetAssetByIdRow)
+
+	slotId, err := strconv.Atoi(c.Params("slot_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid slot_id.")
+	}
+
+	slot, err := server.store.GetViewingSlot(c.Context(), int64(slotId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "viewing slot not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing slot.")
+	}
+
+	if slot.AssetID != asset.ID {
+		return fiber.NewError(fiber.StatusNotFound, "viewing slot not found.")
+	}
+
+	deleted, err := server.store.DeleteViewingSlot(c.Context(), slot.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "delete viewing slot failed.")
+	}
+
+	if deleted == 0 {
+		return fiber.NewError(fiber.StatusConflict, "viewing slot is booked, cancel the viewing first.")
+	}
+
+	return okResponse(c, "delete viewing slot successfully.")
+}
+
+func (server *Server) GetAvailableViewingSlots(c *fiber.Ctx) error {
+	assetId, err := strconv.Atoi(c.Params("asset_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid asset_id.")
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), int64(assetId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "asset not found.")
+	}
+
+	slots, err := server.store.ListAvailableViewingSlots(c.Context(), asset.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing slots.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"slots": slots,
+	})
+}
+
+func (server *Server) BookViewing(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	slotId, err := strconv.Atoi(c.Params("slot_id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid slot_id.")
+	}
+
+	var req BookViewingRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	slot, err := server.getBookableSlot(c.Context(), int64(slotId))
+	if err != nil {
+		return err
+	}
+
+	asset, err := server.store.GetAssetById(c.Context(), slot.AssetID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get asset.")
+	}
+
+	if !isPublicAsset(asset) {
+		return fiber.NewError(fiber.StatusNotFound, "viewing slot not found.")
+	}
+
+	if asset.Owner == user.Username {
+		return fiber.NewError(fiber.StatusBadRequest, "cannot book a viewing of your own asset.")
+	}
+
+	created, err := server.store.CreateViewing(c.Context(), db.CreateViewingParams{
+		SlotID:  slot.ID,
+		AssetID: slot.AssetID,
+		Buyer:   user.Username,
+		Note:    req.Note,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "this slot is taken or you already have a viewing of this asset.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "book viewing failed.")
+	}
+
+	viewing, err := server.store.GetViewingDetail(c.Context(), created.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing.")
+	}
+
+	server.notifyViewing(c.Context(), viewing.Seller, "viewing_booked", user.Username+" booked a viewing", viewing)
+
+	return c.Status(fiber.StatusCreated).JSON(newViewingResponse(viewing))
+}
+
+func (server *Server) RescheduleViewing(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	viewing, err := server.getViewing(c)
+	if err != nil {
+		return err
+	}
+
+	if viewing.Buyer != user.Username {
+		return fiber.NewError(fiber.StatusForbidden, "only the buyer can reschedule a viewing.")
+	}
+
+	if viewing.Status != db.ViewingStatusBooked {
+		return fiber.NewError(fiber.StatusBadRequest, "viewing is cancelled.")
+	}
+
+	var req RescheduleViewingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	slot, err := server.getBookableSlot(c.Context(), req.SlotID)
+	if err != nil {
+		return err
+	}
+
+	if slot.AssetID != viewing.AssetID {
+		return fiber.NewError(fiber.StatusBadRequest, "viewing slot belongs to another asset.")
+	}
+
+	err = server.store.RescheduleViewing(c.Context(), db.RescheduleViewingParams{
+		ID:     viewing.ID,
+		SlotID: slot.ID,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			if pqErr.Code == "23505" {
+				return fiber.NewError(fiber.StatusConflict, "this slot is already taken.")
+			}
+		}
+
+		return fiber.NewError(fiber.StatusInternalServerError, "reschedule viewing failed.")
+	}
+
+	viewing, err = server.store.GetViewingDetail(c.Context(), viewing.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewing.")
+	}
+
+	server.notifyViewing(c.Context(), viewing.Seller, "viewing_rescheduled", user.Username+" rescheduled a viewing", viewing)
+
+	return c.Status(fiber.StatusOK).JSON(newViewingResponse(viewing))
+}
+
+// CancelViewing can be used by either side and frees the slot for someone else.
+func (server *Server) CancelViewing(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	viewing, err := server.getViewing(c)
+	if err != nil {
+		return err
+	}
+
+	if viewing.Status != db.ViewingStatusBooked {
+		return fiber.NewError(fiber.StatusBadRequest, "viewing is already cancelled.")
+	}
+
+	if err := server.store.CancelViewing(c.Context(), viewing.ID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cancel viewing failed.")
+	}
+	viewing.Status = db.ViewingStatusCancelled
+
+	other := viewing.Seller
+	if user.Username == viewing.Seller {
+		other = viewing.Buyer
+	}
+	server.notifyViewing(c.Context(), other, "viewing_cancelled", user.Username+" cancelled a viewing", viewing)
+
+	return okResponse(c, "cancel viewing successfully.")
+}
+
+func (server *Server) DownloadViewingCalendar(c *fiber.Ctx) error {
+	viewing, err := server.getViewing(c)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="viewing-%d.ics"`, viewing.ID))
+
+	return c.Status(fiber.StatusOK).Send(newViewingEvent(viewing).ICS())
+}
+
+func (server *Server) MyViewings(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	viewings, err := server.store.GetBuyerViewings(c.Context(), user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get viewings.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"viewings": viewings,
+	})
+}
+
+// SellerCalendar lists upcoming booked viewings across all of the seller's assets.
+func (server *Server) SellerCalendar(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	days, err := strconv.Atoi(c.Query("days", "30"))
+	if err != nil || days <= 0 || days > 365 {
+		days = 30
+	}
+
+	from := time.Now()
+	viewings, err := server.store.GetSellerCalendar(c.Context(), db.GetSellerCalendarParams{
+		Owner:    user.Username,
+		FromTime: from,
+		ToTime:   from.AddDate(0, 0, days),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get calendar.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"viewings": viewings,
+		"days":     days,
+	})
+}
@@ -0,0 +1,553 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+	"github.com/sangketkit01/real-estate-backend/worker"
+)
+
+// listing events reuse the outbox event names
+const (
+	webhookEventAssetUpdated    = db.EventAssetUpdated
+	webhookEventInquiryReceived = "inquiry.received"
+)
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=asset.created asset.updated asset.sold asset.deleted inquiry.received"`
+	// subscribe for the agency the user administers instead of the user alone
+	Agency bool `json:"agency"`
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string  `json:"url" validate:"omitempty,url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"omitempty,min=1,dive,oneof=asset.created asset.updated asset.sold asset.deleted inquiry.received"`
+	Active     *bool    `json:"active"`
+}
+
+type WebhookResponse struct {
+	ID         int64    `json:"id"`
+	Username   *string  `json:"username"`
+	AgencyID   *int64   `json:"agency_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	// only returned when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int64      `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int32      `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode *int32     `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	RedeliveryOf   *int64     `json:"redelivery_of"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookAttemptResponse struct {
+	Attempt    int32     `json:"attempt"`
+	StatusCode *int32    `json:"status_code"`
+	Error      *string   `json:"error"`
+	DurationMs int32     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// webhookEvent is the JSON body posted to subscribers.
+type webhookEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+func newWebhookResponse(subscription db.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:         subscription.ID,
+		Username:   nullString(subscription.Username),
+		AgencyID:   nullInt64(subscription.AgencyID),
+		URL:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedBy:  subscription.CreatedBy,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery db.WebhookDelivery) WebhookDeliveryResponse {
+	rsp := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: nullInt32(delivery.LastStatusCode),
+		LastError:      nullString(delivery.LastError),
+		RedeliveryOf:   nullInt64(delivery.RedeliveryOf),
+		DeliveredAt:    nullTime(delivery.DeliveredAt),
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == db.WebhookDeliveryStatusPending {
+		rsp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return rsp
+}
+
+func newWebhookDeliveryResponses(deliveries []db.WebhookDelivery) []WebhookDeliveryResponse {
+	rsp := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		rsp = append(rsp, newWebhookDeliveryResponse(delivery))
+	}
+	return rsp
+}
+
+func newWebhookAttemptResponses(attempts []db.WebhookDeliveryAttempt) []WebhookAttemptResponse {
+	rsp := make([]WebhookAttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		rsp = append(rsp, WebhookAttemptResponse{
+			Attempt:    attempt.Attempt,
+			StatusCode: nullInt32(attempt.StatusCode),
+			Error:      nullString(attempt.Error),
+			DurationMs: attempt.DurationMs,
+			CreatedAt:  attempt.CreatedAt,
+		})
+	}
+	return rsp
+}
+
+func randomToken(prefix string, n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return prefix + hex.EncodeToString(b)
+}
+
+// emitWebhook queues an event for every active subscription of the owner or
+// of the agency handling the listing. The delivery worker sends them later,
+// so a failing subscriber never slows down the caller. Queueing the same
+// event id twice for a subscription is a no-op.
+func (server *Server) emitWebhook(ctx context.Context, event webhookEvent, owner string, agencyId sql.NullInt64) error {
+	subscriptions, err := server.store.GetWebhookSubscriptionsForEvent(ctx, db.GetWebhookSubscriptionsForEventParams{
+		EventType: event.Type,
+		Owner:     sql.NullString{String: owner, Valid: true},
+		AgencyID:  agencyId,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get webhook subscriptions: %w", err)
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	payload, err := sonic.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("cannot encode webhook event: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		err := server.store.QueueWebhookDelivery(ctx, db.QueueWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot queue webhook delivery for subscription %d: %w", subscription.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterOutboxSubscribers lets the server react to the domain events
+// relayed from the outbox.
+func (server *Server) RegisterOutboxSubscribers(relay *worker.OutboxRelay) {
+	relay.Subscribe("webhooks", server.relayWebhooks,
+		db.EventAssetCreated, db.EventAssetUpdated, db.EventAssetSold, db.EventAssetDeleted,
+		db.EventContactCreated, db.EventContactUpdated, db.EventContactDeleted,
+	)
+}
+
+// relayWebhooks turns listing and contact events into webhook deliveries.
+// The outbox idempotency key becomes the webhook event id, so an event the
+// relay hands over twice is only delivered once.
+func (server *Server) relayWebhooks(ctx context.Context, event db.OutboxEvent) error {
+	webhook := webhookEvent{
+		ID:        "evt_" + event.IdempotencyKey,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+	}
+
+	var assetId int64
+	switch event.EventType {
+	case db.EventAssetDeleted:
+		var asset db.GetAssetByIdRow
+		if err := json.Unmarshal(event.Payload, &asset); err != nil {
+			return err
+		}
+
+		webhook.Data = newAssetResponse(asset)
+		return server.emitWebhook(ctx, webhook, asset.Owner, asset.AgencyID)
+	case db.EventContactCreated, db.EventContactUpdated, db.EventContactDeleted:
+		// subscribers see contact changes as an update of the listing
+		var contact struct {
+			AssetID int64 `json:"asset_id"`
+		}
+		if err := json.Unmarshal(event.Payload, &contact); err != nil {
+			return err
+		}
+
+		webhook.Type = webhookEventAssetUpdated
+		assetId = contact.AssetID
+	default:
+		id, err := strconv.ParseInt(event.AggregateID, 10, 64)
+		if err != nil {
+			return err
+		}
+		assetId = id
+	}
+
+	asset, err := server.store.GetAssetById(ctx, assetId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// deleted since, its own event tells the subscribers
+			return nil
+		}
+		return err
+	}
+
+	webhook.Data = newAssetResponse(asset)
+	return server.emitWebhook(ctx, webhook, asset.Owner, asset.AgencyID)
+}
+
+// emitInquiryReceived tells the seller's subscribers about a buyer's message.
+// Replies from the seller are not sent out.
+func (server *Server) emitInquiryReceived(ctx context.Context, result db.SendInquiryMessageTxResult) {
+	if result.Message.Sender != result.Thread.Buyer {
+		return
+	}
+
+	var agencyId sql.NullInt64
+	if asset, err := server.store.GetAssetById(ctx, result.Thread.AssetID); err == nil {
+		agencyId = asset.AgencyID
+	}
+
+	event := webhookEvent{
+		ID:        randomToken("evt_", 16),
+		Type:      webhookEventInquiryReceived,
+		CreatedAt: result.Message.CreatedAt,
+		Data: fiber.Map{
+			"thread_id":  result.Thread.ID,
+			"asset_id":   result.Thread.AssetID,
+			"buyer":      result.Thread.Buyer,
+			"message_id": result.Message.ID,
+			"message":    result.Message.Body,
+			"sent_at":    result.Message.CreatedAt,
+		},
+	}
+
+	if err := server.emitWebhook(ctx, event, result.Thread.Seller, agencyId); err != nil {
+		log.Printf("cannot emit %s webhook: %v", event.Type, err)
+	}
+}
+
+// getWebhookSubscription loads :webhook_id for its owner or an admin of its agency.
+func (server *Server) getWebhookSubscription(c *fiber.Ctx) (db.WebhookSubscription, error) {
+	user := c.Locals("user").(db.User)
+
+	webhookId, err := strconv.Atoi(c.Params("webhook_id"))
+	if err != nil {
+		return db.WebhookSubscription{}, fiber.NewError(fiber.StatusBadRequest, "invalid webhook_id.")
+	}
+
+	subscription, err := server.store.GetWebhookSubscription(c.Context(), int64(webhookId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return subscription, fiber.NewError(fiber.StatusNotFound, "webhook not found.")
+		}
+
+		return subscription, fiber.NewError(fiber.StatusInternalServerError, "cannot get webhook.")
+	}
+
+	if subscription.Username.Valid && subscription.Username.String == user.Username {
+		return subscription, nil
+	}
+
+	if subscription.AgencyID.Valid {
+		member, err := server.store.GetAgencyMembership(c.Context(), user.Username)
+		if err == nil && member.AgencyID == subscription.AgencyID.Int64 && member.Role == db.AgencyRoleAdmin {
+			return subscription, nil
+		}
+	}
+
+	return subscription, fiber.NewError(fiber.StatusNotFound, "webhook not found.")
+}
+
+// getWebhookDelivery loads :delivery_id of the subscription.
+func (server *Server) getWebhookDelivery(c *fiber.Ctx, subscription db.WebhookSubscription) (db.WebhookDelivery, error) {
+	deliveryId, err := strconv.Atoi(c.Params("delivery_id"))
+	if err != nil {
+		return db.WebhookDelivery{}, fiber.NewError(fiber.StatusBadRequest, "invalid delivery_id.")
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(c.Context(), int64(deliveryId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return delivery, fiber.NewError(fiber.StatusNotFound, "delivery not found.")
+		}
+
+		return delivery, fiber.NewError(fiber.StatusInternalServerError, "cannot get delivery.")
+	}
+
+	if delivery.SubscriptionID != subscription.ID {
+		return delivery, fiber.NewError(fiber.StatusNotFound, "delivery not found.")
+	}
+
+	return delivery, nil
+}
+
+func (server *Server) MyWebhooks(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	arg := db.ListWebhookSubscriptionsParams{
+		Username: sql.NullString{String: user.Username, Valid: true},
+	}
+
+	member, err := server.store.GetAgencyMembership(c.Context(), user.Username)
+	if err == nil && member.Role == db.AgencyRoleAdmin {
+		arg.AgencyID = sql.NullInt64{Int64: member.AgencyID, Valid: true}
+	} else if err != nil && err != sql.ErrNoRows {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get agency membership.")
+	}
+
+	subscriptions, err := server.store.ListWebhookSubscriptions(c.Context(), arg)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get webhooks.")
+	}
+
+	rsp := make([]WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		rsp = append(rsp, newWebhookResponse(subscription))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rsp)
+}
+
+func (server *Server) CreateWebhook(c *fiber.Ctx) error {
+	user := c.Locals("user").(db.User)
+
+	var req CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	if err := safehttp.CheckURL(c.Context(), req.URL); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "url must be a public http or https address.")
+	}
+
+	arg := db.CreateWebhookSubscriptionParams{
+		Url:        req.URL,
+		Secret:     randomToken("whsec_", 24),
+		EventTypes: req.EventTypes,
+		CreatedBy:  user.Username,
+	}
+
+	if req.Agency {
+		member, err := server.getAgencyAdmin(c)
+		if err != nil {
+			return err
+		}
+		arg.AgencyID = sql.NullInt64{Int64: member.AgencyID, Valid: true}
+	} else {
+		arg.Username = sql.NullString{String: user.Username, Valid: true}
+	}
+
+	subscription, err := server.store.CreateWebhookSubscription(c.Context(), arg)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "create webhook failed.")
+	}
+
+	rsp := newWebhookResponse(subscription)
+	rsp.Secret = subscription.Secret
+
+	return c.Status(fiber.StatusCreated).JSON(rsp)
+}
+
+func (server *Server) UpdateWebhook(c *fiber.Ctx) error {
+	subscription, err := server.getWebhookSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return err
+	}
+
+	arg := db.UpdateWebhookSubscriptionParams{
+		ID:         subscription.ID,
+		Url:        subscription.Url,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+	}
+	if req.URL != nil {
+		if err := safehttp.CheckURL(c.Context(), *req.URL); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "url must be a public http or https address.")
+		}
+		arg.Url = *req.URL
+	}
+	if req.EventTypes != nil {
+		arg.EventTypes = req.EventTypes
+	}
+	if req.Active != nil {
+		arg.Active = *req.Active
+	}
+
+	subscription, err = server.store.UpdateWebhookSubscriptionTx(c.Context(), arg)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "update webhook failed.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newWebhookResponse(subscription))
+}
+
+func (server *Server) DeleteWebhook(c *fiber.Ctx) error {
+	subscription, err := server.getWebhookSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	if err := server.store.DeleteWebhookSubscription(c.Context(), subscription.ID); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "delete webhook failed.")
+	}
+
+	return okResponse(c, "delete webhook successfully.")
+}
+
+func (server *Server) WebhookDeliveries(c *fiber.Ctx) error {
+	subscription, err := server.getWebhookSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit := 20
+	offset := (page - 1) * limit
+
+	deliveries, err := server.store.ListWebhookDeliveries(c.Context(), db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Limit:          int32(limit),
+		Offset:         int32(offset),
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get deliveries.")
+	}
+
+	total, err := server.store.CountWebhookDeliveries(c.Context(), subscription.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot count deliveries.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"deliveries": newWebhookDeliveryResponses(deliveries),
+		"page":       page,
+		"limit":      limit,
+		"total":      total,
+	})
+}
+
+// GetWebhookDelivery returns a delivery with its payload and every attempt
+// made to send it.
+func (server *Server) GetWebhookDelivery(c *fiber.Ctx) error {
+	subscription, err := server.getWebhookSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := server.getWebhookDelivery(c, subscription)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := server.store.GetWebhookDeliveryAttempts(c.Context(), delivery.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "cannot get delivery attempts.")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"delivery": newWebhookDeliveryResponse(delivery),
+		"payload":  delivery.Payload,
+		"attempts": newWebhookAttemptResponses(attempts),
+	})
+}
+
+// RedeliverWebhook queues the same event again as a new delivery. The event
+// id is kept so subscribers can tell it is a repeat.
+func (server *Server) RedeliverWebhook(c *fiber.Ctx) error {
+	subscription, err := server.getWebhookSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := server.getWebhookDelivery(c, subscription)
+	if err != nil {
+		return err
+	}
+
+	if delivery.Status == db.WebhookDeliveryStatusPending {
+		return fiber.NewError(fiber.StatusConflict, "delivery is still pending.")
+	}
+	if !subscription.Active {
+		return fiber.NewError(fiber.StatusConflict, "webhook is not active.")
+	}
+
+	redelivery, err := server.store.CreateWebhookDelivery(c.Context(), db.CreateWebhookDeliveryParams{
+		SubscriptionID: subscription.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		RedeliveryOf:   sql.NullInt64{Int64: delivery.ID, Valid: true},
+	})
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "redeliver webhook failed.")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newWebhookDeliveryResponse(redelivery))
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+func TestCreateWebhookRejectsPrivateURLs(t *testing.T) {
+	server, fake := newTestServer(t, util.Config{})
+	cookie := login(t, server, fake, testUser(db.UserRoleUser))
+
+	for _, url := range []string{
+		"http://127.0.0.1:6379/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://192.168.1.1/admin",
+		"http://[fd00::1]/hook",
+	} {
+		body := `{"url":"` + url + `","event_types":["asset.created"]}`
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookie)
+
+		// no handler for CreateWebhookSubscription, the request must not get that far
+		do(t, server, req, http.StatusBadRequest, nil)
+	}
+}
@@ -0,0 +1,161 @@
+// Package currency converts listing prices between currencies. Rates are kept
+// against the baht as exact decimals and amounts are integers in minor units,
+// so a conversion never goes through floating point.
+package currency
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Base is the currency every rate is quoted against.
+const Base = "THB"
+
+// BaseMinorUnits is the decimals of the base currency; prices in baht, like
+// the listing price filters, are in satang.
+const BaseMinorUnits = 2
+
+// the exchange_rates.rate column is numeric(20,10)
+const rateDecimals = 10
+
+var (
+	ErrInvalidCode       = errors.New("currency must be a three letter ISO 4217 code")
+	ErrInvalidRate       = errors.New("rate must be a positive decimal with at most 10 digits after the point")
+	ErrInvalidMinorUnits = errors.New("minor_units must be between 0 and 4")
+	ErrBaseRate          = errors.New("THB is the base currency, its rate is always 1 with 2 minor units")
+	ErrInvalidAmount     = errors.New("amount must be a decimal with no more decimals than the currency has")
+)
+
+var (
+	codePattern   = regexp.MustCompile(`^[A-Z]{3}$`)
+	ratePattern   = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,10})?$`)
+	amountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+)
+
+// Rate is how many baht one unit of Currency buys.
+type Rate struct {
+	Currency string
+	PerUnit  *big.Rat
+	// decimals the currency is written with, 2 for USD and 0 for JPY
+	MinorUnits int32
+}
+
+// NewRate checks a rate the way the exchange_rates table stores it.
+func NewRate(code, rate string, minorUnits int32) (Rate, error) {
+	code = NormalizeCode(code)
+	if !codePattern.MatchString(code) {
+		return Rate{}, ErrInvalidCode
+	}
+
+	perUnit, err := ParseRate(rate)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	if minorUnits < 0 || minorUnits > 4 {
+		return Rate{}, ErrInvalidMinorUnits
+	}
+
+	if code == Base && (perUnit.Cmp(big.NewRat(1, 1)) != 0 || minorUnits != BaseMinorUnits) {
+		return Rate{}, ErrBaseRate
+	}
+
+	return Rate{Currency: code, PerUnit: perUnit, MinorUnits: minorUnits}, nil
+}
+
+// NormalizeCode upper-cases a currency code from a query string or a file.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidCode reports whether code looks like an ISO 4217 code.
+func ValidCode(code string) bool {
+	return codePattern.MatchString(code)
+}
+
+// ParseRate reads a decimal like "35.125".
+func ParseRate(s string) (*big.Rat, error) {
+	s = strings.TrimSpace(s)
+	if !ratePattern.MatchString(s) {
+		return nil, ErrInvalidRate
+	}
+
+	rate, ok := new(big.Rat).SetString(s)
+	if !ok || rate.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return rate, nil
+}
+
+// String writes the rate without trailing zeros, e.g. "35.125".
+func (rate Rate) String() string {
+	s := rate.PerUnit.FloatString(rateDecimals)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// ToMinor turns a price in whole units into minor units. ok is false when the
+// result does not fit in an int64.
+func ToMinor(amount int64, minorUnits int32) (minor int64, ok bool) {
+	r := new(big.Int).Mul(big.NewInt(amount), pow10(minorUnits))
+	if !r.IsInt64() {
+		return 0, false
+	}
+	return r.Int64(), true
+}
+
+// ParseAmount reads a price like "1234.50" into minor units. It has at most
+// minorUnits decimals, so "1234.505" is not a USD price.
+func ParseAmount(s string, minorUnits int32) (int64, error) {
+	if !amountPattern.MatchString(s) {
+		return 0, ErrInvalidAmount
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > int(minorUnits) {
+		return 0, ErrInvalidAmount
+	}
+
+	r, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", int(minorUnits)-len(fraction)), 10)
+	if !ok || !r.IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+	return r.Int64(), nil
+}
+
+// FormatAmount writes minor units as a decimal, e.g. 123450 with 2 minor
+// units as "1234.50".
+func FormatAmount(amount int64, minorUnits int32) string {
+	return new(big.Rat).SetFrac(big.NewInt(amount), pow10(minorUnits)).FloatString(int(minorUnits))
+}
+
+// Convert converts amount, in minor units of from, into minor units of to,
+// rounding half away from zero. ok is false when the result does not fit in
+// an int64.
+func Convert(amount int64, from, to Rate) (converted int64, ok bool) {
+	r := new(big.Rat).SetInt64(amount)
+	r.Mul(r, from.PerUnit)
+	r.Quo(r, to.PerUnit)
+	r.Mul(r, new(big.Rat).SetFrac(pow10(to.MinorUnits), pow10(from.MinorUnits)))
+	return round(r)
+}
+
+func round(r *big.Rat) (int64, bool) {
+	quo, rem := new(big.Int).QuoRem(new(big.Int).Abs(r.Num()), r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	if !quo.IsInt64() {
+		return 0, false
+	}
+	return quo.Int64(), true
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
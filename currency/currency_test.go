@@ -0,0 +1,204 @@
+package currency
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func mustRate(t *testing.T, code, rate string, minorUnits int32) Rate {
+	t.Helper()
+
+	r, err := NewRate(code, rate, minorUnits)
+	if err != nil {
+		t.Fatalf("NewRate(%s, %s, %d): %v", code, rate, minorUnits, err)
+	}
+	return r
+}
+
+func TestConvert(t *testing.T) {
+	thb := mustRate(t, "THB", "1", 2)
+	usd := mustRate(t, "USD", "35", 2)
+	jpy := mustRate(t, "JPY", "0.25", 0)
+	// a made up currency where one satang is half a cent
+	half := mustRate(t, "XTS", "2", 2)
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to Rate
+		want     int64
+	}{
+		{"same currency", 123450, usd, usd, 123450},
+		{"baht to dollars", 350000, thb, usd, 10000},
+		{"dollars to baht", 123450, usd, thb, 4320750},
+		{"rounds down below a half", 17, thb, usd, 0},
+		{"rounds up from a half", 18, thb, usd, 1},
+		{"half goes up", 1, thb, half, 1},
+		{"one and a half goes up", 3, thb, half, 2},
+		{"negative half goes away from zero", -1, thb, half, -1},
+		{"negative one and a half", -3, thb, half, -2},
+		{"negative below a half", -17, thb, usd, 0},
+		{"baht to yen", 10000, thb, jpy, 400},
+		{"yen drops fractions", 10, thb, jpy, 0},
+		{"yen rounds half up", 13, thb, jpy, 1},
+		{"yen to baht", 1, jpy, thb, 25},
+		{"zero", 0, usd, jpy, 0},
+	}
+
+	for _, tt := range tests {
+		got, ok := Convert(tt.amount, tt.from, tt.to)
+		if !ok {
+			t.Errorf("%s: Convert(%d) overflowed", tt.name, tt.amount)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Convert(%d, %s, %s) = %d, want %d", tt.name, tt.amount, tt.from.Currency, tt.to.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestConvertOverflow(t *testing.T) {
+	thb := mustRate(t, "THB", "1", 2)
+	tiny := mustRate(t, "XTS", "0.0000000001", 4)
+
+	for _, amount := range []int64{math.MaxInt64, math.MinInt64} {
+		if got, ok := Convert(amount, thb, tiny); ok {
+			t.Errorf("Convert(%d) = %d, want an overflow", amount, got)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	huge, _ := new(big.Rat).SetString("9223372036854775808")
+
+	tests := []struct {
+		r    *big.Rat
+		want int64
+		ok   bool
+	}{
+		{big.NewRat(0, 1), 0, true},
+		{big.NewRat(7, 1), 7, true},
+		{big.NewRat(1, 2), 1, true},
+		{big.NewRat(5, 2), 3, true},
+		{big.NewRat(-1, 2), -1, true},
+		{big.NewRat(-5, 2), -3, true},
+		{big.NewRat(7, 3), 2, true},
+		{big.NewRat(-7, 3), -2, true},
+		{big.NewRat(8, 3), 3, true},
+		{big.NewRat(-8, 3), -3, true},
+		{big.NewRat(math.MaxInt64, 1), math.MaxInt64, true},
+		{huge, 0, false},
+		{new(big.Rat).Neg(huge), math.MinInt64, true},
+		// rounds up past the largest int64
+		{new(big.Rat).Add(big.NewRat(math.MaxInt64, 1), big.NewRat(1, 2)), 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := round(tt.r)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("round(%s) = %d, %t, want %d, %t", tt.r, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestToMinor(t *testing.T) {
+	tests := []struct {
+		amount     int64
+		minorUnits int32
+		want       int64
+		ok         bool
+	}{
+		{1234, 2, 123400, true},
+		{1234, 0, 1234, true},
+		{-5, 2, -500, true},
+		{math.MaxInt64 / 100, 2, math.MaxInt64 / 100 * 100, true},
+		{math.MaxInt64/100 + 1, 2, 0, false},
+		{math.MinInt64 / 10, 2, 0, false},
+		{math.MaxInt64, 4, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ToMinor(tt.amount, tt.minorUnits)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ToMinor(%d, %d) = %d, %t, want %d, %t", tt.amount, tt.minorUnits, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		s          string
+		minorUnits int32
+		want       int64
+	}{
+		{"1234.50", 2, 123450},
+		{"1234.5", 2, 123450},
+		{"1234", 2, 123400},
+		{"0.05", 2, 5},
+		{"1234", 0, 1234},
+		{"9223372036854775807", 0, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.s, tt.minorUnits)
+		if err != nil {
+			t.Errorf("ParseAmount(%q, %d): %v", tt.s, tt.minorUnits, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q, %d) = %d, want %d", tt.s, tt.minorUnits, got, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		s          string
+		minorUnits int32
+	}{
+		{"", 2},
+		{"-1", 2},
+		{"1e3", 2},
+		{"1.", 2},
+		{".5", 2},
+		{"1.234", 2},
+		{"1.5", 0},
+		{"92233720368547758.08", 2},
+	}
+	for _, tt := range invalid {
+		if got, err := ParseAmount(tt.s, tt.minorUnits); err == nil {
+			t.Errorf("ParseAmount(%q, %d) = %d, want an error", tt.s, tt.minorUnits, got)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount     int64
+		minorUnits int32
+		want       string
+	}{
+		{123450, 2, "1234.50"},
+		{5, 2, "0.05"},
+		{-5, 2, "-0.05"},
+		{1234, 0, "1234"},
+		{12345, 3, "12.345"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.minorUnits); got != tt.want {
+			t.Errorf("FormatAmount(%d, %d) = %q, want %q", tt.amount, tt.minorUnits, got, tt.want)
+		}
+	}
+}
+
+func TestNewRateBase(t *testing.T) {
+	if _, err := NewRate("THB", "1", 2); err != nil {
+		t.Errorf("NewRate(THB, 1, 2): %v", err)
+	}
+	if _, err := NewRate("THB", "1.5", 2); err != ErrBaseRate {
+		t.Errorf("NewRate(THB, 1.5, 2) = %v, want %v", err, ErrBaseRate)
+	}
+	if _, err := NewRate("THB", "1", 0); err != ErrBaseRate {
+		t.Errorf("NewRate(THB, 1, 0) = %v, want %v", err, ErrBaseRate)
+	}
+}
@@ -0,0 +1,143 @@
+package currency
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const defaultMinorUnits = 2
+
+var ErrNoRates = errors.New("file has no exchange rates")
+
+// ReadRates reads a rates file. It is either a CSV file with the columns
+// currency, rate and an optional minor_units:
+//
+//	currency,rate,minor_units
+//	USD,35.12,2
+//	JPY,0.2381,0
+//
+// or a JSON array of objects with the same fields. minor_units defaults to 2.
+func ReadRates(r io.Reader) ([]Rate, error) {
+	br := bufio.NewReader(r)
+
+	// skip a byte order mark, spreadsheets like to add one
+	if b, err := br.Peek(3); err == nil && string(b) == "\ufeff" {
+		br.Discard(3)
+	}
+
+	var (
+		rates []Rate
+		err   error
+	)
+	if first, _ := firstByte(br); first == '[' {
+		rates, err = readJSON(br)
+	} else {
+		rates, err = readCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rates) == 0 {
+		return nil, ErrNoRates
+	}
+
+	seen := make(map[string]bool, len(rates))
+	for _, rate := range rates {
+		if seen[rate.Currency] {
+			return nil, fmt.Errorf("%s is listed more than once", rate.Currency)
+		}
+		seen[rate.Currency] = true
+	}
+
+	return rates, nil
+}
+
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func readCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rates []Rate
+	for header := true; ; header = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if header && strings.EqualFold(strings.TrimSpace(record[0]), "currency") {
+			continue
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected currency, rate and minor_units", line)
+		}
+
+		minorUnits := int64(defaultMinorUnits)
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			minorUnits, err = strconv.ParseInt(strings.TrimSpace(record[2]), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, ErrInvalidMinorUnits)
+			}
+		}
+
+		rate, err := NewRate(record[0], record[1], int32(minorUnits))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+type jsonRate struct {
+	Currency   string      `json:"currency"`
+	Rate       json.Number `json:"rate"`
+	MinorUnits *int32      `json:"minor_units"`
+}
+
+func readJSON(r io.Reader) ([]Rate, error) {
+	var entries []jsonRate
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	rates := make([]Rate, 0, len(entries))
+	for i, entry := range entries {
+		minorUnits := int32(defaultMinorUnits)
+		if entry.MinorUnits != nil {
+			minorUnits = *entry.MinorUnits
+		}
+
+		rate, err := NewRate(entry.Currency, entry.Rate.String(), minorUnits)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
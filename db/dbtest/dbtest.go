@@ -0,0 +1,216 @@
+// Package dbtest answers the queries sqlc generates with handlers written in
+// Go, so code built on db.Store can be tested without a Postgres server.
+// Queries are matched by their sqlc name; a query without a handler fails
+// the test.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// Result is what a handler returns: rows for queries and RowsAffected for
+// statements. Row values are scanned in the order the generated code lists
+// its columns.
+type Result struct {
+	Rows         [][]driver.Value
+	RowsAffected int64
+}
+
+// Handler answers one query. args are the query's parameters after the
+// database/sql conversions, e.g. sql.NullString becomes a string or nil.
+type Handler func(args []driver.Value) (Result, error)
+
+// TxHooks are called when a transaction begins, commits or rolls back, so a
+// fake can undo the writes of a rolled back transaction.
+type TxHooks struct {
+	Begin    func()
+	Commit   func()
+	Rollback func()
+}
+
+type DB struct {
+	t        testing.TB
+	mu       sync.Mutex
+	handlers map[string]Handler
+	hooks    TxHooks
+	calls    []string
+}
+
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// New returns a fake and the *sql.DB that runs queries against it.
+func New(t testing.TB) (*DB, *sql.DB) {
+	fake := &DB{t: t, handlers: map[string]Handler{}}
+	conn := sql.OpenDB(connector{fake})
+	t.Cleanup(func() { conn.Close() })
+	return fake, conn
+}
+
+// Handle sets the handler of the query called name.
+func (fake *DB) Handle(name string, h Handler) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.handlers[name] = h
+}
+
+// Hooks sets what happens when transactions begin and end.
+func (fake *DB) Hooks(hooks TxHooks) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.hooks = hooks
+}
+
+// Calls lists the queries run so far by name, along with BEGIN, COMMIT and
+// ROLLBACK.
+func (fake *DB) Calls() []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return append([]string(nil), fake.calls...)
+}
+
+// Row is a shorthand for a result with a single row.
+func Row(values ...driver.Value) Result {
+	return Result{Rows: [][]driver.Value{values}}
+}
+
+func (fake *DB) run(query string, args []driver.NamedValue) (Result, error) {
+	m := queryName.FindStringSubmatch(query)
+	if m == nil {
+		fake.t.Errorf("dbtest: query without a sqlc name: %s", query)
+		return Result{}, fmt.Errorf("dbtest: unnamed query")
+	}
+
+	fake.mu.Lock()
+	h, ok := fake.handlers[m[1]]
+	fake.calls = append(fake.calls, m[1])
+	fake.mu.Unlock()
+
+	if !ok {
+		fake.t.Errorf("dbtest: no handler for %s", m[1])
+		return Result{}, fmt.Errorf("dbtest: no handler for %s", m[1])
+	}
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return h(values)
+}
+
+func (fake *DB) txEvent(name string, hook func()) {
+	fake.mu.Lock()
+	fake.calls = append(fake.calls, name)
+	fake.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+}
+
+type connector struct{ fake *DB }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return &conn{fake: c.fake}, nil }
+func (c connector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, fmt.Errorf("dbtest: use dbtest.New")
+}
+
+type conn struct{ fake *DB }
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("dbtest: prepared statements are not supported")
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.fake.txEvent("BEGIN", c.fake.hooks.Begin)
+	return tx{c.fake}, nil
+}
+
+// CheckNamedValue lets every argument through; the generated code only
+// passes values database/sql already knows how to convert.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if valuer, ok := nv.Value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		nv.Value = v
+		return nil
+	}
+
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = v
+	return nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.fake.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &rows{values: result.Rows}, nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.fake.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(result.RowsAffected), nil
+}
+
+type tx struct{ fake *DB }
+
+func (t tx) Commit() error {
+	t.fake.txEvent("COMMIT", t.fake.hooks.Commit)
+	return nil
+}
+
+func (t tx) Rollback() error {
+	t.fake.txEvent("ROLLBACK", t.fake.hooks.Rollback)
+	return nil
+}
+
+type rows struct {
+	values [][]driver.Value
+	next   int
+}
+
+// Columns only has to have the right length, the generated code scans by
+// position.
+func (r *rows) Columns() []string {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return make([]string, len(r.values[0]))
+}
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
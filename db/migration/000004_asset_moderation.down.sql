@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS asset_moderation_logs;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_trusted;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS moderation_updated_at;
+ALTER TABLE assets DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE assets DROP COLUMN IF EXISTS moderation_reason;
+ALTER TABLE assets DROP COLUMN IF EXISTS moderation_status;
+
+DROP TYPE IF EXISTS moderation_status;
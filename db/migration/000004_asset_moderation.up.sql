@@ -0,0 +1,30 @@
+CREATE TYPE moderation_status AS ENUM ('pending', 'approved', 'rejected');
+
+ALTER TABLE "assets" ADD COLUMN "moderation_status" moderation_status NOT NULL DEFAULT 'pending';
+ALTER TABLE "assets" ADD COLUMN "moderation_reason" text;
+ALTER TABLE "assets" ADD COLUMN "moderated_by" varchar;
+ALTER TABLE "assets" ADD COLUMN "moderation_updated_at" timestamptz NOT NULL DEFAULT (now());
+
+-- listings that were already live before moderation existed stay live
+UPDATE "assets" SET "moderation_status" = 'approved';
+
+ALTER TABLE "assets" ADD FOREIGN KEY ("moderated_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "assets" ("moderation_status", "moderation_updated_at");
+
+ALTER TABLE "users" ADD COLUMN "is_trusted" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE "asset_moderation_logs" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "moderator" varchar,
+  "status" moderation_status NOT NULL,
+  "reason" text,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "asset_moderation_logs" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "asset_moderation_logs" ADD FOREIGN KEY ("moderator") REFERENCES "users" ("username");
+
+CREATE INDEX ON "asset_moderation_logs" ("asset_id");
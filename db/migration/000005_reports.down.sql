@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reports;
+
+DROP TYPE IF EXISTS report_status;
+DROP TYPE IF EXISTS report_category;
+DROP TYPE IF EXISTS report_target;
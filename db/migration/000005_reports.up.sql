@@ -0,0 +1,39 @@
+CREATE TYPE report_target AS ENUM ('asset', 'user');
+
+CREATE TYPE report_category AS ENUM ('scam', 'duplicate', 'sold', 'abusive', 'other');
+
+CREATE TYPE report_status AS ENUM ('open', 'assigned', 'resolved', 'dismissed');
+
+CREATE TABLE "reports" (
+  "id" bigserial PRIMARY KEY,
+  "reporter" varchar NOT NULL,
+  "target_type" report_target NOT NULL,
+  "asset_id" bigint,
+  "reported_user" varchar,
+  "category" report_category NOT NULL,
+  "comment" text NOT NULL DEFAULT '',
+  "status" report_status NOT NULL DEFAULT 'open',
+  "assignee" varchar,
+  "resolution" text,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK (
+    ("target_type" = 'asset' AND "asset_id" IS NOT NULL) OR
+    ("target_type" = 'user' AND "reported_user" IS NOT NULL)
+  )
+);
+
+ALTER TABLE "reports" ADD FOREIGN KEY ("reporter") REFERENCES "users" ("username");
+
+ALTER TABLE "reports" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "reports" ADD FOREIGN KEY ("reported_user") REFERENCES "users" ("username");
+
+ALTER TABLE "reports" ADD FOREIGN KEY ("assignee") REFERENCES "users" ("username");
+
+CREATE INDEX ON "reports" ("status", "created_at");
+
+-- one open report per reporter and target, so a single user can't trip the auto-hide threshold
+CREATE UNIQUE INDEX ON "reports" ("reporter", "asset_id") WHERE "asset_id" IS NOT NULL AND "status" IN ('open', 'assigned');
+
+CREATE UNIQUE INDEX ON "reports" ("reporter", "reported_user") WHERE "reported_user" IS NOT NULL AND "status" IN ('open', 'assigned');
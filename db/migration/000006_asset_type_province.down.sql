@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS users_created_at_idx;
+DROP INDEX IF EXISTS assets_created_at_idx;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS province;
+ALTER TABLE assets DROP COLUMN IF EXISTS property_type;
+
+DROP TYPE IF EXISTS property_type;
@@ -0,0 +1,8 @@
+CREATE TYPE property_type AS ENUM ('house', 'condo', 'townhouse', 'land', 'commercial', 'other');
+
+ALTER TABLE "assets" ADD COLUMN "property_type" property_type NOT NULL DEFAULT 'other';
+ALTER TABLE "assets" ADD COLUMN "province" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "assets" ("property_type", "province");
+CREATE INDEX ON "assets" ("created_at");
+CREATE INDEX ON "users" ("created_at");
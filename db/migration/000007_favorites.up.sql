@@ -0,0 +1,12 @@
+CREATE TABLE "favorites" (
+  "username" varchar NOT NULL,
+  "asset_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("username", "asset_id")
+);
+
+ALTER TABLE "favorites" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "favorites" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "favorites" ("asset_id");
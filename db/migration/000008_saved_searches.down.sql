@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS saved_search_notifications;
+DROP TABLE IF EXISTS saved_searches;
+
+DROP TYPE IF EXISTS notification_channel;
+DROP TYPE IF EXISTS alert_frequency;
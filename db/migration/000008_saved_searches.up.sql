@@ -0,0 +1,49 @@
+CREATE TYPE alert_frequency AS ENUM ('instant', 'daily', 'weekly');
+
+CREATE TYPE notification_channel AS ENUM ('in_app', 'email', 'webhook');
+
+CREATE TABLE "saved_searches" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "filters" jsonb NOT NULL DEFAULT '{}',
+  "frequency" alert_frequency NOT NULL DEFAULT 'daily',
+  "channel" notification_channel NOT NULL DEFAULT 'in_app',
+  "webhook_url" varchar,
+  "last_checked_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "saved_searches" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "saved_searches" ("username");
+CREATE INDEX ON "saved_searches" ("frequency", "last_checked_at");
+
+-- what each saved search already told its owner about, and at which price
+CREATE TABLE "saved_search_notifications" (
+  "saved_search_id" bigint NOT NULL,
+  "asset_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "notified_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("saved_search_id", "asset_id")
+);
+
+ALTER TABLE "saved_search_notifications" ADD FOREIGN KEY ("saved_search_id") REFERENCES "saved_searches" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "saved_search_notifications" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "title" varchar NOT NULL,
+  "body" text NOT NULL,
+  "data" jsonb NOT NULL DEFAULT '{}',
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "notifications" ("username", "created_at");
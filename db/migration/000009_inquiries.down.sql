@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS inquiry_messages;
+DROP TABLE IF EXISTS inquiry_threads;
@@ -0,0 +1,36 @@
+CREATE TABLE "inquiry_threads" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "buyer" varchar NOT NULL,
+  "seller" varchar NOT NULL,
+  "seller_replied_at" timestamptz,
+  "last_message_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "inquiry_threads" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "inquiry_threads" ADD FOREIGN KEY ("buyer") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "inquiry_threads" ADD FOREIGN KEY ("seller") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+-- one conversation per buyer and asset
+CREATE UNIQUE INDEX ON "inquiry_threads" ("asset_id", "buyer");
+CREATE INDEX ON "inquiry_threads" ("buyer", "last_message_at");
+CREATE INDEX ON "inquiry_threads" ("seller", "last_message_at");
+
+CREATE TABLE "inquiry_messages" (
+  "id" bigserial PRIMARY KEY,
+  "thread_id" bigint NOT NULL,
+  "sender" varchar NOT NULL,
+  "body" text NOT NULL,
+  "read_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "inquiry_messages" ADD FOREIGN KEY ("thread_id") REFERENCES "inquiry_threads" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "inquiry_messages" ADD FOREIGN KEY ("sender") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "inquiry_messages" ("thread_id", "id");
+CREATE INDEX ON "inquiry_messages" ("thread_id") WHERE "read_at" IS NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS notifications_notify ON notifications;
+DROP FUNCTION IF EXISTS notify_new_notification();
+DROP INDEX IF EXISTS notifications_username_idx;
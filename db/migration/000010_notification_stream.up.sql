@@ -0,0 +1,14 @@
+CREATE INDEX ON "notifications" ("username") WHERE "read_at" IS NULL;
+
+-- announce new notifications so every API instance can push them to its
+-- connected clients; the payload stays small, listeners load the row by id
+CREATE FUNCTION notify_new_notification() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('notifications', json_build_object('id', NEW.id, 'username', NEW.username)::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_notify
+AFTER INSERT ON "notifications"
+FOR EACH ROW EXECUTE FUNCTION notify_new_notification();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS viewings;
+DROP TABLE IF EXISTS viewing_slots;
+
+DROP TYPE IF EXISTS viewing_status;
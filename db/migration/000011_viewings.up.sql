@@ -0,0 +1,36 @@
+CREATE TYPE viewing_status AS ENUM ('booked', 'cancelled');
+
+CREATE TABLE "viewing_slots" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("ends_at" > "starts_at")
+);
+
+ALTER TABLE "viewing_slots" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "viewing_slots" ("asset_id", "starts_at");
+
+CREATE TABLE "viewings" (
+  "id" bigserial PRIMARY KEY,
+  "slot_id" bigint NOT NULL,
+  "asset_id" bigint NOT NULL,
+  "buyer" varchar NOT NULL,
+  "status" viewing_status NOT NULL DEFAULT 'booked',
+  "note" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "viewings" ADD FOREIGN KEY ("slot_id") REFERENCES "viewing_slots" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "viewings" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "viewings" ADD FOREIGN KEY ("buyer") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+-- a slot holds at most one booking, and a buyer one booking per asset
+CREATE UNIQUE INDEX viewings_booked_slot_idx ON "viewings" ("slot_id") WHERE "status" = 'booked';
+CREATE UNIQUE INDEX viewings_booked_buyer_idx ON "viewings" ("asset_id", "buyer") WHERE "status" = 'booked';
+CREATE INDEX ON "viewings" ("buyer");
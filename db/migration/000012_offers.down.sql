@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS offer_events;
+DROP TABLE IF EXISTS offers;
+
+DROP TYPE IF EXISTS offer_action;
+DROP TYPE IF EXISTS offer_status;
+
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "under_offer";
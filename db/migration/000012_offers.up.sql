@@ -0,0 +1,47 @@
+ALTER TABLE "assets" ADD COLUMN "under_offer" boolean NOT NULL DEFAULT false;
+
+-- pending waits on the seller, countered waits on the buyer
+CREATE TYPE offer_status AS ENUM ('pending', 'countered', 'accepted', 'rejected', 'declined', 'withdrawn', 'expired');
+
+CREATE TYPE offer_action AS ENUM ('submitted', 'countered', 'accepted', 'rejected', 'declined', 'withdrawn', 'expired');
+
+CREATE TABLE "offers" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "buyer" varchar NOT NULL,
+  "seller" varchar NOT NULL,
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "conditions" text NOT NULL DEFAULT '',
+  "expires_at" timestamptz NOT NULL,
+  "status" offer_status NOT NULL DEFAULT 'pending',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "offers" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "offers" ADD FOREIGN KEY ("buyer") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "offers" ADD FOREIGN KEY ("seller") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+-- one open negotiation per buyer and asset
+CREATE UNIQUE INDEX offers_open_idx ON "offers" ("asset_id", "buyer") WHERE "status" IN ('pending', 'countered');
+CREATE INDEX ON "offers" ("buyer");
+CREATE INDEX ON "offers" ("asset_id", "status");
+
+-- every step of the negotiation, with the terms on the table at that point
+CREATE TABLE "offer_events" (
+  "id" bigserial PRIMARY KEY,
+  "offer_id" bigint NOT NULL,
+  "actor" varchar NOT NULL,
+  "action" offer_action NOT NULL,
+  "amount" bigint NOT NULL,
+  "conditions" text NOT NULL DEFAULT '',
+  "expires_at" timestamptz NOT NULL,
+  "note" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "offer_events" ADD FOREIGN KEY ("offer_id") REFERENCES "offers" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "offer_events" ("offer_id", "id");
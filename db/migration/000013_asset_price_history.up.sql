@@ -0,0 +1,12 @@
+CREATE TABLE "asset_price_history" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "old_price" bigint NOT NULL,
+  "new_price" bigint NOT NULL,
+  "changed_by" varchar NOT NULL,
+  "changed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "asset_price_history" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "asset_price_history" ("asset_id", "id");
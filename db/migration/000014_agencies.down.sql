@@ -0,0 +1,7 @@
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "agent";
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "agency_id";
+
+DROP TABLE IF EXISTS agency_members;
+DROP TABLE IF EXISTS agencies;
+
+DROP TYPE IF EXISTS agency_role;
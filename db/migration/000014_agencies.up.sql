@@ -0,0 +1,38 @@
+CREATE TYPE "agency_role" AS ENUM (
+  'admin',
+  'agent'
+);
+
+CREATE TABLE "agencies" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "license_number" varchar UNIQUE NOT NULL,
+  "logo_url" varchar,
+  "verified" boolean NOT NULL DEFAULT false,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+-- a user works for at most one agency
+CREATE TABLE "agency_members" (
+  "agency_id" bigint NOT NULL,
+  "username" varchar UNIQUE NOT NULL,
+  "role" agency_role NOT NULL DEFAULT 'agent',
+  "joined_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("agency_id", "username")
+);
+
+ALTER TABLE "agencies" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "agency_members" ADD FOREIGN KEY ("agency_id") REFERENCES "agencies" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "agency_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "assets" ADD COLUMN "agency_id" bigint REFERENCES "agencies" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "assets" ADD COLUMN "agent" varchar REFERENCES "users" ("username") ON DELETE SET NULL;
+
+CREATE INDEX ON "assets" ("agency_id");
+
+CREATE INDEX ON "assets" ("agent");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS seller_reviews;
+
+DROP TYPE IF EXISTS review_status;
@@ -0,0 +1,27 @@
+CREATE TYPE "review_status" AS ENUM (
+  'visible',
+  'hidden'
+);
+
+CREATE TABLE "seller_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "seller" varchar NOT NULL,
+  "reviewer" varchar NOT NULL,
+  "rating" integer NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+  "comment" text NOT NULL DEFAULT '',
+  "reply" text,
+  "replied_at" timestamptz,
+  "status" review_status NOT NULL DEFAULT 'visible',
+  "moderated_by" varchar,
+  "moderation_reason" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "seller_reviews" ADD FOREIGN KEY ("seller") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "seller_reviews" ADD FOREIGN KEY ("reviewer") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+-- one review per buyer and seller
+CREATE UNIQUE INDEX ON "seller_reviews" ("seller", "reviewer");
+CREATE INDEX ON "seller_reviews" ("seller", "status", "created_at");
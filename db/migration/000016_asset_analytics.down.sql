@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS inquiry_threads_asset_id_created_at_idx;
+
+DROP TABLE IF EXISTS asset_visitors;
+DROP TABLE IF EXISTS asset_daily_stats;
@@ -0,0 +1,23 @@
+CREATE TABLE "asset_daily_stats" (
+  "asset_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "views" integer NOT NULL DEFAULT 0,
+  "unique_visitors" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("asset_id", "day")
+);
+
+ALTER TABLE "asset_daily_stats" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+-- who has already been counted today, only kept long enough to deduplicate
+CREATE TABLE "asset_visitors" (
+  "asset_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "visitor" varchar NOT NULL,
+  PRIMARY KEY ("asset_id", "day", "visitor")
+);
+
+ALTER TABLE "asset_visitors" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "asset_visitors" ("day");
+
+CREATE INDEX ON "inquiry_threads" ("asset_id", "created_at");
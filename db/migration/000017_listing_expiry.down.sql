@@ -0,0 +1,3 @@
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "archived_at";
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "expiry_warned_at";
+ALTER TABLE "assets" DROP COLUMN IF EXISTS "expires_at";
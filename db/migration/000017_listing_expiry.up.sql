@@ -0,0 +1,7 @@
+ALTER TABLE "assets" ADD COLUMN "expires_at" timestamptz NOT NULL DEFAULT (now() + interval '90 days');
+
+ALTER TABLE "assets" ADD COLUMN "expiry_warned_at" timestamptz;
+
+ALTER TABLE "assets" ADD COLUMN "archived_at" timestamptz;
+
+CREATE INDEX ON "assets" ("expires_at");
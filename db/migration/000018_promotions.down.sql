@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS asset_promotions;
+
+DROP TYPE IF EXISTS promotion_status;
+DROP TYPE IF EXISTS promotion_type;
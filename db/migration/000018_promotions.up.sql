@@ -0,0 +1,34 @@
+CREATE TYPE "promotion_type" AS ENUM (
+  'featured',
+  'top_of_search',
+  'highlighted'
+);
+
+CREATE TYPE "promotion_status" AS ENUM (
+  'active',
+  'expired',
+  'revoked'
+);
+
+CREATE TABLE "asset_promotions" (
+  "id" bigserial PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "type" promotion_type NOT NULL,
+  "status" promotion_status NOT NULL DEFAULT 'active',
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "granted_by" varchar,
+  "revoked_by" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("ends_at" > "starts_at")
+);
+
+ALTER TABLE "asset_promotions" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "asset_promotions" ADD FOREIGN KEY ("granted_by") REFERENCES "users" ("username") ON DELETE SET NULL;
+
+ALTER TABLE "asset_promotions" ADD FOREIGN KEY ("revoked_by") REFERENCES "users" ("username") ON DELETE SET NULL;
+
+CREATE INDEX ON "asset_promotions" ("asset_id", "status");
+CREATE INDEX ON "asset_promotions" ("status", "ends_at");
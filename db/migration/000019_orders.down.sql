@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS orders;
+
+DROP TYPE IF EXISTS order_feature;
+DROP TYPE IF EXISTS order_status;
@@ -0,0 +1,52 @@
+CREATE TYPE "order_status" AS ENUM (
+  'pending',
+  'paid',
+  'failed',
+  'refunded'
+);
+
+CREATE TYPE "order_feature" AS ENUM (
+  'featured',
+  'top_of_search',
+  'highlighted',
+  'listing_extension'
+);
+
+CREATE TABLE "orders" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "asset_id" bigint NOT NULL,
+  "feature" order_feature NOT NULL,
+  "duration_days" integer NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL DEFAULT 'THB',
+  "status" order_status NOT NULL DEFAULT 'pending',
+  "provider" varchar NOT NULL,
+  "provider_ref" varchar,
+  "promotion_id" bigint,
+  "paid_at" timestamptz,
+  "refunded_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "orders" ADD FOREIGN KEY ("promotion_id") REFERENCES "asset_promotions" ("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX ON "orders" ("provider", "provider_ref");
+CREATE INDEX ON "orders" ("username", "created_at");
+
+-- webhook events already handled, so redeliveries are ignored
+CREATE TABLE "payment_events" (
+  "provider" varchar NOT NULL,
+  "event_id" varchar NOT NULL,
+  "order_id" bigint NOT NULL,
+  "type" varchar NOT NULL,
+  "received_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("provider", "event_id")
+);
+
+ALTER TABLE "payment_events" ADD FOREIGN KEY ("order_id") REFERENCES "orders" ("id") ON DELETE CASCADE;
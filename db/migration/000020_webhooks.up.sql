@@ -0,0 +1,65 @@
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+  'pending',
+  'succeeded',
+  'failed'
+);
+
+-- a subscription belongs to either a user or an agency
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar,
+  "agency_id" bigint,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK (("username" IS NULL) <> ("agency_id" IS NULL))
+);
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("agency_id") REFERENCES "agencies" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "webhook_subscriptions" ("username");
+CREATE INDEX ON "webhook_subscriptions" ("agency_id");
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "event_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" webhook_delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_status_code" integer,
+  "last_error" text,
+  "redelivery_of" bigint,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("redelivery_of") REFERENCES "webhook_deliveries" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "webhook_deliveries" ("status", "next_attempt_at");
+CREATE INDEX ON "webhook_deliveries" ("subscription_id", "id");
+
+CREATE TABLE "webhook_delivery_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "delivery_id" bigint NOT NULL,
+  "attempt" integer NOT NULL,
+  "status_code" integer,
+  "error" text,
+  "duration_ms" integer NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_delivery_attempts" ADD FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "webhook_delivery_attempts" ("delivery_id");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS webhook_deliveries_subscription_id_event_id_idx;
+
+DROP TABLE IF EXISTS outbox_events;
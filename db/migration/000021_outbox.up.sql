@@ -0,0 +1,19 @@
+-- domain events written in the same transaction as the change they describe
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "idempotency_key" varchar UNIQUE NOT NULL,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" text,
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox_events" ("id") WHERE "published_at" IS NULL;
+CREATE INDEX ON "outbox_events" ("published_at");
+
+-- an event relayed twice must not be delivered twice to the same subscriber
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("subscription_id", "event_id") WHERE "redelivery_of" IS NULL;
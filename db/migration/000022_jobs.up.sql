@@ -0,0 +1,29 @@
+CREATE TYPE "job_status" AS ENUM (
+  'pending',
+  'running',
+  'succeeded',
+  'dead'
+);
+
+CREATE TABLE "jobs" (
+  "id" bigserial PRIMARY KEY,
+  "queue" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" job_status NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "max_attempts" integer NOT NULL,
+  "run_at" timestamptz NOT NULL DEFAULT (now()),
+  "locked_by" varchar,
+  "locked_until" timestamptz,
+  "last_error" text,
+  -- at most one job is ever enqueued per key, used by cron schedules
+  "unique_key" varchar UNIQUE,
+  "finished_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "jobs" ("queue", "run_at") WHERE "status" = 'pending';
+CREATE INDEX ON "jobs" ("locked_until") WHERE "status" = 'running';
+CREATE INDEX ON "jobs" ("status", "id");
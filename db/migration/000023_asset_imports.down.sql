@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS asset_imports;
+
+DROP TYPE IF EXISTS import_status;
@@ -0,0 +1,37 @@
+CREATE TYPE "import_status" AS ENUM (
+  'pending',
+  'running',
+  'completed',
+  'failed'
+);
+
+CREATE TABLE "asset_imports" (
+  "id" bigserial PRIMARY KEY,
+  "agency_id" bigint NOT NULL,
+  "created_by" varchar NOT NULL,
+  "filename" varchar NOT NULL,
+  "status" import_status NOT NULL DEFAULT 'pending',
+  -- validated rows, the job imports them in order and resumes after processed_rows
+  "items" jsonb NOT NULL,
+  "archive_path" varchar,
+  "moderation_status" moderation_status NOT NULL,
+  "moderation_reason" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "total_rows" integer NOT NULL,
+  "processed_rows" integer NOT NULL DEFAULT 0,
+  "imported_rows" integer NOT NULL DEFAULT 0,
+  "failed_rows" integer NOT NULL DEFAULT 0,
+  "errors" jsonb NOT NULL DEFAULT '[]',
+  "job_id" bigint,
+  "finished_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "asset_imports" ADD FOREIGN KEY ("agency_id") REFERENCES "agencies" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "asset_imports" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "asset_imports" ADD FOREIGN KEY ("job_id") REFERENCES "jobs" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "asset_imports" ("agency_id", "id");
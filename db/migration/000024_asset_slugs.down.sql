@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS asset_slug_redirects;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS slug;
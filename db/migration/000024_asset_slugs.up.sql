@@ -0,0 +1,26 @@
+ALTER TABLE "assets" ADD COLUMN "slug" varchar;
+
+-- the application builds slugs from the same parts, this only has to give
+-- existing listings a unique one
+UPDATE "assets" SET "slug" = concat_ws('-',
+  nullif(trim(BOTH '-' FROM regexp_replace(
+    lower(property_type::text || ' ' || province || ' ' || left(split_part(detail, E'\n', 1), 40)),
+    '[^[:alnum:]]+', '-', 'g'
+  )), ''),
+  id
+);
+
+ALTER TABLE "assets" ALTER COLUMN "slug" SET NOT NULL;
+
+ALTER TABLE "assets" ADD CONSTRAINT "assets_slug_key" UNIQUE ("slug");
+
+-- slugs an asset had before, requests for them are redirected to the current one
+CREATE TABLE "asset_slug_redirects" (
+  "slug" varchar PRIMARY KEY,
+  "asset_id" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "asset_slug_redirects" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "asset_slug_redirects" ("asset_id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS asset_translations;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS primary_locale;
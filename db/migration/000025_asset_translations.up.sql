@@ -0,0 +1,14 @@
+-- the language assets.detail is written in
+ALTER TABLE "assets" ADD COLUMN "primary_locale" varchar(8) NOT NULL DEFAULT 'th';
+
+CREATE TABLE "asset_translations" (
+  "asset_id" bigint NOT NULL,
+  "locale" varchar(8) NOT NULL,
+  "title" varchar NOT NULL,
+  "description" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("asset_id", "locale")
+);
+
+ALTER TABLE "asset_translations" ADD FOREIGN KEY ("asset_id") REFERENCES "assets" ("id") ON DELETE CASCADE;
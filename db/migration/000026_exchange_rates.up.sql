@@ -0,0 +1,18 @@
+-- rate is how many baht one unit of the currency buys, minor_units is the
+-- number of decimals the currency is written with (2 for USD, 0 for JPY)
+CREATE TABLE "exchange_rates" (
+  "currency" varchar(3) PRIMARY KEY,
+  "rate" numeric(20,10) NOT NULL CHECK ("rate" > 0),
+  "minor_units" smallint NOT NULL DEFAULT 2 CHECK ("minor_units" BETWEEN 0 AND 4),
+  "updated_by" varchar,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "exchange_rates" ADD FOREIGN KEY ("updated_by") REFERENCES "users" ("username") ON DELETE SET NULL;
+
+INSERT INTO "exchange_rates" ("currency", "rate", "minor_units") VALUES ('THB', 1, 2);
+
+-- assets.price is in whole units of this currency
+ALTER TABLE "assets" ADD COLUMN "currency" varchar(3) NOT NULL DEFAULT 'THB';
+
+ALTER TABLE "assets" ADD FOREIGN KEY ("currency") REFERENCES "exchange_rates" ("currency");
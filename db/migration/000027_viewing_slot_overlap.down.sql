@@ -0,0 +1 @@
+ALTER TABLE "viewing_slots" DROP CONSTRAINT IF EXISTS viewing_slots_no_overlap;
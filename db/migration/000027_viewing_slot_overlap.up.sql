@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- a listing's viewing slots may touch but not overlap
+ALTER TABLE "viewing_slots" ADD CONSTRAINT viewing_slots_no_overlap
+  EXCLUDE USING gist ("asset_id" WITH =, tstzrange("starts_at", "ends_at") WITH &&);
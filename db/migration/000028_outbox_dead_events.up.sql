@@ -0,0 +1,8 @@
+-- events the relay gave up on, they are skipped until an admin retries them
+ALTER TABLE "outbox_events" ADD COLUMN "dead_at" timestamptz;
+
+-- events that already ran out of attempts were skipped silently until now
+UPDATE "outbox_events" SET "dead_at" = now()
+WHERE "published_at" IS NULL AND "attempts" >= 10;
+
+CREATE INDEX ON "outbox_events" ("dead_at") WHERE "dead_at" IS NOT NULL;
@@ -0,0 +1,16 @@
+UPDATE "orders" SET "status" = 'paid' WHERE "status" = 'refunding';
+
+ALTER TYPE "order_status" RENAME TO "order_status_old";
+
+CREATE TYPE "order_status" AS ENUM (
+  'pending',
+  'paid',
+  'failed',
+  'refunded'
+);
+
+ALTER TABLE "orders" ALTER COLUMN "status" DROP DEFAULT;
+ALTER TABLE "orders" ALTER COLUMN "status" TYPE "order_status" USING "status"::text::"order_status";
+ALTER TABLE "orders" ALTER COLUMN "status" SET DEFAULT 'pending';
+
+DROP TYPE "order_status_old";
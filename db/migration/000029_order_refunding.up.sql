@@ -0,0 +1,3 @@
+-- a refund claims its order before the provider is called, so two admins
+-- cannot refund the same order twice
+ALTER TYPE "order_status" ADD VALUE 'refunding' AFTER 'paid';
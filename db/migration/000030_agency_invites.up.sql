@@ -0,0 +1,18 @@
+-- agencies invite users, who join by accepting
+CREATE TABLE "agency_invites" (
+  "id" bigserial PRIMARY KEY,
+  "agency_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" agency_role NOT NULL DEFAULT 'agent',
+  "invited_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("agency_id", "username")
+);
+
+ALTER TABLE "agency_invites" ADD FOREIGN KEY ("agency_id") REFERENCES "agencies" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "agency_invites" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "agency_invites" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "agency_invites" ("username");
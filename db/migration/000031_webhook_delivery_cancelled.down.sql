@@ -0,0 +1,15 @@
+UPDATE "webhook_deliveries" SET "status" = 'failed' WHERE "status" = 'cancelled';
+
+ALTER TYPE "webhook_delivery_status" RENAME TO "webhook_delivery_status_old";
+
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+  'pending',
+  'succeeded',
+  'failed'
+);
+
+ALTER TABLE "webhook_deliveries" ALTER COLUMN "status" DROP DEFAULT;
+ALTER TABLE "webhook_deliveries" ALTER COLUMN "status" TYPE "webhook_delivery_status" USING "status"::text::"webhook_delivery_status";
+ALTER TABLE "webhook_deliveries" ALTER COLUMN "status" SET DEFAULT 'pending';
+
+DROP TYPE "webhook_delivery_status_old";
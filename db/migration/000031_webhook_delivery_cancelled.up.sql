@@ -0,0 +1,3 @@
+-- deliveries still pending when their subscription is deactivated are
+-- cancelled instead of being sent once it is turned back on
+ALTER TYPE "webhook_delivery_status" ADD VALUE 'cancelled';
@@ -0,0 +1,50 @@
+-- prices are stored in minor units of the listing's currency, satang for THB,
+-- so a USD price of 1,234.50 is 123450
+UPDATE "assets" a
+SET "price" = a."price" * power(10, er."minor_units")::bigint
+FROM "exchange_rates" er
+WHERE er."currency" = a."currency";
+
+UPDATE "asset_price_history" h
+SET
+  "old_price" = h."old_price" * power(10, er."minor_units")::bigint,
+  "new_price" = h."new_price" * power(10, er."minor_units")::bigint
+FROM "assets" a
+JOIN "exchange_rates" er ON er."currency" = a."currency"
+WHERE a."id" = h."asset_id";
+
+UPDATE "offers" o
+SET "amount" = o."amount" * power(10, er."minor_units")::bigint
+FROM "assets" a
+JOIN "exchange_rates" er ON er."currency" = a."currency"
+WHERE a."id" = o."asset_id";
+
+UPDATE "offer_events" e
+SET "amount" = e."amount" * power(10, er."minor_units")::bigint
+FROM "offers" o
+JOIN "assets" a ON a."id" = o."asset_id"
+JOIN "exchange_rates" er ON er."currency" = a."currency"
+WHERE o."id" = e."offer_id";
+
+UPDATE "saved_search_notifications" n
+SET "price" = n."price" * power(10, er."minor_units")::bigint
+FROM "assets" a
+JOIN "exchange_rates" er ON er."currency" = a."currency"
+WHERE a."id" = n."asset_id";
+
+-- saved search price filters are in satang
+UPDATE "saved_searches"
+SET "filters" = jsonb_set("filters", '{min_price}', to_jsonb(("filters"->>'min_price')::bigint * 100))
+WHERE "filters" ? 'min_price';
+
+UPDATE "saved_searches"
+SET "filters" = jsonb_set("filters", '{max_price}', to_jsonb(("filters"->>'max_price')::bigint * 100))
+WHERE "filters" ? 'max_price';
+
+-- rows of imports still to run are priced in baht
+UPDATE "asset_imports" i
+SET "items" = CASE WHEN jsonb_typeof(i."items") = 'array' THEN (
+  SELECT coalesce(jsonb_agg(jsonb_set(item, '{price}', to_jsonb((item->>'price')::bigint * 100)) ORDER BY ord), '[]')
+  FROM jsonb_array_elements(i."items") WITH ORDINALITY AS t(item, ord)
+) ELSE i."items" END
+WHERE i."status" IN ('pending', 'running');
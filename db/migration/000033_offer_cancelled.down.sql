@@ -0,0 +1,23 @@
+UPDATE "offers" SET "status" = 'withdrawn' WHERE "status" = 'cancelled';
+UPDATE "offer_events" SET "action" = 'withdrawn' WHERE "action" = 'cancelled';
+
+-- the partial index compares against the type, it cannot survive the swap
+DROP INDEX offers_open_idx;
+
+ALTER TYPE "offer_status" RENAME TO "offer_status_old";
+ALTER TYPE "offer_action" RENAME TO "offer_action_old";
+
+CREATE TYPE "offer_status" AS ENUM ('pending', 'countered', 'accepted', 'rejected', 'declined', 'withdrawn', 'expired');
+
+CREATE TYPE "offer_action" AS ENUM ('submitted', 'countered', 'accepted', 'rejected', 'declined', 'withdrawn', 'expired');
+
+ALTER TABLE "offers" ALTER COLUMN "status" DROP DEFAULT;
+ALTER TABLE "offers" ALTER COLUMN "status" TYPE "offer_status" USING "status"::text::"offer_status";
+ALTER TABLE "offers" ALTER COLUMN "status" SET DEFAULT 'pending';
+
+ALTER TABLE "offer_events" ALTER COLUMN "action" TYPE "offer_action" USING "action"::text::"offer_action";
+
+DROP TYPE "offer_status_old";
+DROP TYPE "offer_action_old";
+
+CREATE UNIQUE INDEX offers_open_idx ON "offers" ("asset_id", "buyer") WHERE "status" IN ('pending', 'countered');
@@ -0,0 +1,5 @@
+-- an accepted offer is cancelled when the deal falls through, which takes the
+-- asset off offer again
+ALTER TYPE "offer_status" ADD VALUE 'cancelled';
+
+ALTER TYPE "offer_action" ADD VALUE 'cancelled';
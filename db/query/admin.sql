@@ -1,4 +1,9 @@
 -- name: GetAllUsers :many
 SELECT * FROM users 
 ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
\ No newline at end of file
+LIMIT $1 OFFSET $2;
+
+-- name: SetUserTrusted :exec
+UPDATE users
+SET is_trusted = $2
+WHERE username = $1;
@@ -0,0 +1,191 @@
+-- name: CreateAgency :one
+INSERT INTO agencies (
+  name,
+  license_number,
+  logo_url,
+  created_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAgency :one
+SELECT * FROM agencies
+WHERE id = $1;
+
+-- name: GetAgencyForUpdate :one
+SELECT * FROM agencies
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateAgency :one
+UPDATE agencies
+SET name = $2, logo_url = $3, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetAgencyVerified :one
+UPDATE agencies
+SET verified = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListAgencies :many
+SELECT * FROM agencies
+WHERE sqlc.narg(verified)::boolean IS NULL OR verified = sqlc.narg(verified)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountAgencies :one
+SELECT count(id) FROM agencies
+WHERE sqlc.narg(verified)::boolean IS NULL OR verified = sqlc.narg(verified);
+
+-- name: AddAgencyMember :one
+INSERT INTO agency_members (
+  agency_id,
+  username,
+  role
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: CreateAgencyInvite :one
+INSERT INTO agency_invites (
+  agency_id,
+  username,
+  role,
+  invited_by
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAgencyInviteForUpdate :one
+SELECT * FROM agency_invites
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListAgencyInvites :many
+SELECT * FROM agency_invites
+WHERE agency_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListAgencyInvitesByUser :many
+SELECT
+  i.id,
+  i.agency_id,
+  i.role,
+  i.invited_by,
+  i.created_at,
+  a.name AS agency_name,
+  a.verified AS agency_verified
+FROM agency_invites i
+JOIN agencies a ON a.id = i.agency_id
+WHERE i.username = $1
+ORDER BY i.created_at DESC;
+
+-- name: CancelAgencyInvite :execrows
+DELETE FROM agency_invites
+WHERE id = $1 AND agency_id = $2;
+
+-- name: DeclineAgencyInvite :execrows
+DELETE FROM agency_invites
+WHERE id = $1 AND username = $2;
+
+-- name: DeleteAgencyInvitesByUser :exec
+DELETE FROM agency_invites
+WHERE username = $1;
+
+-- name: GetAgencyMembership :one
+SELECT * FROM agency_members
+WHERE username = $1;
+
+-- name: GetAgencyMembers :many
+SELECT
+  m.agency_id,
+  m.username,
+  m.role,
+  m.joined_at,
+  u.name,
+  u.profile_url
+FROM agency_members m
+JOIN users u ON u.username = m.username
+WHERE m.agency_id = $1
+ORDER BY m.role, m.joined_at;
+
+-- name: CountAgencyAdmins :one
+SELECT count(*) FROM agency_members
+WHERE agency_id = $1 AND role = 'admin';
+
+-- name: UpdateAgencyMemberRole :execrows
+UPDATE agency_members
+SET role = $3
+WHERE agency_id = $1 AND username = $2;
+
+-- name: RemoveAgencyMember :execrows
+DELETE FROM agency_members
+WHERE agency_id = $1 AND username = $2;
+
+-- name: SetAssetAgency :exec
+UPDATE assets
+SET agency_id = sqlc.narg(agency_id), agent = sqlc.narg(agent), updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: UnassignAgentAssets :exec
+UPDATE assets
+SET agent = NULL, updated_at = now()
+WHERE agency_id = $1 AND agent = $2;
+
+-- name: CanManageAsset :one
+SELECT EXISTS (
+  SELECT 1 FROM assets a
+  LEFT JOIN agency_members m ON m.agency_id = a.agency_id AND m.username = sqlc.arg(username)
+  WHERE a.id = sqlc.arg(asset_id)
+    AND (
+      a.owner = sqlc.arg(username)
+      OR (a.agent = sqlc.arg(username) AND m.username IS NOT NULL)
+      OR m.role = 'admin'
+    )
+)::boolean AS can_manage;
+
+-- name: GetAgencyAssets :many
+SELECT 
+  a.id,
+  a.owner,
+  a.price,
+  a.detail,
+  a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
+  a.created_at,
+  a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
+  MIN(ac.id) AS contact_id,
+  MIN(ac.contact_name) AS contact_name,
+  MIN(ac.contact_detail) AS contact_detail,
+  MIN(ai.id) AS image_id,
+  MIN(ai.image_url) AS image_url
+FROM assets a
+LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
+LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
+WHERE a.agency_id = sqlc.arg(agency_id)
+  AND (NOT sqlc.arg(active_only)::boolean OR (a.moderation_status = 'approved' AND NOT a.status AND a.expires_at > now()))
+GROUP BY a.id
+ORDER BY MIN(pr.promotion_rank) NULLS LAST, a.id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: GetAgencyAssetCount :one
+SELECT count(id) FROM assets
+WHERE agency_id = sqlc.arg(agency_id)
+  AND (NOT sqlc.arg(active_only)::boolean OR (moderation_status = 'approved' AND NOT status AND expires_at > now()));
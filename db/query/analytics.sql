@@ -0,0 +1,39 @@
+-- name: InsertAssetVisitors :execrows
+INSERT INTO asset_visitors (asset_id, day, visitor)
+SELECT sqlc.arg(asset_id), sqlc.arg(day), unnest(sqlc.arg(visitors)::varchar[])
+ON CONFLICT DO NOTHING;
+
+-- name: AddAssetDailyStats :exec
+INSERT INTO asset_daily_stats (
+  asset_id,
+  day,
+  views,
+  unique_visitors
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (asset_id, day) DO UPDATE
+SET
+  views = asset_daily_stats.views + EXCLUDED.views,
+  unique_visitors = asset_daily_stats.unique_visitors + EXCLUDED.unique_visitors;
+
+-- name: DeleteAssetVisitorsBefore :exec
+DELETE FROM asset_visitors
+WHERE day < $1;
+
+-- name: GetAssetAnalytics :many
+SELECT
+  d.day::date AS day,
+  COALESCE(s.views, 0)::bigint AS views,
+  COALESCE(s.unique_visitors, 0)::bigint AS unique_visitors,
+  (
+    SELECT count(*) FROM favorites f
+    WHERE f.asset_id = sqlc.arg(asset_id) AND (f.created_at AT TIME ZONE 'UTC')::date = d.day::date
+  ) AS favorites,
+  (
+    SELECT count(*) FROM inquiry_threads t
+    WHERE t.asset_id = sqlc.arg(asset_id) AND (t.created_at AT TIME ZONE 'UTC')::date = d.day::date
+  ) AS inquiries
+FROM generate_series(sqlc.arg(from_day)::date, sqlc.arg(to_day)::date, interval '1 day') AS d(day)
+LEFT JOIN asset_daily_stats s ON s.asset_id = sqlc.arg(asset_id) AND s.day = d.day::date
+ORDER BY d.day;
@@ -1,8 +1,23 @@
 -- name: InsertAsset :one
+WITH next AS (
+  SELECT nextval(pg_get_serial_sequence('assets', 'id')) AS id
+)
 INSERT INTO assets 
-    (owner, price, detail)
+    (id, owner, price, detail, moderation_status, property_type, province, expires_at, primary_locale, currency, slug)
 VALUES 
-    ($1, $2, $3)
+    (
+      (SELECT id FROM next),
+      sqlc.arg(owner),
+      sqlc.arg(price),
+      sqlc.arg(detail),
+      sqlc.arg(moderation_status),
+      sqlc.arg(property_type),
+      sqlc.arg(province),
+      sqlc.arg(expires_at),
+      sqlc.arg(primary_locale),
+      sqlc.arg(currency),
+      sqlc.arg(slug_base)::varchar || '-' || (SELECT id FROM next)
+    )
 RETURNING *;
 
 -- name: GetAssetById :one
@@ -12,16 +27,35 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   ac.id AS contact_id,
   ac.contact_name,
   ac.contact_detail,
   ai.id AS image_id,
-  ai.image_url
+  ai.image_url,
+  ph.old_price AS previous_price,
+  ph.changed_at AS price_changed_at
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
 WHERE a.id = $1;
 
 
@@ -32,8 +66,19 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   MIN(ac.id) AS contact_id,
   MIN(ac.contact_name) AS contact_name,
   MIN(ac.contact_detail) AS contact_detail,
@@ -42,9 +87,14 @@ SELECT
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
 WHERE a.owner = $1
 GROUP BY a.id
-ORDER BY a.id DESC
+ORDER BY MIN(pr.promotion_rank) NULLS LAST, a.id DESC
 LIMIT $2 OFFSET $3;
 
 
@@ -56,22 +106,59 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   ac.id AS contact_id,
   ac.contact_name,
   ac.contact_detail,
   ai.id AS image_id,
-  ai.image_url
+  ai.image_url,
+  ph.old_price AS previous_price,
+  ph.changed_at AS price_changed_at
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
-ORDER BY a.id DESC
-LIMIT $1 OFFSET $2;
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
+-- price filters are in satang, prices are in minor units of the listing's
+-- currency and converted
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND a.expires_at > now()
+  AND (sqlc.narg(property_type)::property_type IS NULL OR a.property_type = sqlc.narg(property_type))
+  AND (sqlc.narg(province)::varchar IS NULL OR a.province = sqlc.narg(province))
+  AND (sqlc.narg(min_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) <= sqlc.narg(max_price))
+  AND (sqlc.narg(reduced_since)::timestamptz IS NULL OR (ph.changed_at >= sqlc.narg(reduced_since) AND ph.old_price > a.price))
+ORDER BY
+  pr.promotion_rank NULLS LAST,
+  CASE WHEN sqlc.arg(sort)::varchar = 'recently_reduced' AND ph.old_price > a.price THEN ph.changed_at END DESC NULLS LAST,
+  a.id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
 
 -- name: UpdateAsset :exec
 UPDATE assets
-SET price = coalesce($1, price), detail = coalesce($2, detail)
+SET price = coalesce($1, price), detail = coalesce($2, detail), updated_at = now()
 WHERE id = $3;
 
 -- name: DeleteAsset :exec
@@ -79,8 +166,98 @@ DELETE FROM assets
 WHERE id = $1;
 
 -- name: GetAssetCount :one
-SELECT count(id) FROM assets;
+SELECT count(a.id) FROM assets a
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND a.expires_at > now()
+  AND (sqlc.narg(property_type)::property_type IS NULL OR a.property_type = sqlc.narg(property_type))
+  AND (sqlc.narg(province)::varchar IS NULL OR a.province = sqlc.narg(province))
+  AND (sqlc.narg(min_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) <= sqlc.narg(max_price))
+  AND (sqlc.narg(reduced_since)::timestamptz IS NULL OR (ph.changed_at >= sqlc.narg(reduced_since) AND ph.old_price > a.price));
 
 -- name: GetAssetCountByUsername :one
 SELECT count(id) FROM assets
-WHERE owner = $1;
\ No newline at end of file
+WHERE owner = $1;
+
+-- name: GetApprovedAssetsByUsername :many
+SELECT 
+  a.id,
+  a.owner,
+  a.price,
+  a.detail,
+  a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
+  a.created_at,
+  a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
+  MIN(ac.id) AS contact_id,
+  MIN(ac.contact_name) AS contact_name,
+  MIN(ac.contact_detail) AS contact_detail,
+  MIN(ai.id) AS image_id,
+  MIN(ai.image_url) AS image_url
+FROM assets a
+LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
+LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
+WHERE a.owner = $1 AND a.moderation_status = 'approved' AND a.expires_at > now()
+GROUP BY a.id
+ORDER BY MIN(pr.promotion_rank) NULLS LAST, a.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetApprovedAssetCountByUsername :one
+SELECT count(id) FROM assets
+WHERE owner = $1 AND moderation_status = 'approved' AND expires_at > now();
+
+-- name: GetAssetPriceForUpdate :one
+SELECT price FROM assets
+WHERE id = $1
+FOR UPDATE;
+
+-- name: RenewAsset :one
+UPDATE assets
+SET expires_at = $2, expiry_warned_at = NULL, archived_at = NULL, updated_at = now()
+WHERE id = $1
+RETURNING expires_at;
+
+-- name: WarnExpiringAssets :many
+UPDATE assets
+SET expiry_warned_at = now()
+WHERE archived_at IS NULL
+  AND expiry_warned_at IS NULL
+  AND NOT status
+  AND expires_at > now()
+  AND expires_at <= sqlc.arg(warn_before)
+RETURNING id, owner, expires_at;
+
+-- name: ArchiveExpiredAssets :many
+UPDATE assets
+SET archived_at = now()
+WHERE archived_at IS NULL
+  AND NOT status
+  AND expires_at <= now()
+RETURNING id, owner, expires_at;
+
+-- name: MarkAssetSold :execrows
+UPDATE assets
+SET status = true, under_offer = false, updated_at = now()
+WHERE id = $1 AND NOT status;
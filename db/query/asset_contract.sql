@@ -23,4 +23,8 @@ WHERE id = $1;
 
 -- name: GetAssetContacts :many
 SELECT * FROM asset_contacts
-WHERE asset_id = $1;
\ No newline at end of file
+WHERE asset_id = $1;
+-- name: GetAssetContactsByAssetIDs :many
+SELECT * FROM asset_contacts
+WHERE asset_id = ANY(sqlc.arg(asset_ids)::bigint[])
+ORDER BY asset_id, id;
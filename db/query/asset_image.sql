@@ -13,3 +13,13 @@ WHERE id = $1;
 SELECT * FROM asset_images
 WHERE id = $1;
 
+
+
+-- name: GetAssetImages :many
+SELECT * FROM asset_images
+WHERE asset_id = $1;
+
+-- name: GetAssetImagesByAssetIDs :many
+SELECT * FROM asset_images
+WHERE asset_id = ANY(sqlc.arg(asset_ids)::bigint[])
+ORDER BY asset_id, id;
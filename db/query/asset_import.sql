@@ -0,0 +1,57 @@
+-- name: CreateAssetImport :one
+INSERT INTO asset_imports (
+  agency_id,
+  created_by,
+  filename,
+  items,
+  archive_path,
+  moderation_status,
+  moderation_reason,
+  expires_at,
+  total_rows
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: SetAssetImportJob :exec
+UPDATE asset_imports
+SET job_id = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: GetAssetImport :one
+SELECT * FROM asset_imports
+WHERE id = $1;
+
+-- name: ListAssetImports :many
+SELECT
+  id, agency_id, created_by, filename, status, total_rows, processed_rows,
+  imported_rows, failed_rows, job_id, finished_at, created_at
+FROM asset_imports
+WHERE agency_id = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountAssetImports :one
+SELECT count(id) FROM asset_imports
+WHERE agency_id = $1;
+
+-- name: StartAssetImport :one
+UPDATE asset_imports
+SET status = 'running', updated_at = now()
+WHERE id = $1 AND status IN ('pending', 'running')
+RETURNING *;
+
+-- name: RecordAssetImportRow :exec
+UPDATE asset_imports
+SET
+  processed_rows = processed_rows + 1,
+  imported_rows = imported_rows + CASE WHEN sqlc.arg(imported)::boolean THEN 1 ELSE 0 END,
+  failed_rows = failed_rows + CASE WHEN sqlc.arg(imported)::boolean THEN 0 ELSE 1 END,
+  errors = errors || sqlc.arg(row_errors)::jsonb,
+  updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FinishAssetImport :exec
+UPDATE asset_imports
+SET status = $2, finished_at = now(), updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,42 @@
+-- name: UpsertAssetTranslation :one
+INSERT INTO asset_translations (asset_id, locale, title, description)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (asset_id, locale) DO UPDATE
+SET title = EXCLUDED.title, description = EXCLUDED.description, updated_at = now()
+RETURNING *;
+
+-- name: GetAssetTranslations :many
+SELECT * FROM asset_translations
+WHERE asset_id = $1
+ORDER BY locale;
+
+-- name: DeleteAssetTranslation :execrows
+DELETE FROM asset_translations
+WHERE asset_id = $1 AND locale = $2;
+
+-- name: GetAssetPrimaryLocale :one
+SELECT primary_locale FROM assets
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetAssetDetail :exec
+UPDATE assets
+SET detail = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: SetPrimaryTranslationDescription :exec
+UPDATE asset_translations t
+SET description = a.detail, updated_at = now()
+FROM assets a
+WHERE a.id = $1 AND t.asset_id = a.id AND t.locale = a.primary_locale AND t.description <> a.detail;
+
+-- name: GetLocalizedAssets :many
+SELECT a.id AS asset_id, a.primary_locale, t.locale, t.title, t.description
+FROM assets a
+LEFT JOIN LATERAL (
+  SELECT tr.locale, tr.title, tr.description FROM asset_translations tr
+  WHERE tr.asset_id = a.id AND tr.locale IN (sqlc.arg(locale)::varchar, a.primary_locale)
+  ORDER BY tr.locale = sqlc.arg(locale)::varchar DESC
+  LIMIT 1
+) t ON true
+WHERE a.id = ANY(sqlc.arg(asset_ids)::bigint[]);
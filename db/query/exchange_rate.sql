@@ -0,0 +1,26 @@
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+ORDER BY currency;
+
+-- name: GetExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE currency = $1;
+
+-- name: UpsertExchangeRate :one
+INSERT INTO exchange_rates (currency, rate, minor_units, updated_by)
+VALUES ($1, $2, $3, $4)
+-- listing prices are stored in minor units, so the minor units of a currency
+-- never change and no row comes back when they would
+ON CONFLICT (currency) DO UPDATE
+SET rate = EXCLUDED.rate, updated_by = EXCLUDED.updated_by, updated_at = now()
+WHERE exchange_rates.minor_units = EXCLUDED.minor_units
+RETURNING *;
+
+-- name: DeleteExchangeRate :execrows
+DELETE FROM exchange_rates
+WHERE currency = $1;
+
+-- name: GetAssetExchangeRate :one
+SELECT er.currency, er.rate, er.minor_units, er.updated_by, er.updated_at FROM exchange_rates er
+JOIN assets a ON a.currency = er.currency
+WHERE a.id = $1;
@@ -0,0 +1,59 @@
+-- name: AddFavorite :execrows
+INSERT INTO favorites
+    (username, asset_id)
+VALUES
+    ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveFavorite :exec
+DELETE FROM favorites
+WHERE username = $1 AND asset_id = $2;
+
+-- name: GetFavoriteAssets :many
+SELECT 
+  a.id,
+  a.owner,
+  a.price,
+  a.detail,
+  a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
+  a.created_at,
+  a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
+  MIN(ac.id) AS contact_id,
+  MIN(ac.contact_name) AS contact_name,
+  MIN(ac.contact_detail) AS contact_detail,
+  MIN(ai.id) AS image_id,
+  MIN(ai.image_url) AS image_url
+FROM favorites f
+JOIN assets a ON a.id = f.asset_id
+LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
+LEFT JOIN asset_images ai ON ai.asset_id = a.id
+WHERE f.username = $1 AND a.moderation_status = 'approved'
+GROUP BY a.id, f.created_at
+ORDER BY f.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountFavoritesByUsername :one
+SELECT count(f.asset_id) FROM favorites f
+JOIN assets a ON a.id = f.asset_id
+WHERE f.username = $1 AND a.moderation_status = 'approved';
+
+-- name: GetFavoriteCounts :many
+SELECT asset_id, count(username) AS favorite_count
+FROM favorites
+WHERE asset_id = ANY(sqlc.arg(asset_ids)::bigint[])
+GROUP BY asset_id;
+
+-- name: GetFavoritedAssetIDs :many
+SELECT asset_id FROM favorites
+WHERE username = sqlc.arg(username) AND asset_id = ANY(sqlc.arg(asset_ids)::bigint[]);
@@ -0,0 +1,96 @@
+-- name: UpsertInquiryThread :one
+INSERT INTO inquiry_threads
+    (asset_id, buyer, seller)
+VALUES
+    ($1, $2, $3)
+ON CONFLICT (asset_id, buyer)
+DO UPDATE SET seller = EXCLUDED.seller
+RETURNING *;
+
+-- name: GetInquiryThread :one
+SELECT * FROM inquiry_threads
+WHERE id = $1;
+
+-- name: UpdateInquiryThreadActivity :exec
+UPDATE inquiry_threads
+SET last_message_at = sqlc.arg(sent_at),
+    seller_replied_at = CASE
+      WHEN sqlc.arg(from_seller)::boolean THEN COALESCE(seller_replied_at, sqlc.arg(sent_at))
+      ELSE seller_replied_at
+    END
+WHERE id = sqlc.arg(id);
+
+-- name: ListInquiryThreads :many
+SELECT
+  t.id,
+  t.asset_id,
+  t.buyer,
+  t.seller,
+  t.seller_replied_at,
+  t.last_message_at,
+  t.created_at,
+  a.price AS asset_price,
+  a.detail AS asset_detail,
+  (SELECT m.body FROM inquiry_messages m
+   WHERE m.thread_id = t.id
+   ORDER BY m.id DESC LIMIT 1)::text AS last_message,
+  (SELECT count(m.id) FROM inquiry_messages m
+   WHERE m.thread_id = t.id AND m.sender <> sqlc.arg(username) AND m.read_at IS NULL) AS unread_count
+FROM inquiry_threads t
+JOIN assets a ON a.id = t.asset_id
+WHERE (t.buyer = sqlc.arg(username) OR t.seller = sqlc.arg(username))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR t.asset_id = sqlc.narg(asset_id))
+ORDER BY t.last_message_at DESC, t.id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountInquiryThreads :one
+SELECT count(t.id) FROM inquiry_threads t
+WHERE (t.buyer = sqlc.arg(username) OR t.seller = sqlc.arg(username))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR t.asset_id = sqlc.narg(asset_id));
+
+-- name: GetInquiryInboxByAsset :many
+SELECT
+  t.asset_id,
+  count(DISTINCT t.id) AS thread_count,
+  count(m.id) AS unread_count,
+  max(t.last_message_at)::timestamptz AS last_message_at
+FROM inquiry_threads t
+LEFT JOIN inquiry_messages m ON m.thread_id = t.id AND m.sender <> t.seller AND m.read_at IS NULL
+WHERE t.seller = $1
+GROUP BY t.asset_id
+ORDER BY last_message_at DESC;
+
+-- name: CountUnreadInquiryMessages :one
+SELECT count(m.id) FROM inquiry_messages m
+JOIN inquiry_threads t ON t.id = m.thread_id
+WHERE (t.buyer = sqlc.arg(username) OR t.seller = sqlc.arg(username))
+  AND m.sender <> sqlc.arg(username)
+  AND m.read_at IS NULL;
+
+-- name: CreateInquiryMessage :one
+INSERT INTO inquiry_messages
+    (thread_id, sender, body, created_at)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetInquiryMessages :many
+SELECT * FROM inquiry_messages
+WHERE thread_id = sqlc.arg(thread_id)
+  AND (sqlc.narg(before_id)::bigint IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: MarkInquiryMessagesRead :execrows
+UPDATE inquiry_messages
+SET read_at = now()
+WHERE thread_id = sqlc.arg(thread_id)
+  AND sender <> sqlc.arg(reader)
+  AND read_at IS NULL
+  AND (sqlc.narg(up_to_id)::bigint IS NULL OR id <= sqlc.narg(up_to_id));
+
+-- name: GetRepliedInquiryAssetIDs :many
+SELECT asset_id FROM inquiry_threads
+WHERE buyer = sqlc.arg(buyer)
+  AND seller_replied_at IS NOT NULL
+  AND asset_id = ANY(sqlc.arg(asset_ids)::bigint[]);
@@ -0,0 +1,89 @@
+-- name: EnqueueJob :one
+INSERT INTO jobs (
+  queue,
+  kind,
+  payload,
+  max_attempts,
+  run_at,
+  unique_key
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) ON CONFLICT (unique_key) DO NOTHING
+RETURNING *;
+
+-- name: ClaimJobs :many
+UPDATE jobs
+SET
+  status = 'running',
+  attempts = attempts + 1,
+  locked_by = sqlc.arg(worker_id),
+  locked_until = sqlc.arg(locked_until),
+  updated_at = now()
+WHERE id IN (
+  SELECT id FROM jobs
+  WHERE queue = sqlc.arg(queue) AND status = 'pending' AND run_at <= now()
+  ORDER BY run_at, id
+  LIMIT sqlc.arg(batch_size)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :execrows
+UPDATE jobs
+SET status = 'succeeded', locked_by = NULL, locked_until = NULL, finished_at = now(), updated_at = now()
+WHERE id = $1 AND locked_by = $2;
+
+-- name: RetryJobLater :execrows
+UPDATE jobs
+SET status = 'pending', run_at = $3, last_error = $4, locked_by = NULL, locked_until = NULL, updated_at = now()
+WHERE id = $1 AND locked_by = $2;
+
+-- name: BuryJob :execrows
+UPDATE jobs
+SET status = 'dead', last_error = $3, locked_by = NULL, locked_until = NULL, finished_at = now(), updated_at = now()
+WHERE id = $1 AND locked_by = $2;
+
+-- name: RequeueExpiredJobs :execrows
+UPDATE jobs
+SET
+  status = CASE WHEN attempts >= max_attempts THEN 'dead'::job_status ELSE 'pending'::job_status END,
+  last_error = 'job timed out or its worker stopped',
+  finished_at = CASE WHEN attempts >= max_attempts THEN now() END,
+  locked_by = NULL,
+  locked_until = NULL,
+  updated_at = now()
+WHERE status = 'running' AND locked_until < now();
+
+-- name: GetJob :one
+SELECT * FROM jobs
+WHERE id = $1;
+
+-- name: ListJobs :many
+SELECT * FROM jobs
+WHERE (sqlc.narg(queue)::varchar IS NULL OR queue = sqlc.narg(queue))
+  AND (sqlc.narg(status)::job_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(kind)::varchar IS NULL OR kind = sqlc.narg(kind))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountJobs :one
+SELECT count(id) FROM jobs
+WHERE (sqlc.narg(queue)::varchar IS NULL OR queue = sqlc.narg(queue))
+  AND (sqlc.narg(status)::job_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(kind)::varchar IS NULL OR kind = sqlc.narg(kind));
+
+-- name: GetJobStats :many
+SELECT queue, status, count(id) AS count
+FROM jobs
+GROUP BY queue, status
+ORDER BY queue, status;
+
+-- name: RetryDeadJob :one
+UPDATE jobs
+SET status = 'pending', attempts = 0, run_at = now(), finished_at = NULL, updated_at = now()
+WHERE id = $1 AND status = 'dead'
+RETURNING *;
+
+-- name: DeleteFinishedJobs :execrows
+DELETE FROM jobs
+WHERE status = 'succeeded' AND finished_at < $1;
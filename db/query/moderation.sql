@@ -0,0 +1,30 @@
+-- name: UpdateAssetModeration :exec
+UPDATE assets
+SET moderation_status = $2, moderation_reason = $3, moderated_by = $4, moderation_updated_at = now()
+WHERE id = $1;
+
+-- name: InsertModerationLog :one
+INSERT INTO asset_moderation_logs
+    (asset_id, moderator, status, reason)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetModerationLogs :many
+SELECT * FROM asset_moderation_logs
+WHERE asset_id = $1
+ORDER BY id DESC;
+
+-- name: GetModerationQueue :many
+SELECT * FROM assets
+WHERE moderation_status = sqlc.arg(moderation_status)
+  AND (sqlc.narg(owner)::varchar IS NULL OR owner = sqlc.narg(owner))
+  AND (sqlc.narg(submitted_after)::timestamptz IS NULL OR moderation_updated_at >= sqlc.narg(submitted_after))
+ORDER BY moderation_updated_at ASC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountModerationQueue :one
+SELECT count(id) FROM assets
+WHERE moderation_status = sqlc.arg(moderation_status)
+  AND (sqlc.narg(owner)::varchar IS NULL OR owner = sqlc.narg(owner))
+  AND (sqlc.narg(submitted_after)::timestamptz IS NULL OR moderation_updated_at >= sqlc.narg(submitted_after));
@@ -0,0 +1,42 @@
+-- name: CreateNotification :one
+INSERT INTO notifications
+    (username, kind, title, body, data)
+VALUES
+    ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetNotification :one
+SELECT * FROM notifications
+WHERE id = $1;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE username = sqlc.arg(username)
+  AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountNotifications :one
+SELECT count(id) FROM notifications
+WHERE username = sqlc.arg(username)
+  AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL);
+
+-- name: CountUnreadNotifications :one
+SELECT count(id) FROM notifications
+WHERE username = $1 AND read_at IS NULL;
+
+-- name: GetNotificationsAfter :many
+SELECT * FROM notifications
+WHERE username = $1 AND id > $2
+ORDER BY id
+LIMIT $3;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE id = $1 AND username = $2 AND read_at IS NULL;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE username = $1 AND read_at IS NULL;
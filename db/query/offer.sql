@@ -0,0 +1,78 @@
+-- name: CreateOffer :one
+INSERT INTO offers
+    (asset_id, buyer, seller, amount, conditions, expires_at)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetOffer :one
+SELECT * FROM offers
+WHERE id = $1;
+
+-- name: GetOfferForUpdate :one
+SELECT * FROM offers
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateOfferTerms :one
+UPDATE offers
+SET status = $2, amount = $3, conditions = $4, expires_at = $5, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetOfferStatus :one
+UPDATE offers
+SET status = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeclineOpenOffers :many
+UPDATE offers
+SET status = 'declined', updated_at = now()
+WHERE asset_id = sqlc.arg(asset_id)
+  AND id <> sqlc.arg(accepted_id)
+  AND status IN ('pending', 'countered')
+RETURNING *;
+
+-- name: CreateOfferEvent :exec
+INSERT INTO offer_events
+    (offer_id, actor, action, amount, conditions, expires_at, note)
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetOfferEvents :many
+SELECT * FROM offer_events
+WHERE offer_id = $1
+ORDER BY id;
+
+-- name: ListOffersByBuyer :many
+SELECT * FROM offers
+WHERE buyer = $1
+ORDER BY updated_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountOffersByBuyer :one
+SELECT count(id) FROM offers
+WHERE buyer = $1;
+
+-- name: ListOffersByAsset :many
+SELECT * FROM offers
+WHERE asset_id = sqlc.arg(asset_id)
+  AND (sqlc.narg(status)::offer_status IS NULL OR status = sqlc.narg(status))
+ORDER BY updated_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountOffersByAsset :one
+SELECT count(id) FROM offers
+WHERE asset_id = sqlc.arg(asset_id)
+  AND (sqlc.narg(status)::offer_status IS NULL OR status = sqlc.narg(status));
+
+-- name: LockAssetForOffer :one
+SELECT status, under_offer FROM assets
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetAssetUnderOffer :exec
+UPDATE assets
+SET under_offer = $2, updated_at = now()
+WHERE id = $1;
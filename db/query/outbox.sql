@@ -0,0 +1,55 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox_events (
+  idempotency_key,
+  aggregate_type,
+  aggregate_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT (idempotency_key) DO NOTHING;
+
+-- name: LockOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE published_at IS NULL AND dead_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventsPublished :exec
+UPDATE outbox_events
+SET published_at = now()
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: MarkOutboxEventFailed :one
+UPDATE outbox_events
+SET
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  dead_at = CASE WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeletePublishedOutboxEvents :execrows
+DELETE FROM outbox_events
+WHERE published_at < $1;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1;
+
+-- name: ListDeadOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE dead_at IS NOT NULL
+ORDER BY dead_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: CountDeadOutboxEvents :one
+SELECT count(id) FROM outbox_events
+WHERE dead_at IS NOT NULL;
+
+-- name: RetryDeadOutboxEvent :one
+UPDATE outbox_events
+SET attempts = 0, dead_at = NULL
+WHERE id = $1 AND dead_at IS NOT NULL
+RETURNING *;
@@ -0,0 +1,106 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+  username,
+  asset_id,
+  feature,
+  duration_days,
+  amount,
+  currency,
+  provider
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: SetOrderProviderRef :one
+UPDATE orders
+SET provider_ref = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetOrder :one
+SELECT * FROM orders
+WHERE id = $1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetOrderByProviderRef :one
+SELECT * FROM orders
+WHERE provider = $1 AND provider_ref = $2;
+
+-- name: GetOrderByProviderRefForUpdate :one
+SELECT * FROM orders
+WHERE provider = $1 AND provider_ref = $2
+FOR UPDATE;
+
+-- name: MarkOrderPaid :one
+UPDATE orders
+SET status = 'paid', promotion_id = $2, paid_at = now(), updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOrderFailed :one
+UPDATE orders
+SET status = 'failed', updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ClaimOrderRefund :one
+UPDATE orders
+SET status = 'refunding', updated_at = now()
+WHERE id = $1 AND status = 'paid'
+RETURNING *;
+
+-- name: ReleaseOrderRefund :exec
+UPDATE orders
+SET status = 'paid', updated_at = now()
+WHERE id = $1 AND status = 'refunding';
+
+-- name: MarkOrderRefunded :one
+UPDATE orders
+SET status = 'refunded', refunded_at = now(), updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: InsertPaymentEvent :execrows
+INSERT INTO payment_events (
+  provider,
+  event_id,
+  order_id,
+  type
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT DO NOTHING;
+
+-- name: ListOrdersByUser :many
+SELECT * FROM orders
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountOrdersByUser :one
+SELECT count(id) FROM orders
+WHERE username = $1;
+
+-- name: ListOrders :many
+SELECT * FROM orders
+WHERE sqlc.narg(status)::order_status IS NULL OR status = sqlc.narg(status)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountOrders :one
+SELECT count(id) FROM orders
+WHERE sqlc.narg(status)::order_status IS NULL OR status = sqlc.narg(status);
+
+-- name: ExtendAssetExpiry :one
+UPDATE assets
+SET
+  expires_at = GREATEST(expires_at, now()) + make_interval(days => sqlc.arg(days)::int),
+  expiry_warned_at = NULL,
+  archived_at = NULL,
+  updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING expires_at;
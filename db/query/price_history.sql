@@ -0,0 +1,11 @@
+-- name: InsertAssetPriceHistory :exec
+INSERT INTO asset_price_history
+    (asset_id, old_price, new_price, changed_by)
+VALUES
+    ($1, $2, $3, $4);
+
+-- name: GetAssetPriceHistory :many
+SELECT * FROM asset_price_history
+WHERE asset_id = $1
+ORDER BY id DESC
+LIMIT $2;
@@ -0,0 +1,54 @@
+-- name: CreatePromotion :one
+INSERT INTO asset_promotions (
+  asset_id,
+  type,
+  starts_at,
+  ends_at,
+  granted_by
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetPromotion :one
+SELECT * FROM asset_promotions
+WHERE id = $1;
+
+-- name: RevokePromotion :one
+UPDATE asset_promotions
+SET status = 'revoked', revoked_by = $2, updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ListPromotions :many
+SELECT * FROM asset_promotions
+WHERE (sqlc.narg(status)::promotion_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR asset_id = sqlc.narg(asset_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountPromotions :one
+SELECT count(id) FROM asset_promotions
+WHERE (sqlc.narg(status)::promotion_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR asset_id = sqlc.narg(asset_id));
+
+-- name: GetAssetPromotions :many
+SELECT * FROM asset_promotions
+WHERE asset_id = $1
+ORDER BY id DESC;
+
+-- name: GetLivePromotions :many
+SELECT asset_id, type FROM asset_promotions
+WHERE asset_id = ANY(sqlc.arg(asset_ids)::bigint[])
+  AND status = 'active'
+  AND starts_at <= now()
+  AND ends_at > now()
+ORDER BY asset_id, type;
+
+-- name: ExpirePromotions :many
+UPDATE asset_promotions p
+SET status = 'expired', updated_at = now()
+FROM assets a
+WHERE a.id = p.asset_id
+  AND p.status = 'active'
+  AND p.ends_at <= now()
+RETURNING p.id, p.asset_id, p.type, a.owner;
@@ -0,0 +1,52 @@
+-- name: CreateReport :one
+INSERT INTO reports
+    (reporter, target_type, asset_id, reported_user, category, comment)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetReport :one
+SELECT * FROM reports
+WHERE id = $1;
+
+-- name: ListReports :many
+SELECT * FROM reports
+WHERE (sqlc.narg(status)::report_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(category)::report_category IS NULL OR category = sqlc.narg(category))
+  AND (sqlc.narg(target_type)::report_target IS NULL OR target_type = sqlc.narg(target_type))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR asset_id = sqlc.narg(asset_id))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountReports :one
+SELECT count(id) FROM reports
+WHERE (sqlc.narg(status)::report_status IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(category)::report_category IS NULL OR category = sqlc.narg(category))
+  AND (sqlc.narg(target_type)::report_target IS NULL OR target_type = sqlc.narg(target_type))
+  AND (sqlc.narg(asset_id)::bigint IS NULL OR asset_id = sqlc.narg(asset_id));
+
+-- name: AssignReport :execrows
+UPDATE reports
+SET assignee = $2, status = 'assigned', updated_at = now()
+WHERE id = $1 AND status IN ('open', 'assigned');
+
+-- name: CloseReport :execrows
+UPDATE reports
+SET status = $2, resolution = $3, updated_at = now()
+WHERE id = $1 AND status IN ('open', 'assigned');
+
+-- name: CountOpenReportsByAsset :one
+SELECT count(DISTINCT reporter) FROM reports
+WHERE asset_id = $1 AND status IN ('open', 'assigned');
+
+-- name: GetReportCountsByAsset :many
+SELECT
+  asset_id,
+  count(id) AS total_count,
+  count(id) FILTER (WHERE status IN ('open', 'assigned')) AS open_count,
+  max(created_at)::timestamptz AS last_reported_at
+FROM reports
+WHERE asset_id IS NOT NULL
+GROUP BY asset_id
+ORDER BY open_count DESC, total_count DESC
+LIMIT $1 OFFSET $2;
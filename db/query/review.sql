@@ -0,0 +1,91 @@
+-- name: CreateSellerReview :one
+INSERT INTO seller_reviews (
+  seller,
+  reviewer,
+  rating,
+  comment
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetSellerReview :one
+SELECT * FROM seller_reviews
+WHERE id = $1;
+
+-- name: ListSellerReviews :many
+SELECT
+  r.id,
+  r.seller,
+  r.reviewer,
+  r.rating,
+  r.comment,
+  r.reply,
+  r.replied_at,
+  r.created_at,
+  u.name AS reviewer_name,
+  u.profile_url AS reviewer_profile_url
+FROM seller_reviews r
+JOIN users u ON u.username = r.reviewer
+WHERE r.seller = $1 AND r.status = 'visible'
+ORDER BY r.created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetSellerRatingSummary :one
+SELECT
+  count(*) AS review_count,
+  COALESCE(AVG(rating), 0)::float8 AS average_rating
+FROM seller_reviews
+WHERE seller = $1 AND status = 'visible';
+
+-- name: ReplySellerReview :execrows
+UPDATE seller_reviews
+SET reply = $3, replied_at = now(), updated_at = now()
+WHERE id = $1 AND seller = $2 AND reply IS NULL;
+
+-- name: ListReviewsForModeration :many
+SELECT * FROM seller_reviews
+WHERE sqlc.narg(status)::review_status IS NULL OR status = sqlc.narg(status)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountReviewsForModeration :one
+SELECT count(id) FROM seller_reviews
+WHERE sqlc.narg(status)::review_status IS NULL OR status = sqlc.narg(status);
+
+-- name: SetSellerReviewStatus :one
+UPDATE seller_reviews
+SET
+  status = $2,
+  moderated_by = $3,
+  moderation_reason = $4,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: HasCompletedInteraction :one
+SELECT (
+  EXISTS (
+    SELECT 1 FROM inquiry_threads
+    WHERE seller = sqlc.arg(seller) AND buyer = sqlc.arg(buyer) AND seller_replied_at IS NOT NULL
+  ) OR EXISTS (
+    SELECT 1 FROM viewings v
+    JOIN viewing_slots s ON s.id = v.slot_id
+    JOIN assets a ON a.id = v.asset_id
+    WHERE a.owner = sqlc.arg(seller) AND v.buyer = sqlc.arg(buyer)
+      AND v.status = 'booked' AND s.ends_at < now()
+  )
+)::boolean AS completed;
+
+-- name: GetSellerResponseStats :one
+SELECT
+  count(*) AS inquiry_count,
+  count(seller_replied_at) AS replied_count
+FROM inquiry_threads
+WHERE seller = $1;
+
+-- name: GetSellerListingCounts :one
+SELECT
+  count(*) FILTER (WHERE moderation_status = 'approved' AND NOT status AND expires_at > now()) AS active_count,
+  count(*) FILTER (WHERE status) AS sold_count
+FROM assets
+WHERE owner = $1;
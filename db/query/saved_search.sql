@@ -0,0 +1,79 @@
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches
+    (username, name, filters, frequency, channel, webhook_url)
+VALUES
+    ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetSavedSearch :one
+SELECT * FROM saved_searches
+WHERE id = $1;
+
+-- name: GetSavedSearchesByUsername :many
+SELECT * FROM saved_searches
+WHERE username = $1
+ORDER BY id DESC;
+
+-- name: UpdateSavedSearch :exec
+UPDATE saved_searches
+SET name = $2, filters = $3, frequency = $4, channel = $5, webhook_url = $6, updated_at = now()
+WHERE id = $1;
+
+-- name: DeleteSavedSearch :exec
+DELETE FROM saved_searches
+WHERE id = $1;
+
+-- name: GetDueSavedSearches :many
+SELECT * FROM saved_searches
+WHERE frequency = 'instant'
+   OR (frequency = 'daily' AND last_checked_at <= now() - interval '1 day')
+   OR (frequency = 'weekly' AND last_checked_at <= now() - interval '7 days')
+ORDER BY last_checked_at
+LIMIT $1;
+
+-- name: MarkSavedSearchChecked :exec
+UPDATE saved_searches
+SET last_checked_at = $2
+WHERE id = $1;
+
+-- name: GetSavedSearchMatches :many
+SELECT
+  a.id,
+  a.owner,
+  a.price,
+  a.currency,
+  er.minor_units,
+  a.detail,
+  a.property_type,
+  a.province,
+  n.price AS notified_price
+FROM assets a
+LEFT JOIN saved_search_notifications n ON n.asset_id = a.id AND n.saved_search_id = sqlc.arg(saved_search_id)
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND NOT a.status
+  AND a.expires_at > now()
+  AND a.owner <> sqlc.arg(username)
+  AND (a.moderation_updated_at > sqlc.arg(since) OR a.updated_at > sqlc.arg(since))
+  AND (n.asset_id IS NULL OR a.price < n.price)
+  AND (sqlc.narg(property_type)::property_type IS NULL OR a.property_type = sqlc.narg(property_type))
+  AND (sqlc.narg(province)::varchar IS NULL OR a.province = sqlc.narg(province))
+  AND (sqlc.narg(min_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) >= sqlc.narg(min_price))
+  AND (sqlc.narg(max_price)::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) <= sqlc.narg(max_price))
+  AND (sqlc.narg(reduced_since)::timestamptz IS NULL OR (ph.changed_at >= sqlc.narg(reduced_since) AND ph.old_price > a.price))
+ORDER BY a.id
+LIMIT 50;
+
+-- name: UpsertSavedSearchNotification :exec
+INSERT INTO saved_search_notifications
+    (saved_search_id, asset_id, price)
+VALUES
+    ($1, $2, $3)
+ON CONFLICT (saved_search_id, asset_id)
+DO UPDATE SET price = EXCLUDED.price, notified_at = now();
@@ -0,0 +1,38 @@
+-- name: ResolveAssetSlug :one
+SELECT id, slug FROM assets
+WHERE slug = sqlc.arg(slug)
+UNION ALL
+SELECT a.id, a.slug FROM asset_slug_redirects r
+JOIN assets a ON a.id = r.asset_id
+WHERE r.slug = sqlc.arg(slug)
+LIMIT 1;
+
+-- name: GetAssetSlugSource :one
+SELECT a.slug, a.property_type, a.province, a.detail, t.title FROM assets a
+LEFT JOIN asset_translations t ON t.asset_id = a.id AND t.locale = a.primary_locale
+WHERE a.id = $1
+FOR UPDATE OF a;
+
+-- name: SetAssetSlug :exec
+UPDATE assets
+SET slug = sqlc.arg(slug)
+WHERE id = sqlc.arg(id);
+
+-- name: AddAssetSlugRedirect :exec
+INSERT INTO asset_slug_redirects (slug, asset_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO NOTHING;
+
+-- name: DeleteAssetSlugRedirect :exec
+DELETE FROM asset_slug_redirects
+WHERE slug = $1;
+
+-- name: ListSitemapAssets :many
+SELECT id, slug, updated_at FROM assets
+WHERE moderation_status = 'approved' AND NOT status AND expires_at > now()
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: CountSitemapAssets :one
+SELECT count(id) FROM assets
+WHERE moderation_status = 'approved' AND NOT status AND expires_at > now();
@@ -0,0 +1,55 @@
+-- name: GetNewUsersPerBucket :many
+SELECT
+  date_trunc(sqlc.arg(bucket_size)::text, created_at)::timestamptz AS bucket,
+  count(username) AS count
+FROM users
+WHERE created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: GetNewAssetsPerBucket :many
+SELECT
+  date_trunc(sqlc.arg(bucket_size)::text, created_at)::timestamptz AS bucket,
+  property_type,
+  province,
+  count(id) AS count
+FROM assets
+WHERE created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+GROUP BY bucket, property_type, province
+ORDER BY bucket, property_type, province;
+
+-- name: GetAssetStatusCounts :one
+SELECT
+  count(id) FILTER (WHERE moderation_status = 'approved' AND NOT status) AS active_count,
+  count(id) FILTER (WHERE status) AS sold_count,
+  count(id) FILTER (WHERE moderation_status = 'pending') AS pending_count,
+  count(id) FILTER (WHERE moderation_status = 'rejected') AS rejected_count
+FROM assets
+WHERE created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time);
+
+-- name: GetPriceStats :many
+-- prices are in satang, listings priced in other currencies are converted
+SELECT
+  a.property_type,
+  a.province,
+  count(a.id) AS listing_count,
+  avg(a.price * er.rate * 100 / power(10::numeric, er.minor_units))::bigint AS average_price,
+  (percentile_cont(0.5) WITHIN GROUP (ORDER BY a.price * er.rate * 100 / power(10::numeric, er.minor_units)))::bigint AS median_price
+FROM assets a
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND a.created_at >= sqlc.arg(from_time) AND a.created_at < sqlc.arg(to_time)
+GROUP BY a.property_type, a.province
+ORDER BY a.property_type, a.province;
+
+-- name: GetTopSellers :many
+SELECT
+  owner,
+  count(id) AS listing_count,
+  count(id) FILTER (WHERE status) AS sold_count
+FROM assets
+WHERE moderation_status = 'approved'
+  AND created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+GROUP BY owner
+ORDER BY listing_count DESC, owner
+LIMIT sqlc.arg(seller_limit);
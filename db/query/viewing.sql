@@ -0,0 +1,112 @@
+-- name: CreateViewingSlot :one
+INSERT INTO viewing_slots
+    (asset_id, starts_at, ends_at)
+VALUES
+    ($1, $2, $3)
+RETURNING *;
+
+-- name: GetViewingSlot :one
+SELECT * FROM viewing_slots
+WHERE id = $1;
+
+-- name: DeleteViewingSlot :execrows
+DELETE FROM viewing_slots s
+WHERE s.id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM viewings v WHERE v.slot_id = s.id AND v.status = 'booked'
+  );
+
+-- name: ListAvailableViewingSlots :many
+SELECT s.id, s.asset_id, s.starts_at, s.ends_at, s.created_at FROM viewing_slots s
+WHERE s.asset_id = $1
+  AND s.starts_at > now()
+  AND NOT EXISTS (
+    SELECT 1 FROM viewings v WHERE v.slot_id = s.id AND v.status = 'booked'
+  )
+ORDER BY s.starts_at;
+
+-- name: ListViewingSlotsByAsset :many
+SELECT
+  s.id,
+  s.asset_id,
+  s.starts_at,
+  s.ends_at,
+  s.created_at,
+  v.id AS viewing_id,
+  v.buyer
+FROM viewing_slots s
+LEFT JOIN viewings v ON v.slot_id = s.id AND v.status = 'booked'
+WHERE s.asset_id = $1 AND s.ends_at > now()
+ORDER BY s.starts_at;
+
+-- name: CreateViewing :one
+INSERT INTO viewings
+    (slot_id, asset_id, buyer, note)
+VALUES
+    ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetViewingDetail :one
+SELECT
+  v.id,
+  v.slot_id,
+  v.asset_id,
+  v.buyer,
+  v.status,
+  v.note,
+  v.created_at,
+  v.updated_at,
+  s.starts_at,
+  s.ends_at,
+  a.owner AS seller,
+  a.detail AS asset_detail,
+  a.province
+FROM viewings v
+JOIN viewing_slots s ON s.id = v.slot_id
+JOIN assets a ON a.id = v.asset_id
+WHERE v.id = $1;
+
+-- name: RescheduleViewing :exec
+UPDATE viewings
+SET slot_id = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: CancelViewing :exec
+UPDATE viewings
+SET status = 'cancelled', updated_at = now()
+WHERE id = $1;
+
+-- name: GetBuyerViewings :many
+SELECT
+  v.id,
+  v.asset_id,
+  v.buyer,
+  v.status,
+  v.note,
+  s.starts_at,
+  s.ends_at,
+  a.owner AS seller
+FROM viewings v
+JOIN viewing_slots s ON s.id = v.slot_id
+JOIN assets a ON a.id = v.asset_id
+WHERE v.buyer = $1 AND v.status = 'booked' AND s.ends_at > now()
+ORDER BY s.starts_at;
+
+-- name: GetSellerCalendar :many
+SELECT
+  v.id,
+  v.asset_id,
+  v.buyer,
+  v.status,
+  v.note,
+  s.starts_at,
+  s.ends_at,
+  a.owner AS seller
+FROM viewings v
+JOIN viewing_slots s ON s.id = v.slot_id
+JOIN assets a ON a.id = v.asset_id
+WHERE a.owner = sqlc.arg(owner)
+  AND v.status = 'booked'
+  AND s.starts_at >= sqlc.arg(from_time)
+  AND s.starts_at < sqlc.arg(to_time)
+ORDER BY s.starts_at;
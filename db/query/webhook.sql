@@ -0,0 +1,124 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+  username,
+  agency_id,
+  url,
+  secret,
+  event_types,
+  created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE username = sqlc.arg(username)
+  OR (sqlc.narg(agency_id)::bigint IS NOT NULL AND agency_id = sqlc.narg(agency_id))
+ORDER BY id;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = $2, event_types = $3, active = $4, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: GetWebhookSubscriptionsForEvent :many
+SELECT * FROM webhook_subscriptions
+WHERE active
+  AND sqlc.arg(event_type)::varchar = ANY(event_types)
+  AND (
+    username = sqlc.arg(owner)
+    OR (sqlc.narg(agency_id)::bigint IS NOT NULL AND agency_id = sqlc.narg(agency_id))
+  );
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload,
+  redelivery_of
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: QueueWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+  subscription_id,
+  event_id,
+  event_type,
+  payload
+) VALUES (
+  $1, $2, $3, $4
+) ON CONFLICT (subscription_id, event_id) WHERE redelivery_of IS NULL DO NOTHING;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = $1
+ORDER BY id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountWebhookDeliveries :one
+SELECT count(id) FROM webhook_deliveries
+WHERE subscription_id = $1;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = sqlc.arg(lease_until), updated_at = now()
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id
+  AND d.id IN (
+    SELECT pending.id FROM webhook_deliveries pending
+    JOIN webhook_subscriptions sub ON sub.id = pending.subscription_id
+    WHERE pending.status = 'pending' AND pending.next_attempt_at <= now() AND sub.active
+    ORDER BY pending.next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE OF pending SKIP LOCKED
+  )
+  AND s.active
+RETURNING d.id, d.event_id, d.event_type, d.payload, d.attempts, s.url, s.secret;
+
+-- name: UpdateWebhookDeliveryResult :exec
+UPDATE webhook_deliveries
+SET
+  status = $2,
+  attempts = $3,
+  next_attempt_at = $4,
+  last_status_code = $5,
+  last_error = $6,
+  delivered_at = $7,
+  updated_at = now()
+WHERE id = $1 AND status = 'pending';
+
+-- name: CancelPendingWebhookDeliveries :execrows
+UPDATE webhook_deliveries
+SET status = 'cancelled', last_error = 'subscription was deactivated', updated_at = now()
+WHERE subscription_id = $1 AND status = 'pending';
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (
+  delivery_id,
+  attempt,
+  status_code,
+  error,
+  duration_ms
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: GetWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY attempt;
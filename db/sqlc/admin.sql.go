@@ -10,7 +10,7 @@ import (
 )
 
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT username, name, email, phone, password, profile_url, created_at, updated_at, roles FROM users 
+SELECT username, name, email, phone, password, profile_url, created_at, updated_at, roles, is_trusted FROM users 
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -39,6 +39,7 @@ func (q *Queries) GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]Use
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Roles,
+			&i.IsTrusted,
 		); err != nil {
 			return nil, err
 		}
@@ -52,3 +53,19 @@ func (q *Queries) GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]Use
 	}
 	return items, nil
 }
+
+const setUserTrusted = `-- name: SetUserTrusted :exec
+UPDATE users
+SET is_trusted = $2
+WHERE username = $1
+`
+
+type SetUserTrustedParams struct {
+	Username  string `json:"username"`
+	IsTrusted bool   `json:"is_trusted"`
+}
+
+func (q *Queries) SetUserTrusted(ctx context.Context, arg SetUserTrustedParams) error {
+	_, err := q.db.ExecContext(ctx, setUserTrusted, arg.Username, arg.IsTrusted)
+	return err
+}
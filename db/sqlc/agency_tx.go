@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrLastAgencyAdmin = errors.New("agency must keep at least one admin")
+
+// CreateAgencyTx registers an agency and makes its creator the first admin.
+func (store *Store) CreateAgencyTx(ctx context.Context, arg CreateAgencyParams) (Agency, error) {
+	var agency Agency
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		agency, err = q.CreateAgency(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAgencyMember(ctx, AddAgencyMemberParams{
+			AgencyID: agency.ID,
+			Username: arg.CreatedBy,
+			Role:     AgencyRoleAdmin,
+		})
+		return err
+	})
+
+	return agency, err
+}
+
+// lockAgencyMember locks the agency so admin counts cannot change underneath
+// us and returns the member's current membership.
+func lockAgencyMember(ctx context.Context, q *Queries, agencyID int64, username string) (AgencyMember, error) {
+	if _, err := q.GetAgencyForUpdate(ctx, agencyID); err != nil {
+		return AgencyMember{}, err
+	}
+
+	member, err := q.GetAgencyMembership(ctx, username)
+	if err != nil {
+		return member, err
+	}
+
+	if member.AgencyID != agencyID {
+		return member, sql.ErrNoRows
+	}
+
+	return member, nil
+}
+
+func ensureAnotherAdmin(ctx context.Context, q *Queries, member AgencyMember) error {
+	if member.Role != AgencyRoleAdmin {
+		return nil
+	}
+
+	admins, err := q.CountAgencyAdmins(ctx, member.AgencyID)
+	if err != nil {
+		return err
+	}
+
+	if admins <= 1 {
+		return ErrLastAgencyAdmin
+	}
+
+	return nil
+}
+
+// UpdateAgencyMemberRoleTx changes a member's role without ever leaving the
+// agency without an admin.
+func (store *Store) UpdateAgencyMemberRoleTx(ctx context.Context, arg UpdateAgencyMemberRoleParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		member, err := lockAgencyMember(ctx, q, arg.AgencyID, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		if arg.Role != AgencyRoleAdmin {
+			if err := ensureAnotherAdmin(ctx, q, member); err != nil {
+				return err
+			}
+		}
+
+		_, err = q.UpdateAgencyMemberRole(ctx, arg)
+		return err
+	})
+}
+
+// RemoveAgencyMemberTx removes a member and hands their listings back to the
+// agency unassigned.
+func (store *Store) RemoveAgencyMemberTx(ctx context.Context, arg RemoveAgencyMemberParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		member, err := lockAgencyMember(ctx, q, arg.AgencyID, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		if err := ensureAnotherAdmin(ctx, q, member); err != nil {
+			return err
+		}
+
+		if _, err := q.RemoveAgencyMember(ctx, arg); err != nil {
+			return err
+		}
+
+		return q.UnassignAgentAssets(ctx, UnassignAgentAssetsParams{
+			AgencyID: sql.NullInt64{Int64: arg.AgencyID, Valid: true},
+			Agent:    sql.NullString{String: arg.Username, Valid: true},
+		})
+	})
+}
+
+type AcceptAgencyInviteTxParams struct {
+	ID       int64
+	Username string
+}
+
+// AcceptAgencyInviteTx makes the invited user a member of the agency and drops
+// their other invites, a user works for one agency only. An invite of someone
+// else is reported as sql.ErrNoRows.
+func (store *Store) AcceptAgencyInviteTx(ctx context.Context, arg AcceptAgencyInviteTxParams) (AgencyMember, error) {
+	var member AgencyMember
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		invite, err := q.GetAgencyInviteForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if invite.Username != arg.Username {
+			return sql.ErrNoRows
+		}
+
+		member, err = q.AddAgencyMember(ctx, AddAgencyMemberParams{
+			AgencyID: invite.AgencyID,
+			Username: invite.Username,
+			Role:     invite.Role,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteAgencyInvitesByUser(ctx, invite.Username)
+	})
+
+	return member, err
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type RecordAssetViewsTxParams struct {
+	AssetID  int64
+	Day      time.Time
+	Views    int32
+	Visitors []string
+}
+
+// RecordAssetViewsTx adds a batch of views to an asset's daily stats,
+// counting only visitors that were not seen on that day yet.
+func (store *Store) RecordAssetViewsTx(ctx context.Context, arg RecordAssetViewsTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		newVisitors, err := q.InsertAssetVisitors(ctx, InsertAssetVisitorsParams{
+			AssetID:  arg.AssetID,
+			Day:      arg.Day,
+			Visitors: arg.Visitors,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.AddAssetDailyStats(ctx, AddAssetDailyStatsParams{
+			AssetID:        arg.AssetID,
+			Day:            arg.Day,
+			Views:          arg.Views,
+			UniqueVisitors: int32(newVisitors),
+		})
+	})
+}
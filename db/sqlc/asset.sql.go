@@ -11,6 +11,44 @@ import (
 	"time"
 )
 
+const archiveExpiredAssets = `-- name: ArchiveExpiredAssets :many
+UPDATE assets
+SET archived_at = now()
+WHERE archived_at IS NULL
+  AND NOT status
+  AND expires_at <= now()
+RETURNING id, owner, expires_at
+`
+
+type ArchiveExpiredAssetsRow struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) ArchiveExpiredAssets(ctx context.Context) ([]ArchiveExpiredAssetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, archiveExpiredAssets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ArchiveExpiredAssetsRow{}
+	for rows.Next() {
+		var i ArchiveExpiredAssetsRow
+		if err := rows.Scan(&i.ID, &i.Owner, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteAsset = `-- name: DeleteAsset :exec
 DELETE FROM assets
 WHERE id = $1
@@ -28,42 +66,107 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   ac.id AS contact_id,
   ac.contact_name,
   ac.contact_detail,
   ai.id AS image_id,
-  ai.image_url
+  ai.image_url,
+  ph.old_price AS previous_price,
+  ph.changed_at AS price_changed_at
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
-ORDER BY a.id DESC
-LIMIT $1 OFFSET $2
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
+-- price filters are in satang, prices are in minor units of the listing's
+-- currency and converted
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND a.expires_at > now()
+  AND ($1::property_type IS NULL OR a.property_type = $1)
+  AND ($2::varchar IS NULL OR a.province = $2)
+  AND ($3::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) >= $3)
+  AND ($4::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) <= $4)
+  AND ($5::timestamptz IS NULL OR (ph.changed_at >= $5 AND ph.old_price > a.price))
+ORDER BY
+  pr.promotion_rank NULLS LAST,
+  CASE WHEN $6::varchar = 'recently_reduced' AND ph.old_price > a.price THEN ph.changed_at END DESC NULLS LAST,
+  a.id DESC
+LIMIT $7 OFFSET $8
 `
 
 type GetAllAssetsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	PropertyType NullPropertyType `json:"property_type"`
+	Province     sql.NullString   `json:"province"`
+	MinPrice     sql.NullInt64    `json:"min_price"`
+	MaxPrice     sql.NullInt64    `json:"max_price"`
+	ReducedSince sql.NullTime     `json:"reduced_since"`
+	Sort         string           `json:"sort"`
+	PageLimit    int32            `json:"page_limit"`
+	PageOffset   int32            `json:"page_offset"`
 }
 
 type GetAllAssetsRow struct {
-	ID            int64          `json:"id"`
-	Owner         string         `json:"owner"`
-	Price         int64          `json:"price"`
-	Detail        string         `json:"detail"`
-	Status        bool           `json:"status"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	ContactID     sql.NullInt64  `json:"contact_id"`
-	ContactName   sql.NullString `json:"contact_name"`
-	ContactDetail sql.NullString `json:"contact_detail"`
-	ImageID       sql.NullInt64  `json:"image_id"`
-	ImageUrl      sql.NullString `json:"image_url"`
+	ID               int64            `json:"id"`
+	Owner            string           `json:"owner"`
+	Price            int64            `json:"price"`
+	Detail           string           `json:"detail"`
+	Status           bool             `json:"status"`
+	UnderOffer       bool             `json:"under_offer"`
+	AgencyID         sql.NullInt64    `json:"agency_id"`
+	Agent            sql.NullString   `json:"agent"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationReason sql.NullString   `json:"moderation_reason"`
+	PropertyType     PropertyType     `json:"property_type"`
+	Province         string           `json:"province"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Slug             string           `json:"slug"`
+	PrimaryLocale    string           `json:"primary_locale"`
+	Currency         string           `json:"currency"`
+	ContactID        sql.NullInt64    `json:"contact_id"`
+	ContactName      sql.NullString   `json:"contact_name"`
+	ContactDetail    sql.NullString   `json:"contact_detail"`
+	ImageID          sql.NullInt64    `json:"image_id"`
+	ImageUrl         sql.NullString   `json:"image_url"`
+	PreviousPrice    sql.NullInt64    `json:"previous_price"`
+	PriceChangedAt   sql.NullTime     `json:"price_changed_at"`
 }
 
 func (q *Queries) GetAllAssets(ctx context.Context, arg GetAllAssetsParams) ([]GetAllAssetsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllAssets, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getAllAssets,
+		arg.PropertyType,
+		arg.Province,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.ReducedSince,
+		arg.Sort,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +180,151 @@ func (q *Queries) GetAllAssets(ctx context.Context, arg GetAllAssetsParams) ([]G
 			&i.Price,
 			&i.Detail,
 			&i.Status,
+			&i.UnderOffer,
+			&i.AgencyID,
+			&i.Agent,
+			&i.ExpiresAt,
+			&i.ModerationStatus,
+			&i.ModerationReason,
+			&i.PropertyType,
+			&i.Province,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PrimaryLocale,
+			&i.Currency,
+			&i.ContactID,
+			&i.ContactName,
+			&i.ContactDetail,
+			&i.ImageID,
+			&i.ImageUrl,
+			&i.PreviousPrice,
+			&i.PriceChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getApprovedAssetCountByUsername = `-- name: GetApprovedAssetCountByUsername :one
+SELECT count(id) FROM assets
+WHERE owner = $1 AND moderation_status = 'approved' AND expires_at > now()
+`
+
+func (q *Queries) GetApprovedAssetCountByUsername(ctx context.Context, owner string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getApprovedAssetCountByUsername, owner)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getApprovedAssetsByUsername = `-- name: GetApprovedAssetsByUsername :many
+SELECT 
+  a.id,
+  a.owner,
+  a.price,
+  a.detail,
+  a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
+  a.created_at,
+  a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
+  MIN(ac.id) AS contact_id,
+  MIN(ac.contact_name) AS contact_name,
+  MIN(ac.contact_detail) AS contact_detail,
+  MIN(ai.id) AS image_id,
+  MIN(ai.image_url) AS image_url
+FROM assets a
+LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
+LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
+WHERE a.owner = $1 AND a.moderation_status = 'approved' AND a.expires_at > now()
+GROUP BY a.id
+ORDER BY MIN(pr.promotion_rank) NULLS LAST, a.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetApprovedAssetsByUsernameParams struct {
+	Owner  string `json:"owner"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type GetApprovedAssetsByUsernameRow struct {
+	ID               int64            `json:"id"`
+	Owner            string           `json:"owner"`
+	Price            int64            `json:"price"`
+	Detail           string           `json:"detail"`
+	Status           bool             `json:"status"`
+	UnderOffer       bool             `json:"under_offer"`
+	AgencyID         sql.NullInt64    `json:"agency_id"`
+	Agent            sql.NullString   `json:"agent"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationReason sql.NullString   `json:"moderation_reason"`
+	PropertyType     PropertyType     `json:"property_type"`
+	Province         string           `json:"province"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Slug             string           `json:"slug"`
+	PrimaryLocale    string           `json:"primary_locale"`
+	Currency         string           `json:"currency"`
+	ContactID        interface{}      `json:"contact_id"`
+	ContactName      interface{}      `json:"contact_name"`
+	ContactDetail    interface{}      `json:"contact_detail"`
+	ImageID          interface{}      `json:"image_id"`
+	ImageUrl         interface{}      `json:"image_url"`
+}
+
+func (q *Queries) GetApprovedAssetsByUsername(ctx context.Context, arg GetApprovedAssetsByUsernameParams) ([]GetApprovedAssetsByUsernameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getApprovedAssetsByUsername, arg.Owner, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetApprovedAssetsByUsernameRow{}
+	for rows.Next() {
+		var i GetApprovedAssetsByUsernameRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Price,
+			&i.Detail,
+			&i.Status,
+			&i.UnderOffer,
+			&i.AgencyID,
+			&i.Agent,
+			&i.ExpiresAt,
+			&i.ModerationStatus,
+			&i.ModerationReason,
+			&i.PropertyType,
+			&i.Province,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Slug,
+			&i.PrimaryLocale,
+			&i.Currency,
 			&i.ContactID,
 			&i.ContactName,
 			&i.ContactDetail,
@@ -105,32 +351,64 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   ac.id AS contact_id,
   ac.contact_name,
   ac.contact_detail,
   ai.id AS image_id,
-  ai.image_url
+  ai.image_url,
+  ph.old_price AS previous_price,
+  ph.changed_at AS price_changed_at
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
 WHERE a.id = $1
 `
 
 type GetAssetByIdRow struct {
-	ID            int64          `json:"id"`
-	Owner         string         `json:"owner"`
-	Price         int64          `json:"price"`
-	Detail        string         `json:"detail"`
-	Status        bool           `json:"status"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	ContactID     sql.NullInt64  `json:"contact_id"`
-	ContactName   sql.NullString `json:"contact_name"`
-	ContactDetail sql.NullString `json:"contact_detail"`
-	ImageID       sql.NullInt64  `json:"image_id"`
-	ImageUrl      sql.NullString `json:"image_url"`
+	ID               int64            `json:"id"`
+	Owner            string           `json:"owner"`
+	Price            int64            `json:"price"`
+	Detail           string           `json:"detail"`
+	Status           bool             `json:"status"`
+	UnderOffer       bool             `json:"under_offer"`
+	AgencyID         sql.NullInt64    `json:"agency_id"`
+	Agent            sql.NullString   `json:"agent"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationReason sql.NullString   `json:"moderation_reason"`
+	PropertyType     PropertyType     `json:"property_type"`
+	Province         string           `json:"province"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Slug             string           `json:"slug"`
+	PrimaryLocale    string           `json:"primary_locale"`
+	Currency         string           `json:"currency"`
+	ContactID        sql.NullInt64    `json:"contact_id"`
+	ContactName      sql.NullString   `json:"contact_name"`
+	ContactDetail    sql.NullString   `json:"contact_detail"`
+	ImageID          sql.NullInt64    `json:"image_id"`
+	ImageUrl         sql.NullString   `json:"image_url"`
+	PreviousPrice    sql.NullInt64    `json:"previous_price"`
+	PriceChangedAt   sql.NullTime     `json:"price_changed_at"`
 }
 
 func (q *Queries) GetAssetById(ctx context.Context, id int64) (GetAssetByIdRow, error) {
@@ -142,23 +420,64 @@ func (q *Queries) GetAssetById(ctx context.Context, id int64) (GetAssetByIdRow,
 		&i.Price,
 		&i.Detail,
 		&i.Status,
+		&i.UnderOffer,
+		&i.AgencyID,
+		&i.Agent,
+		&i.ExpiresAt,
+		&i.ModerationStatus,
+		&i.ModerationReason,
+		&i.PropertyType,
+		&i.Province,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.PrimaryLocale,
+		&i.Currency,
 		&i.ContactID,
 		&i.ContactName,
 		&i.ContactDetail,
 		&i.ImageID,
 		&i.ImageUrl,
+		&i.PreviousPrice,
+		&i.PriceChangedAt,
 	)
 	return i, err
 }
 
 const getAssetCount = `-- name: GetAssetCount :one
-SELECT count(id) FROM assets
+SELECT count(a.id) FROM assets a
+LEFT JOIN LATERAL (
+  SELECT h.old_price, h.changed_at FROM asset_price_history h
+  WHERE h.asset_id = a.id
+  ORDER BY h.id DESC
+  LIMIT 1
+) ph ON true
+JOIN exchange_rates er ON er.currency = a.currency
+WHERE a.moderation_status = 'approved'
+  AND a.expires_at > now()
+  AND ($1::property_type IS NULL OR a.property_type = $1)
+  AND ($2::varchar IS NULL OR a.province = $2)
+  AND ($3::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) >= $3)
+  AND ($4::bigint IS NULL OR a.price * er.rate * 100 / power(10::numeric, er.minor_units) <= $4)
+  AND ($5::timestamptz IS NULL OR (ph.changed_at >= $5 AND ph.old_price > a.price))
 `
 
-func (q *Queries) GetAssetCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getAssetCount)
+type GetAssetCountParams struct {
+	PropertyType NullPropertyType `json:"property_type"`
+	Province     sql.NullString   `json:"province"`
+	MinPrice     sql.NullInt64    `json:"min_price"`
+	MaxPrice     sql.NullInt64    `json:"max_price"`
+	ReducedSince sql.NullTime     `json:"reduced_since"`
+}
+
+func (q *Queries) GetAssetCount(ctx context.Context, arg GetAssetCountParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAssetCount,
+		arg.PropertyType,
+		arg.Province,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.ReducedSince,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -176,6 +495,19 @@ func (q *Queries) GetAssetCountByUsername(ctx context.Context, owner string) (in
 	return count, err
 }
 
+const getAssetPriceForUpdate = `-- name: GetAssetPriceForUpdate :one
+SELECT price FROM assets
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetAssetPriceForUpdate(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAssetPriceForUpdate, id)
+	var price int64
+	err := row.Scan(&price)
+	return price, err
+}
+
 const getAssetsByUsername = `-- name: GetAssetsByUsername :many
 SELECT 
   a.id,
@@ -183,8 +515,19 @@ SELECT
   a.price,
   a.detail,
   a.status,
+  a.under_offer,
+  a.agency_id,
+  a.agent,
+  a.expires_at,
+  a.moderation_status,
+  a.moderation_reason,
+  a.property_type,
+  a.province,
   a.created_at,
   a.updated_at,
+  a.slug,
+  a.primary_locale,
+  a.currency,
   MIN(ac.id) AS contact_id,
   MIN(ac.contact_name) AS contact_name,
   MIN(ac.contact_detail) AS contact_detail,
@@ -193,9 +536,14 @@ SELECT
 FROM assets a
 LEFT JOIN asset_contacts ac ON ac.asset_id = a.id
 LEFT JOIN asset_images ai ON ai.asset_id = a.id
+LEFT JOIN LATERAL (
+  SELECT min(CASE p.type WHEN 'top_of_search' THEN 0 WHEN 'featured' THEN 1 END) AS promotion_rank
+  FROM asset_promotions p
+  WHERE p.asset_id = a.id AND p.status = 'active' AND p.starts_at <= now() AND p.ends_at > now()
+) pr ON true
 WHERE a.owner = $1
 GROUP BY a.id
-ORDER BY a.id DESC
+ORDER BY MIN(pr.promotion_rank) NULLS LAST, a.id DESC
 LIMIT $2 OFFSET $3
 `
 
@@ -206,18 +554,29 @@ type GetAssetsByUsernameParams struct {
 }
 
 type GetAssetsByUsernameRow struct {
-	ID            int64       `json:"id"`
-	Owner         string      `json:"owner"`
-	Price         int64       `json:"price"`
-	Detail        string      `json:"detail"`
-	Status        bool        `json:"status"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
-	ContactID     interface{} `json:"contact_id"`
-	ContactName   interface{} `json:"contact_name"`
-	ContactDetail interface{} `json:"contact_detail"`
-	ImageID       interface{} `json:"image_id"`
-	ImageUrl      interface{} `json:"image_url"`
+	ID               int64            `json:"id"`
+	Owner            string           `json:"owner"`
+	Price            int64            `json:"price"`
+	Detail           string           `json:"detail"`
+	Status           bool             `json:"status"`
+	UnderOffer       bool             `json:"under_offer"`
+	AgencyID         sql.NullInt64    `json:"agency_id"`
+	Agent            sql.NullString   `json:"agent"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationReason sql.NullString   `json:"moderation_reason"`
+	PropertyType     PropertyType     `json:"property_type"`
+	Province         string           `json:"province"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Slug             string           `json:"slug"`
+	PrimaryLocale    string           `json:"primary_locale"`
+	Currency         string           `json:"currency"`
+	ContactID        interface{}      `json:"contact_id"`
+	ContactName      interface{}      `json:"contact_name"`
+	ContactDetail    interface{}      `json:"contact_detail"`
+	ImageID          interface{}      `json:"image_id"`
+	ImageUrl         interface{}      `json:"image_url"`
 }
 
 func (q *Queries) GetAssetsByUsername(ctx context.Context, arg GetAssetsByUsernameParams) ([]GetAssetsByUsernameRow, error) {
@@ -235,8 +594,19 @@ func (q *Queries) GetAssetsByUsername(ctx context.Context, arg GetAssetsByUserna
 			&i.Price,
 			&i.Detail,
 			&i.Status,
+			&i.UnderOffer,
+			&i.AgencyID,
+			&i.Agent,
+			&i.ExpiresAt,
+			&i.ModerationStatus,
+			&i.ModerationReason,
+			&i.PropertyType,
+			&i.Province,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Slug,
+			&i.PrimaryLocale,
+			&i.Currency,
 			&i.ContactID,
 			&i.ContactName,
 			&i.ContactDetail,
@@ -257,21 +627,54 @@ func (q *Queries) GetAssetsByUsername(ctx context.Context, arg GetAssetsByUserna
 }
 
 const insertAsset = `-- name: InsertAsset :one
+WITH next AS (
+  SELECT nextval(pg_get_serial_sequence('assets', 'id')) AS id
+)
 INSERT INTO assets 
-    (owner, price, detail)
+    (id, owner, price, detail, moderation_status, property_type, province, expires_at, primary_locale, currency, slug)
 VALUES 
-    ($1, $2, $3)
-RETURNING id, owner, price, detail, status, created_at, updated_at
+    (
+      (SELECT id FROM next),
+      $1,
+      $2,
+      $3,
+      $4,
+      $5,
+      $6,
+      $7,
+      $8,
+      $9,
+      $10::varchar || '-' || (SELECT id FROM next)
+    )
+RETURNING id, owner, price, detail, status, created_at, updated_at, moderation_status, moderation_reason, moderated_by, moderation_updated_at, property_type, province, under_offer, agency_id, agent, expires_at, expiry_warned_at, archived_at, slug, primary_locale, currency
 `
 
 type InsertAssetParams struct {
-	Owner  string `json:"owner"`
-	Price  int64  `json:"price"`
-	Detail string `json:"detail"`
+	Owner            string           `json:"owner"`
+	Price            int64            `json:"price"`
+	Detail           string           `json:"detail"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	PropertyType     PropertyType     `json:"property_type"`
+	Province         string           `json:"province"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	PrimaryLocale    string           `json:"primary_locale"`
+	Currency         string           `json:"currency"`
+	SlugBase         string           `json:"slug_base"`
 }
 
 func (q *Queries) InsertAsset(ctx context.Context, arg InsertAssetParams) (Asset, error) {
-	row := q.db.QueryRowContext(ctx, insertAsset, arg.Owner, arg.Price, arg.Detail)
+	row := q.db.QueryRowContext(ctx, insertAsset,
+		arg.Owner,
+		arg.Price,
+		arg.Detail,
+		arg.ModerationStatus,
+		arg.PropertyType,
+		arg.Province,
+		arg.ExpiresAt,
+		arg.PrimaryLocale,
+		arg.Currency,
+		arg.SlugBase,
+	)
 	var i Asset
 	err := row.Scan(
 		&i.ID,
@@ -281,13 +684,61 @@ func (q *Queries) InsertAsset(ctx context.Context, arg InsertAssetParams) (Asset
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ModerationStatus,
+		&i.ModerationReason,
+		&i.ModeratedBy,
+		&i.ModerationUpdatedAt,
+		&i.PropertyType,
+		&i.Province,
+		&i.UnderOffer,
+		&i.AgencyID,
+		&i.Agent,
+		&i.ExpiresAt,
+		&i.ExpiryWarnedAt,
+		&i.ArchivedAt,
+		&i.Slug,
+		&i.PrimaryLocale,
+		&i.Currency,
 	)
 	return i, err
 }
 
+const markAssetSold = `-- name: MarkAssetSold :execrows
+UPDATE assets
+SET status = true, under_offer = false, updated_at = now()
+WHERE id = $1 AND NOT status
+`
+
+func (q *Queries) MarkAssetSold(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markAssetSold, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renewAsset = `-- name: RenewAsset :one
+UPDATE assets
+SET expires_at = $2, expiry_warned_at = NULL, archived_at = NULL, updated_at = now()
+WHERE id = $1
+RETURNING expires_at
+`
+
+type RenewAssetParams struct {
+	ID        int64     `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) RenewAsset(ctx context.Context, arg RenewAssetParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, renewAsset, arg.ID, arg.ExpiresAt)
+	var expires_at time.Time
+	err := row.Scan(&expires_at)
+	return expires_at, err
+}
+
 const updateAsset = `-- name: UpdateAsset :exec
 UPDATE assets
-SET price = coalesce($1, price), detail = coalesce($2, detail)
+SET price = coalesce($1, price), detail = coalesce($2, detail), updated_at = now()
 WHERE id = $3
 `
 
@@ -301,3 +752,43 @@ func (q *Queries) UpdateAsset(ctx context.Context, arg UpdateAssetParams) error
 	_, err := q.db.ExecContext(ctx, updateAsset, arg.Price, arg.Detail, arg.ID)
 	return err
 }
+
+const warnExpiringAssets = `-- name: WarnExpiringAssets :many
+UPDATE assets
+SET expiry_warned_at = now()
+WHERE archived_at IS NULL
+  AND expiry_warned_at IS NULL
+  AND NOT status
+  AND expires_at > now()
+  AND expires_at <= $1
+RETURNING id, owner, expires_at
+`
+
+type WarnExpiringAssetsRow struct {
+	ID        int64     `json:"id"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) WarnExpiringAssets(ctx context.Context, warnBefore time.Time) ([]WarnExpiringAssetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, warnExpiringAssets, warnBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WarnExpiringAssetsRow{}
+	for rows.Next() {
+		var i WarnExpiringAssetsRow
+		if err := rows.Scan(&i.ID, &i.Owner, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
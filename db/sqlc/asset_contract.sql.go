@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const getAssetContacts = `-- name: GetAssetContacts :many
@@ -44,6 +46,42 @@ func (q *Queries) GetAssetContacts(ctx context.Context, assetID int64) ([]AssetC
 	return items, nil
 }
 
+const getAssetContactsByAssetIDs = `-- name: GetAssetContactsByAssetIDs :many
+SELECT id, asset_id, contact_name, contact_detail, created_at, updated_at FROM asset_contacts
+WHERE asset_id = ANY($1::bigint[])
+ORDER BY asset_id, id
+`
+
+func (q *Queries) GetAssetContactsByAssetIDs(ctx context.Context, assetIds []int64) ([]AssetContact, error) {
+	rows, err := q.db.QueryContext(ctx, getAssetContactsByAssetIDs, pq.Array(assetIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AssetContact{}
+	for rows.Next() {
+		var i AssetContact
+		if err := rows.Scan(
+			&i.ID,
+			&i.AssetID,
+			&i.ContactName,
+			&i.ContactDetail,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getContact = `-- name: GetContact :one
 SELECT id, asset_id, contact_name, contact_detail, created_at, updated_at FROM asset_contacts 
 WHERE id = $1
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AssetFilter holds the listing filters shared by the public listing endpoint
+// and saved searches, where it is stored as JSON.
+type AssetFilter struct {
+	PropertyType string `json:"property_type,omitempty"`
+	Province     string `json:"province,omitempty"`
+	// in satang, listings priced in other currencies are converted
+	MinPrice *int64 `json:"min_price,omitempty"`
+	MaxPrice *int64 `json:"max_price,omitempty"`
+	// listings whose price dropped in the last ReducedWithin days
+	ReducedWithin *int `json:"reduced_within,omitempty"`
+}
+
+func (f AssetFilter) NullPropertyType() NullPropertyType {
+	return NullPropertyType{PropertyType: PropertyType(f.PropertyType), Valid: f.PropertyType != ""}
+}
+
+func (f AssetFilter) NullProvince() sql.NullString {
+	return sql.NullString{String: f.Province, Valid: f.Province != ""}
+}
+
+func (f AssetFilter) NullMinPrice() sql.NullInt64 {
+	if f.MinPrice == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *f.MinPrice, Valid: true}
+}
+
+func (f AssetFilter) NullMaxPrice() sql.NullInt64 {
+	if f.MaxPrice == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *f.MaxPrice, Valid: true}
+}
+
+// NullReducedSince is when the ReducedWithin window starts, counted from now
+// so a saved search keeps looking back the same number of days.
+func (f AssetFilter) NullReducedSince() sql.NullTime {
+	if f.ReducedWithin == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: time.Now().AddDate(0, 0, -*f.ReducedWithin), Valid: true}
+}
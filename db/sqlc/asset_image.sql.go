@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const deleteImage = `-- name: DeleteImage :exec
@@ -19,6 +21,63 @@ func (q *Queries) DeleteImage(ctx context.Context, id int64) error {
 	return err
 }
 
+const getAssetImages = `-- name: GetAssetImages :many
+SELECT id, asset_id, image_url FROM asset_images
+WHERE asset_id = $1
+`
+
+func (q *Queries) GetAssetImages(ctx context.Context, assetID int64) ([]AssetImage, error) {
+	rows, err := q.db.QueryContext(ctx, getAssetImages, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AssetImage{}
+	for rows.Next() {
+		var i AssetImage
+		if err := rows.Scan(&i.ID, &i.AssetID, &i.ImageUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAssetImagesByAssetIDs = `-- name: GetAssetImagesByAssetIDs :many
+SELECT id, asset_id, image_url FROM asset_images
+WHERE asset_id = ANY($1::bigint[])
+ORDER BY asset_id, id
+`
+
+func (q *Queries) GetAssetImagesByAssetIDs(ctx context.Context, assetIds []int64) ([]AssetImage, error) {
+	rows, err := q.db.QueryContext(ctx, getAssetImagesByAssetIDs, pq.Array(assetIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AssetImage{}
+	for rows.Next() {
+		var i AssetImage
+		if err := rows.Scan(&i.ID, &i.AssetID, &i.ImageUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getImageById = `-- name: GetImageById :one
 SELECT id, asset_id, image_url FROM asset_images
 WHERE id = $1
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+type ImportAssetTxParams struct {
+	ImportID         int64
+	AgencyID         int64
+	Asset            InsertAssetParams
+	ModerationReason string
+	ContactName      string
+	ContactDetail    string
+	ImageUrls        []string
+}
+
+// ImportAssetTx creates one listing of a bulk import with its contact and
+// images, and counts the row as imported in the same transaction, so a
+// retried import never creates the same listing twice.
+func (store *Store) ImportAssetTx(ctx context.Context, arg ImportAssetTxParams) (Asset, error) {
+	var asset Asset
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.Asset.PrimaryLocale == "" {
+			arg.Asset.PrimaryLocale = DefaultLocale
+		}
+		if arg.Asset.Currency == "" {
+			arg.Asset.Currency = DefaultCurrency
+		}
+		arg.Asset.SlugBase = AssetSlugBase(arg.Asset.PropertyType, arg.Asset.Province, arg.Asset.Detail)
+
+		var err error
+		asset, err = q.InsertAsset(ctx, arg.Asset)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.InsertModerationLog(ctx, InsertModerationLogParams{
+			AssetID: asset.ID,
+			Status:  arg.Asset.ModerationStatus,
+			Reason:  sql.NullString{String: arg.ModerationReason, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.SetAssetAgency(ctx, SetAssetAgencyParams{
+			ID:       asset.ID,
+			AgencyID: sql.NullInt64{Int64: arg.AgencyID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		asset.AgencyID = sql.NullInt64{Int64: arg.AgencyID, Valid: true}
+
+		if arg.ContactName != "" {
+			_, err = q.InsertAssetContact(ctx, InsertAssetContactParams{
+				AssetID:       asset.ID,
+				ContactName:   arg.ContactName,
+				ContactDetail: arg.ContactDetail,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, imageUrl := range arg.ImageUrls {
+			_, err = q.InsertAssetImage(ctx, InsertAssetImageParams{
+				AssetID:  asset.ID,
+				ImageUrl: imageUrl,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		err = q.writeOutbox(ctx, assetEvent(EventAssetCreated, asset.ID, asset))
+		if err != nil {
+			return err
+		}
+
+		return q.RecordAssetImportRow(ctx, RecordAssetImportRowParams{
+			Imported:  true,
+			RowErrors: json.RawMessage("[]"),
+			ID:        arg.ImportID,
+		})
+	})
+
+	return asset, err
+}
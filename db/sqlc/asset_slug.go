@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// slugs are cut at a word boundary before this many characters, the asset id
+// is added after that
+const maxSlugBaseLength = 60
+
+// AssetSlugBase builds the readable part of a listing's slug from its type,
+// province and title, e.g. "condo-bangkok-2-bed-near-bts". Listings without
+// a title pass their detail, only its first line is used. Thai and other
+// scripts are kept as they are; InsertAsset appends the asset id, which
+// makes the slug unique.
+func AssetSlugBase(propertyType PropertyType, province, title string) string {
+	title, _, _ = strings.Cut(title, "\n")
+
+	var b strings.Builder
+	length := 0
+	for _, word := range strings.FieldsFunc(string(propertyType)+" "+province+" "+title, isSlugSeparator) {
+		runes := []rune(strings.ToLower(word))
+		if length > 0 && length+1+len(runes) > maxSlugBaseLength {
+			break
+		}
+		// a first word longer than the limit is cut instead of dropped
+		if len(runes) > maxSlugBaseLength {
+			runes = runes[:maxSlugBaseLength]
+		}
+
+		if length > 0 {
+			b.WriteByte('-')
+			length++
+		}
+		b.WriteString(string(runes))
+		length += len(runes)
+	}
+
+	slug := b.String()
+	if slug == "" {
+		return "listing"
+	}
+	return slug
+}
+
+// isSlugSeparator keeps letters and digits, along with the combining marks
+// that Thai writes its vowels and tones with.
+func isSlugSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+}
+
+// refreshAssetSlug rebuilds the slug of an edited asset. When it changes the
+// old slug keeps redirecting to the asset.
+func (q *Queries) refreshAssetSlug(ctx context.Context, id int64) error {
+	source, err := q.GetAssetSlugSource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	title := source.Detail
+	if source.Title.Valid {
+		title = source.Title.String
+	}
+
+	slug := AssetSlugBase(source.PropertyType, source.Province, title) + "-" + strconv.FormatInt(id, 10)
+	if slug == source.Slug {
+		return nil
+	}
+
+	err = q.AddAssetSlugRedirect(ctx, AddAssetSlugRedirectParams{Slug: source.Slug, AssetID: id})
+	if err != nil {
+		return err
+	}
+
+	// the asset may go back to a slug it had before
+	if err := q.DeleteAssetSlugRedirect(ctx, slug); err != nil {
+		return err
+	}
+
+	return q.SetAssetSlug(ctx, SetAssetSlugParams{Slug: slug, ID: id})
+}
@@ -0,0 +1,71 @@
+package db
+
+import "context"
+
+// DefaultLocale is the primary language of listings that do not name one.
+const DefaultLocale = "th"
+
+// UpsertAssetTranslationTx stores the title and description of an asset in
+// one locale. The translation of the primary locale also becomes the asset's
+// detail and the title its slug is built from, so they never disagree.
+func (store *Store) UpsertAssetTranslationTx(ctx context.Context, arg UpsertAssetTranslationParams) (AssetTranslation, error) {
+	var translation AssetTranslation
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		primaryLocale, err := q.GetAssetPrimaryLocale(ctx, arg.AssetID)
+		if err != nil {
+			return err
+		}
+
+		translation, err = q.UpsertAssetTranslation(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if arg.Locale == primaryLocale {
+			err = q.SetAssetDetail(ctx, SetAssetDetailParams{ID: arg.AssetID, Detail: arg.Description})
+			if err != nil {
+				return err
+			}
+
+			if err := q.refreshAssetSlug(ctx, arg.AssetID); err != nil {
+				return err
+			}
+		}
+
+		return q.writeOutbox(ctx, assetEvent(EventAssetUpdated, arg.AssetID, map[string]any{
+			"id":          arg.AssetID,
+			"locale":      arg.Locale,
+			"title":       arg.Title,
+			"description": arg.Description,
+		}))
+	})
+
+	return translation, err
+}
+
+// DeleteAssetTranslationTx removes the translation of one locale and returns
+// the number of translations deleted.
+func (store *Store) DeleteAssetTranslationTx(ctx context.Context, arg DeleteAssetTranslationParams) (int64, error) {
+	var rows int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		rows, err = q.DeleteAssetTranslation(ctx, arg)
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		// without a title of the primary locale the slug goes back to the detail
+		if err := q.refreshAssetSlug(ctx, arg.AssetID); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, assetEvent(EventAssetUpdated, arg.AssetID, map[string]any{
+			"id":     arg.AssetID,
+			"locale": arg.Locale,
+		}))
+	})
+
+	return rows, err
+}
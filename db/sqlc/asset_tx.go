@@ -0,0 +1,148 @@
+package db
+
+import "context"
+
+type UpdateAssetTxParams struct {
+	ID        int64
+	Price     int64
+	Detail    string
+	ChangedBy string
+}
+
+type UpdateAssetTxResult struct {
+	OldPrice     int64
+	PriceChanged bool
+}
+
+// UpdateAssetTx updates an asset and, when the price moves, records the change
+// in the asset's price history. The slug follows a changed detail.
+func (store *Store) UpdateAssetTx(ctx context.Context, arg UpdateAssetTxParams) (UpdateAssetTxResult, error) {
+	var result UpdateAssetTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.OldPrice, err = q.GetAssetPriceForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		err = q.UpdateAsset(ctx, UpdateAssetParams{
+			ID:     arg.ID,
+			Price:  arg.Price,
+			Detail: arg.Detail,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.SetPrimaryTranslationDescription(ctx, arg.ID); err != nil {
+			return err
+		}
+
+		if err := q.refreshAssetSlug(ctx, arg.ID); err != nil {
+			return err
+		}
+
+		err = q.writeOutbox(ctx, assetEvent(EventAssetUpdated, arg.ID, map[string]any{
+			"id":        arg.ID,
+			"price":     arg.Price,
+			"old_price": result.OldPrice,
+			"detail":    arg.Detail,
+		}))
+		if err != nil {
+			return err
+		}
+
+		if result.OldPrice == arg.Price {
+			return nil
+		}
+		result.PriceChanged = true
+
+		return q.InsertAssetPriceHistory(ctx, InsertAssetPriceHistoryParams{
+			AssetID:   arg.ID,
+			OldPrice:  result.OldPrice,
+			NewPrice:  arg.Price,
+			ChangedBy: arg.ChangedBy,
+		})
+	})
+
+	return result, err
+}
+
+type InsertAssetTxParams struct {
+	InsertAssetParams
+	// Title is stored as the translation of the primary locale when set
+	Title string
+}
+
+// InsertAssetTx creates an asset together with its asset.created event.
+func (store *Store) InsertAssetTx(ctx context.Context, arg InsertAssetTxParams) (Asset, error) {
+	var asset Asset
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.PrimaryLocale == "" {
+			arg.PrimaryLocale = DefaultLocale
+		}
+		if arg.Currency == "" {
+			arg.Currency = DefaultCurrency
+		}
+
+		title := arg.Detail
+		if arg.Title != "" {
+			title = arg.Title
+		}
+		arg.SlugBase = AssetSlugBase(arg.PropertyType, arg.Province, title)
+
+		var err error
+		asset, err = q.InsertAsset(ctx, arg.InsertAssetParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.Title != "" {
+			_, err = q.UpsertAssetTranslation(ctx, UpsertAssetTranslationParams{
+				AssetID:     asset.ID,
+				Locale:      asset.PrimaryLocale,
+				Title:       arg.Title,
+				Description: asset.Detail,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return q.writeOutbox(ctx, assetEvent(EventAssetCreated, asset.ID, asset))
+	})
+
+	return asset, err
+}
+
+// DeleteAssetTx deletes an asset. The event carries the asset as it was
+// before the delete since subscribers can no longer load it.
+func (store *Store) DeleteAssetTx(ctx context.Context, asset GetAssetByIdRow) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteAsset(ctx, asset.ID); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, assetEvent(EventAssetDeleted, asset.ID, asset))
+	})
+}
+
+// MarkAssetSoldTx closes a listing and returns the number of assets changed,
+// 0 when it was already sold.
+func (store *Store) MarkAssetSoldTx(ctx context.Context, id int64) (int64, error) {
+	var rows int64
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		rows, err = q.MarkAssetSold(ctx, id)
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		return q.writeOutbox(ctx, assetEvent(EventAssetSold, id, map[string]any{"id": id}))
+	})
+
+	return rows, err
+}
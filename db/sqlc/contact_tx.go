@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"strconv"
+)
+
+func contactEvent(eventType string, contactId int64, payload any) OutboxMessage {
+	return OutboxMessage{
+		AggregateType: "contact",
+		AggregateID:   strconv.FormatInt(contactId, 10),
+		EventType:     eventType,
+		Payload:       payload,
+	}
+}
+
+func (store *Store) InsertAssetContactTx(ctx context.Context, arg InsertAssetContactParams) (AssetContact, error) {
+	var contact AssetContact
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		contact, err = q.InsertAssetContact(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, contactEvent(EventContactCreated, contact.ID, contact))
+	})
+
+	return contact, err
+}
+
+type UpdateContactTxParams struct {
+	UpdateContactParams
+	AssetID int64
+}
+
+func (store *Store) UpdateContactTx(ctx context.Context, arg UpdateContactTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.UpdateContact(ctx, arg.UpdateContactParams); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, contactEvent(EventContactUpdated, arg.ID, map[string]any{
+			"id":             arg.ID,
+			"asset_id":       arg.AssetID,
+			"contact_name":   arg.ContactName,
+			"contact_detail": arg.ContactDetail,
+		}))
+	})
+}
+
+type RemoveContactTxParams struct {
+	ID      int64
+	AssetID int64
+}
+
+func (store *Store) RemoveContactTx(ctx context.Context, arg RemoveContactTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.RemoveContact(ctx, arg.ID); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, contactEvent(EventContactDeleted, arg.ID, map[string]any{
+			"id":       arg.ID,
+			"asset_id": arg.AssetID,
+		}))
+	})
+}
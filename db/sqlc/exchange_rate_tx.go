@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// DefaultCurrency is the currency of listings that do not name one.
+const DefaultCurrency = "THB"
+
+// ErrMinorUnitsChanged is returned for a rate that would change the minor
+// units of a currency, which would change every price stored in it.
+var ErrMinorUnitsChanged = errors.New("minor_units of a currency cannot change")
+
+// ImportExchangeRatesTx stores a whole rates file, either every rate in it is
+// saved or none is.
+func (store *Store) ImportExchangeRatesTx(ctx context.Context, rates []UpsertExchangeRateParams) ([]ExchangeRate, error) {
+	saved := make([]ExchangeRate, 0, len(rates))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, arg := range rates {
+			rate, err := q.UpsertExchangeRate(ctx, arg)
+			if err == sql.ErrNoRows {
+				return ErrMinorUnitsChanged
+			}
+			if err != nil {
+				return err
+			}
+			saved = append(saved, rate)
+		}
+		return nil
+	})
+
+	return saved, err
+}
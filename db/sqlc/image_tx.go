@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"strconv"
+)
+
+func imageEvent(eventType string, image AssetImage) OutboxMessage {
+	return OutboxMessage{
+		AggregateType: "image",
+		AggregateID:   strconv.FormatInt(image.ID, 10),
+		EventType:     eventType,
+		Payload:       image,
+	}
+}
+
+func (store *Store) InsertAssetImageTx(ctx context.Context, arg InsertAssetImageParams) (AssetImage, error) {
+	var image AssetImage
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		image, err = q.InsertAssetImage(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, imageEvent(EventImageCreated, image))
+	})
+
+	return image, err
+}
+
+func (store *Store) DeleteImageTx(ctx context.Context, image AssetImage) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteImage(ctx, image.ID); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, imageEvent(EventImageDeleted, image))
+	})
+}
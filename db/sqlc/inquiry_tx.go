@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type SendInquiryMessageTxParams struct {
+	// ThreadID is set when replying; when zero a thread is opened for AssetID/Buyer/Seller.
+	ThreadID int64
+	AssetID  int64
+	Buyer    string
+	Seller   string
+	Sender   string
+	Body     string
+}
+
+type SendInquiryMessageTxResult struct {
+	Thread  InquiryThread
+	Message InquiryMessage
+}
+
+// SendInquiryMessageTx stores a message, opening the buyer's thread on the asset
+// if needed, and bumps the thread's activity. The first seller message marks the
+// thread as replied.
+func (store *Store) SendInquiryMessageTx(ctx context.Context, arg SendInquiryMessageTxParams) (SendInquiryMessageTxResult, error) {
+	var result SendInquiryMessageTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		if arg.ThreadID == 0 {
+			result.Thread, err = q.UpsertInquiryThread(ctx, UpsertInquiryThreadParams{
+				AssetID: arg.AssetID,
+				Buyer:   arg.Buyer,
+				Seller:  arg.Seller,
+			})
+		} else {
+			result.Thread, err = q.GetInquiryThread(ctx, arg.ThreadID)
+		}
+		if err != nil {
+			return err
+		}
+
+		sentAt := time.Now()
+		result.Message, err = q.CreateInquiryMessage(ctx, CreateInquiryMessageParams{
+			ThreadID:  result.Thread.ID,
+			Sender:    arg.Sender,
+			Body:      arg.Body,
+			CreatedAt: sentAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		fromSeller := arg.Sender == result.Thread.Seller
+		err = q.UpdateInquiryThreadActivity(ctx, UpdateInquiryThreadActivityParams{
+			SentAt:     sentAt,
+			FromSeller: fromSeller,
+			ID:         result.Thread.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Thread.LastMessageAt = sentAt
+		if fromSeller && !result.Thread.SellerRepliedAt.Valid {
+			result.Thread.SellerRepliedAt.Time = sentAt
+			result.Thread.SellerRepliedAt.Valid = true
+		}
+		return nil
+	})
+
+	return result, err
+}
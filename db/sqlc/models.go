@@ -7,10 +7,760 @@ package db
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+type AgencyRole string
+
+const (
+	AgencyRoleAdmin AgencyRole = "admin"
+	AgencyRoleAgent AgencyRole = "agent"
+)
+
+func (e *AgencyRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AgencyRole(s)
+	case string:
+		*e = AgencyRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AgencyRole: %T", src)
+	}
+	return nil
+}
+
+type NullAgencyRole struct {
+	AgencyRole AgencyRole `json:"agency_role"`
+	Valid      bool       `json:"valid"` // Valid is true if AgencyRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAgencyRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.AgencyRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AgencyRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAgencyRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AgencyRole), nil
+}
+
+type AlertFrequency string
+
+const (
+	AlertFrequencyInstant AlertFrequency = "instant"
+	AlertFrequencyDaily   AlertFrequency = "daily"
+	AlertFrequencyWeekly  AlertFrequency = "weekly"
+)
+
+func (e *AlertFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AlertFrequency(s)
+	case string:
+		*e = AlertFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AlertFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullAlertFrequency struct {
+	AlertFrequency AlertFrequency `json:"alert_frequency"`
+	Valid          bool           `json:"valid"` // Valid is true if AlertFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAlertFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.AlertFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AlertFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAlertFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AlertFrequency), nil
+}
+
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+func (e *ImportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportStatus(s)
+	case string:
+		*e = ImportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImportStatus struct {
+	ImportStatus ImportStatus `json:"import_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ImportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportStatus), nil
+}
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusDead      JobStatus = "dead"
+)
+
+func (e *JobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JobStatus(s)
+	case string:
+		*e = JobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullJobStatus struct {
+	JobStatus JobStatus `json:"job_status"`
+	Valid     bool      `json:"valid"` // Valid is true if JobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.JobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.JobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.JobStatus), nil
+}
+
+type ModerationStatus string
+
+const (
+	ModerationStatusPending  ModerationStatus = "pending"
+	ModerationStatusApproved ModerationStatus = "approved"
+	ModerationStatusRejected ModerationStatus = "rejected"
+)
+
+func (e *ModerationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ModerationStatus(s)
+	case string:
+		*e = ModerationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ModerationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullModerationStatus struct {
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	Valid            bool             `json:"valid"` // Valid is true if ModerationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullModerationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ModerationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ModerationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullModerationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ModerationStatus), nil
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp   NotificationChannel = "in_app"
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+func (e *NotificationChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationChannel(s)
+	case string:
+		*e = NotificationChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationChannel: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationChannel struct {
+	NotificationChannel NotificationChannel `json:"notification_channel"`
+	Valid               bool                `json:"valid"` // Valid is true if NotificationChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationChannel), nil
+}
+
+type OfferAction string
+
+const (
+	OfferActionSubmitted OfferAction = "submitted"
+	OfferActionCountered OfferAction = "countered"
+	OfferActionAccepted  OfferAction = "accepted"
+	OfferActionRejected  OfferAction = "rejected"
+	OfferActionDeclined  OfferAction = "declined"
+	OfferActionWithdrawn OfferAction = "withdrawn"
+	OfferActionExpired   OfferAction = "expired"
+	OfferActionCancelled OfferAction = "cancelled"
+)
+
+func (e *OfferAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OfferAction(s)
+	case string:
+		*e = OfferAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OfferAction: %T", src)
+	}
+	return nil
+}
+
+type NullOfferAction struct {
+	OfferAction OfferAction `json:"offer_action"`
+	Valid       bool        `json:"valid"` // Valid is true if OfferAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOfferAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.OfferAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OfferAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOfferAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OfferAction), nil
+}
+
+type OfferStatus string
+
+const (
+	OfferStatusPending   OfferStatus = "pending"
+	OfferStatusCountered OfferStatus = "countered"
+	OfferStatusAccepted  OfferStatus = "accepted"
+	OfferStatusRejected  OfferStatus = "rejected"
+	OfferStatusDeclined  OfferStatus = "declined"
+	OfferStatusWithdrawn OfferStatus = "withdrawn"
+	OfferStatusExpired   OfferStatus = "expired"
+	OfferStatusCancelled OfferStatus = "cancelled"
+)
+
+func (e *OfferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OfferStatus(s)
+	case string:
+		*e = OfferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OfferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOfferStatus struct {
+	OfferStatus OfferStatus `json:"offer_status"`
+	Valid       bool        `json:"valid"` // Valid is true if OfferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOfferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OfferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OfferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOfferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OfferStatus), nil
+}
+
+type OrderFeature string
+
+const (
+	OrderFeatureFeatured         OrderFeature = "featured"
+	OrderFeatureTopOfSearch      OrderFeature = "top_of_search"
+	OrderFeatureHighlighted      OrderFeature = "highlighted"
+	OrderFeatureListingExtension OrderFeature = "listing_extension"
+)
+
+func (e *OrderFeature) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderFeature(s)
+	case string:
+		*e = OrderFeature(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderFeature: %T", src)
+	}
+	return nil
+}
+
+type NullOrderFeature struct {
+	OrderFeature OrderFeature `json:"order_feature"`
+	Valid        bool         `json:"valid"` // Valid is true if OrderFeature is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderFeature) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderFeature, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderFeature.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderFeature) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderFeature), nil
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusRefunding OrderStatus = "refunding"
+	OrderStatusFailed    OrderStatus = "failed"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+func (e *OrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OrderStatus(s)
+	case string:
+		*e = OrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOrderStatus struct {
+	OrderStatus OrderStatus `json:"order_status"`
+	Valid       bool        `json:"valid"` // Valid is true if OrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OrderStatus), nil
+}
+
+type PromotionStatus string
+
+const (
+	PromotionStatusActive  PromotionStatus = "active"
+	PromotionStatusExpired PromotionStatus = "expired"
+	PromotionStatusRevoked PromotionStatus = "revoked"
+)
+
+func (e *PromotionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromotionStatus(s)
+	case string:
+		*e = PromotionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromotionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPromotionStatus struct {
+	PromotionStatus PromotionStatus `json:"promotion_status"`
+	Valid           bool            `json:"valid"` // Valid is true if PromotionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromotionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromotionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromotionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromotionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromotionStatus), nil
+}
+
+type PromotionType string
+
+const (
+	PromotionTypeFeatured    PromotionType = "featured"
+	PromotionTypeTopOfSearch PromotionType = "top_of_search"
+	PromotionTypeHighlighted PromotionType = "highlighted"
+)
+
+func (e *PromotionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromotionType(s)
+	case string:
+		*e = PromotionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromotionType: %T", src)
+	}
+	return nil
+}
+
+type NullPromotionType struct {
+	PromotionType PromotionType `json:"promotion_type"`
+	Valid         bool          `json:"valid"` // Valid is true if PromotionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromotionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromotionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromotionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromotionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromotionType), nil
+}
+
+type PropertyType string
+
+const (
+	PropertyTypeHouse      PropertyType = "house"
+	PropertyTypeCondo      PropertyType = "condo"
+	PropertyTypeTownhouse  PropertyType = "townhouse"
+	PropertyTypeLand       PropertyType = "land"
+	PropertyTypeCommercial PropertyType = "commercial"
+	PropertyTypeOther      PropertyType = "other"
+)
+
+func (e *PropertyType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PropertyType(s)
+	case string:
+		*e = PropertyType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PropertyType: %T", src)
+	}
+	return nil
+}
+
+type NullPropertyType struct {
+	PropertyType PropertyType `json:"property_type"`
+	Valid        bool         `json:"valid"` // Valid is true if PropertyType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPropertyType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PropertyType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PropertyType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPropertyType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PropertyType), nil
+}
+
+type ReportCategory string
+
+const (
+	ReportCategoryScam      ReportCategory = "scam"
+	ReportCategoryDuplicate ReportCategory = "duplicate"
+	ReportCategorySold      ReportCategory = "sold"
+	ReportCategoryAbusive   ReportCategory = "abusive"
+	ReportCategoryOther     ReportCategory = "other"
+)
+
+func (e *ReportCategory) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportCategory(s)
+	case string:
+		*e = ReportCategory(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportCategory: %T", src)
+	}
+	return nil
+}
+
+type NullReportCategory struct {
+	ReportCategory ReportCategory `json:"report_category"`
+	Valid          bool           `json:"valid"` // Valid is true if ReportCategory is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportCategory) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportCategory, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportCategory.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportCategory) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportCategory), nil
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusAssigned  ReportStatus = "assigned"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (e *ReportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportStatus(s)
+	case string:
+		*e = ReportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReportStatus struct {
+	ReportStatus ReportStatus `json:"report_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportStatus), nil
+}
+
+type ReportTarget string
+
+const (
+	ReportTargetAsset ReportTarget = "asset"
+	ReportTargetUser  ReportTarget = "user"
+)
+
+func (e *ReportTarget) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportTarget(s)
+	case string:
+		*e = ReportTarget(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportTarget: %T", src)
+	}
+	return nil
+}
+
+type NullReportTarget struct {
+	ReportTarget ReportTarget `json:"report_target"`
+	Valid        bool         `json:"valid"` // Valid is true if ReportTarget is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportTarget) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportTarget, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportTarget.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportTarget) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportTarget), nil
+}
+
+type ReviewStatus string
+
+const (
+	ReviewStatusVisible ReviewStatus = "visible"
+	ReviewStatusHidden  ReviewStatus = "hidden"
+)
+
+func (e *ReviewStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReviewStatus(s)
+	case string:
+		*e = ReviewStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReviewStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReviewStatus struct {
+	ReviewStatus ReviewStatus `json:"review_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReviewStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReviewStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReviewStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReviewStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReviewStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReviewStatus), nil
+}
+
 type UserRole string
 
 const (
@@ -53,14 +803,142 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type ViewingStatus string
+
+const (
+	ViewingStatusBooked    ViewingStatus = "booked"
+	ViewingStatusCancelled ViewingStatus = "cancelled"
+)
+
+func (e *ViewingStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ViewingStatus(s)
+	case string:
+		*e = ViewingStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ViewingStatus: %T", src)
+	}
+	return nil
+}
+
+type NullViewingStatus struct {
+	ViewingStatus ViewingStatus `json:"viewing_status"`
+	Valid         bool          `json:"valid"` // Valid is true if ViewingStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullViewingStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ViewingStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ViewingStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullViewingStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ViewingStatus), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+	WebhookDeliveryStatusCancelled WebhookDeliveryStatus = "cancelled"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhook_delivery_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
+}
+
+type Agency struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	LicenseNumber string         `json:"license_number"`
+	LogoUrl       sql.NullString `json:"logo_url"`
+	Verified      bool           `json:"verified"`
+	CreatedBy     string         `json:"created_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type AgencyInvite struct {
+	ID        int64      `json:"id"`
+	AgencyID  int64      `json:"agency_id"`
+	Username  string     `json:"username"`
+	Role      AgencyRole `json:"role"`
+	InvitedBy string     `json:"invited_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AgencyMember struct {
+	AgencyID int64      `json:"agency_id"`
+	Username string     `json:"username"`
+	Role     AgencyRole `json:"role"`
+	JoinedAt time.Time  `json:"joined_at"`
+}
+
 type Asset struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
-	Price     int64     `json:"price"`
-	Detail    string    `json:"detail"`
-	Status    bool      `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  int64            `json:"id"`
+	Owner               string           `json:"owner"`
+	Price               int64            `json:"price"`
+	Detail              string           `json:"detail"`
+	Status              bool             `json:"status"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+	ModerationStatus    ModerationStatus `json:"moderation_status"`
+	ModerationReason    sql.NullString   `json:"moderation_reason"`
+	ModeratedBy         sql.NullString   `json:"moderated_by"`
+	ModerationUpdatedAt time.Time        `json:"moderation_updated_at"`
+	PropertyType        PropertyType     `json:"property_type"`
+	Province            string           `json:"province"`
+	UnderOffer          bool             `json:"under_offer"`
+	AgencyID            sql.NullInt64    `json:"agency_id"`
+	Agent               sql.NullString   `json:"agent"`
+	ExpiresAt           time.Time        `json:"expires_at"`
+	ExpiryWarnedAt      sql.NullTime     `json:"expiry_warned_at"`
+	ArchivedAt          sql.NullTime     `json:"archived_at"`
+	Slug                string           `json:"slug"`
+	PrimaryLocale       string           `json:"primary_locale"`
+	Currency            string           `json:"currency"`
 }
 
 type AssetContact struct {
@@ -72,12 +950,258 @@ type AssetContact struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+type AssetDailyStat struct {
+	AssetID        int64     `json:"asset_id"`
+	Day            time.Time `json:"day"`
+	Views          int32     `json:"views"`
+	UniqueVisitors int32     `json:"unique_visitors"`
+}
+
 type AssetImage struct {
 	ID       int64  `json:"id"`
 	AssetID  int64  `json:"asset_id"`
 	ImageUrl string `json:"image_url"`
 }
 
+type AssetImport struct {
+	ID               int64            `json:"id"`
+	AgencyID         int64            `json:"agency_id"`
+	CreatedBy        string           `json:"created_by"`
+	Filename         string           `json:"filename"`
+	Status           ImportStatus     `json:"status"`
+	Items            json.RawMessage  `json:"items"`
+	ArchivePath      sql.NullString   `json:"archive_path"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	ModerationReason string           `json:"moderation_reason"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	TotalRows        int32            `json:"total_rows"`
+	ProcessedRows    int32            `json:"processed_rows"`
+	ImportedRows     int32            `json:"imported_rows"`
+	FailedRows       int32            `json:"failed_rows"`
+	Errors           json.RawMessage  `json:"errors"`
+	JobID            sql.NullInt64    `json:"job_id"`
+	FinishedAt       sql.NullTime     `json:"finished_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+type AssetModerationLog struct {
+	ID        int64            `json:"id"`
+	AssetID   int64            `json:"asset_id"`
+	Moderator sql.NullString   `json:"moderator"`
+	Status    ModerationStatus `json:"status"`
+	Reason    sql.NullString   `json:"reason"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type AssetPriceHistory struct {
+	ID        int64     `json:"id"`
+	AssetID   int64     `json:"asset_id"`
+	OldPrice  int64     `json:"old_price"`
+	NewPrice  int64     `json:"new_price"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type AssetPromotion struct {
+	ID        int64           `json:"id"`
+	AssetID   int64           `json:"asset_id"`
+	Type      PromotionType   `json:"type"`
+	Status    PromotionStatus `json:"status"`
+	StartsAt  time.Time       `json:"starts_at"`
+	EndsAt    time.Time       `json:"ends_at"`
+	GrantedBy sql.NullString  `json:"granted_by"`
+	RevokedBy sql.NullString  `json:"revoked_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type AssetTranslation struct {
+	AssetID     int64     `json:"asset_id"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type AssetVisitor struct {
+	AssetID int64     `json:"asset_id"`
+	Day     time.Time `json:"day"`
+	Visitor string    `json:"visitor"`
+}
+
+type ExchangeRate struct {
+	Currency   string         `json:"currency"`
+	Rate       string         `json:"rate"`
+	MinorUnits int16          `json:"minor_units"`
+	UpdatedBy  sql.NullString `json:"updated_by"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type InquiryMessage struct {
+	ID        int64        `json:"id"`
+	ThreadID  int64        `json:"thread_id"`
+	Sender    string       `json:"sender"`
+	Body      string       `json:"body"`
+	ReadAt    sql.NullTime `json:"read_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type InquiryThread struct {
+	ID              int64        `json:"id"`
+	AssetID         int64        `json:"asset_id"`
+	Buyer           string       `json:"buyer"`
+	Seller          string       `json:"seller"`
+	SellerRepliedAt sql.NullTime `json:"seller_replied_at"`
+	LastMessageAt   time.Time    `json:"last_message_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+type Job struct {
+	ID          int64           `json:"id"`
+	Queue       string          `json:"queue"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      JobStatus       `json:"status"`
+	Attempts    int32           `json:"attempts"`
+	MaxAttempts int32           `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedBy    sql.NullString  `json:"locked_by"`
+	LockedUntil sql.NullTime    `json:"locked_until"`
+	LastError   sql.NullString  `json:"last_error"`
+	UniqueKey   sql.NullString  `json:"unique_key"`
+	FinishedAt  sql.NullTime    `json:"finished_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type Notification struct {
+	ID        int64           `json:"id"`
+	Username  string          `json:"username"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    sql.NullTime    `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type Offer struct {
+	ID         int64       `json:"id"`
+	AssetID    int64       `json:"asset_id"`
+	Buyer      string      `json:"buyer"`
+	Seller     string      `json:"seller"`
+	Amount     int64       `json:"amount"`
+	Conditions string      `json:"conditions"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	Status     OfferStatus `json:"status"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type OfferEvent struct {
+	ID         int64       `json:"id"`
+	OfferID    int64       `json:"offer_id"`
+	Actor      string      `json:"actor"`
+	Action     OfferAction `json:"action"`
+	Amount     int64       `json:"amount"`
+	Conditions string      `json:"conditions"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+	Note       string      `json:"note"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type Order struct {
+	ID           int64          `json:"id"`
+	Username     string         `json:"username"`
+	AssetID      int64          `json:"asset_id"`
+	Feature      OrderFeature   `json:"feature"`
+	DurationDays int32          `json:"duration_days"`
+	Amount       int64          `json:"amount"`
+	Currency     string         `json:"currency"`
+	Status       OrderStatus    `json:"status"`
+	Provider     string         `json:"provider"`
+	ProviderRef  sql.NullString `json:"provider_ref"`
+	PromotionID  sql.NullInt64  `json:"promotion_id"`
+	PaidAt       sql.NullTime   `json:"paid_at"`
+	RefundedAt   sql.NullTime   `json:"refunded_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type OutboxEvent struct {
+	ID             int64           `json:"id"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	AggregateType  string          `json:"aggregate_type"`
+	AggregateID    string          `json:"aggregate_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int32           `json:"attempts"`
+	LastError      sql.NullString  `json:"last_error"`
+	PublishedAt    sql.NullTime    `json:"published_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeadAt         sql.NullTime    `json:"dead_at"`
+}
+
+type PaymentEvent struct {
+	Provider   string    `json:"provider"`
+	EventID    string    `json:"event_id"`
+	OrderID    int64     `json:"order_id"`
+	Type       string    `json:"type"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+type Report struct {
+	ID           int64          `json:"id"`
+	Reporter     string         `json:"reporter"`
+	TargetType   ReportTarget   `json:"target_type"`
+	AssetID      sql.NullInt64  `json:"asset_id"`
+	ReportedUser sql.NullString `json:"reported_user"`
+	Category     ReportCategory `json:"category"`
+	Comment      string         `json:"comment"`
+	Status       ReportStatus   `json:"status"`
+	Assignee     sql.NullString `json:"assignee"`
+	Resolution   sql.NullString `json:"resolution"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type SavedSearch struct {
+	ID            int64               `json:"id"`
+	Username      string              `json:"username"`
+	Name          string              `json:"name"`
+	Filters       json.RawMessage     `json:"filters"`
+	Frequency     AlertFrequency      `json:"frequency"`
+	Channel       NotificationChannel `json:"channel"`
+	WebhookUrl    sql.NullString      `json:"webhook_url"`
+	LastCheckedAt time.Time           `json:"last_checked_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+type SavedSearchNotification struct {
+	SavedSearchID int64     `json:"saved_search_id"`
+	AssetID       int64     `json:"asset_id"`
+	Price         int64     `json:"price"`
+	NotifiedAt    time.Time `json:"notified_at"`
+}
+
+type SellerReview struct {
+	ID               int64          `json:"id"`
+	Seller           string         `json:"seller"`
+	Reviewer         string         `json:"reviewer"`
+	Rating           int32          `json:"rating"`
+	Comment          string         `json:"comment"`
+	Reply            sql.NullString `json:"reply"`
+	RepliedAt        sql.NullTime   `json:"replied_at"`
+	Status           ReviewStatus   `json:"status"`
+	ModeratedBy      sql.NullString `json:"moderated_by"`
+	ModerationReason sql.NullString `json:"moderation_reason"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
 type User struct {
 	Username   string         `json:"username"`
 	Name       string         `json:"name"`
@@ -88,4 +1212,64 @@ type User struct {
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
 	Roles      UserRole       `json:"roles"`
+	IsTrusted  bool           `json:"is_trusted"`
+}
+
+type Viewing struct {
+	ID        int64         `json:"id"`
+	SlotID    int64         `json:"slot_id"`
+	AssetID   int64         `json:"asset_id"`
+	Buyer     string        `json:"buyer"`
+	Status    ViewingStatus `json:"status"`
+	Note      string        `json:"note"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+type ViewingSlot struct {
+	ID        int64     `json:"id"`
+	AssetID   int64     `json:"asset_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscription_id"`
+	EventID        string                `json:"event_id"`
+	EventType      string                `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32         `json:"last_status_code"`
+	LastError      sql.NullString        `json:"last_error"`
+	RedeliveryOf   sql.NullInt64         `json:"redelivery_of"`
+	DeliveredAt    sql.NullTime          `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         int64          `json:"id"`
+	DeliveryID int64          `json:"delivery_id"`
+	Attempt    int32          `json:"attempt"`
+	StatusCode sql.NullInt32  `json:"status_code"`
+	Error      sql.NullString `json:"error"`
+	DurationMs int32          `json:"duration_ms"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int64          `json:"id"`
+	Username   sql.NullString `json:"username"`
+	AgencyID   sql.NullInt64  `json:"agency_id"`
+	Url        string         `json:"url"`
+	Secret     string         `json:"secret"`
+	EventTypes []string       `json:"event_types"`
+	Active     bool           `json:"active"`
+	CreatedBy  string         `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
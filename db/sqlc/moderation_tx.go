@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+type ModerateAssetTxParams struct {
+	AssetID int64
+	Status  ModerationStatus
+	Reason  sql.NullString
+	// Moderator is empty when the decision was made by an auto-approval rule.
+	Moderator sql.NullString
+}
+
+// ModerateAssetTx moves an asset to a new moderation state and records the
+// decision in the moderation log.
+func (store *Store) ModerateAssetTx(ctx context.Context, arg ModerateAssetTxParams) (AssetModerationLog, error) {
+	var log AssetModerationLog
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.UpdateAssetModeration(ctx, UpdateAssetModerationParams{
+			ID:               arg.AssetID,
+			ModerationStatus: arg.Status,
+			ModerationReason: arg.Reason,
+			ModeratedBy:      arg.Moderator,
+		})
+		if err != nil {
+			return err
+		}
+
+		log, err = q.InsertModerationLog(ctx, InsertModerationLogParams{
+			AssetID:   arg.AssetID,
+			Moderator: arg.Moderator,
+			Status:    arg.Status,
+			Reason:    arg.Reason,
+		})
+		return err
+	})
+
+	return log, err
+}
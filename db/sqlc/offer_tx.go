@@ -0,0 +1,273 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOfferNotOpen     = errors.New("offer is no longer open")
+	ErrAssetUnavailable = errors.New("asset is sold or already under offer")
+	ErrAssetSold        = errors.New("asset is already sold")
+)
+
+// CreateOfferTx submits a buyer's offer and starts its history.
+func (store *Store) CreateOfferTx(ctx context.Context, arg CreateOfferParams) (Offer, error) {
+	var offer Offer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		asset, err := q.LockAssetForOffer(ctx, arg.AssetID)
+		if err != nil {
+			return err
+		}
+
+		if asset.Status || asset.UnderOffer {
+			return ErrAssetUnavailable
+		}
+
+		offer, err = q.CreateOffer(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.CreateOfferEvent(ctx, CreateOfferEventParams{
+			OfferID:    offer.ID,
+			Actor:      offer.Buyer,
+			Action:     OfferActionSubmitted,
+			Amount:     offer.Amount,
+			Conditions: offer.Conditions,
+			ExpiresAt:  offer.ExpiresAt,
+		})
+	})
+
+	return offer, err
+}
+
+type UpdateOfferTxParams struct {
+	OfferID int64
+	Actor   string
+	// ExpectedStatus guards against acting on an offer that changed since it was read.
+	ExpectedStatus OfferStatus
+	Action         OfferAction
+	Status         OfferStatus
+	// Amount, Conditions and ExpiresAt are the new terms of a counter offer.
+	Amount     int64
+	Conditions string
+	ExpiresAt  time.Time
+	Note       string
+}
+
+// UpdateOfferTx counters, rejects, withdraws or expires an open offer and
+// records the step in its history.
+func (store *Store) UpdateOfferTx(ctx context.Context, arg UpdateOfferTxParams) (Offer, error) {
+	var offer Offer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetOfferForUpdate(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != arg.ExpectedStatus {
+			return ErrOfferNotOpen
+		}
+
+		if arg.Action == OfferActionCountered {
+			offer, err = q.UpdateOfferTerms(ctx, UpdateOfferTermsParams{
+				ID:         arg.OfferID,
+				Status:     arg.Status,
+				Amount:     arg.Amount,
+				Conditions: arg.Conditions,
+				ExpiresAt:  arg.ExpiresAt,
+			})
+		} else {
+			offer, err = q.SetOfferStatus(ctx, SetOfferStatusParams{
+				ID:     arg.OfferID,
+				Status: arg.Status,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		return q.CreateOfferEvent(ctx, CreateOfferEventParams{
+			OfferID:    offer.ID,
+			Actor:      arg.Actor,
+			Action:     arg.Action,
+			Amount:     offer.Amount,
+			Conditions: offer.Conditions,
+			ExpiresAt:  offer.ExpiresAt,
+			Note:       arg.Note,
+		})
+	})
+
+	return offer, err
+}
+
+type AcceptOfferTxParams struct {
+	OfferID        int64
+	Actor          string
+	ExpectedStatus OfferStatus
+	Note           string
+}
+
+type AcceptOfferTxResult struct {
+	Offer    Offer
+	Declined []Offer
+}
+
+// AcceptOfferTx accepts an offer, puts the asset under offer and declines every
+// other open offer on it.
+func (store *Store) AcceptOfferTx(ctx context.Context, arg AcceptOfferTxParams) (AcceptOfferTxResult, error) {
+	var result AcceptOfferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		offer, err := q.GetOffer(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		// lock the asset before any offer so concurrent accepts on the same
+		// asset queue up here instead of deadlocking on each other's offers
+		asset, err := q.LockAssetForOffer(ctx, offer.AssetID)
+		if err != nil {
+			return err
+		}
+
+		if asset.Status || asset.UnderOffer {
+			return ErrAssetUnavailable
+		}
+
+		offer, err = q.GetOfferForUpdate(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		if offer.Status != arg.ExpectedStatus || !offer.ExpiresAt.After(time.Now()) {
+			return ErrOfferNotOpen
+		}
+
+		result.Offer, err = q.SetOfferStatus(ctx, SetOfferStatusParams{
+			ID:     offer.ID,
+			Status: OfferStatusAccepted,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateOfferEvent(ctx, CreateOfferEventParams{
+			OfferID:    offer.ID,
+			Actor:      arg.Actor,
+			Action:     OfferActionAccepted,
+			Amount:     offer.Amount,
+			Conditions: offer.Conditions,
+			ExpiresAt:  offer.ExpiresAt,
+			Note:       arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.SetAssetUnderOffer(ctx, SetAssetUnderOfferParams{
+			ID:         offer.AssetID,
+			UnderOffer: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Declined, err = q.DeclineOpenOffers(ctx, DeclineOpenOffersParams{
+			AssetID:    offer.AssetID,
+			AcceptedID: offer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, declined := range result.Declined {
+			err = q.CreateOfferEvent(ctx, CreateOfferEventParams{
+				OfferID:    declined.ID,
+				Actor:      declined.Seller,
+				Action:     OfferActionDeclined,
+				Amount:     declined.Amount,
+				Conditions: declined.Conditions,
+				ExpiresAt:  declined.ExpiresAt,
+				Note:       "another offer was accepted",
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type CancelOfferTxParams struct {
+	OfferID int64
+	Actor   string
+	Note    string
+}
+
+// CancelOfferTx calls off an accepted offer when the deal falls through and
+// takes the asset off offer, so it can get offers again.
+func (store *Store) CancelOfferTx(ctx context.Context, arg CancelOfferTxParams) (Offer, error) {
+	var offer Offer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		current, err := q.GetOffer(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		// same lock order as AcceptOfferTx, the asset before the offer
+		asset, err := q.LockAssetForOffer(ctx, current.AssetID)
+		if err != nil {
+			return err
+		}
+
+		if asset.Status {
+			return ErrAssetSold
+		}
+
+		current, err = q.GetOfferForUpdate(ctx, arg.OfferID)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != OfferStatusAccepted {
+			return ErrOfferNotOpen
+		}
+
+		offer, err = q.SetOfferStatus(ctx, SetOfferStatusParams{
+			ID:     current.ID,
+			Status: OfferStatusCancelled,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateOfferEvent(ctx, CreateOfferEventParams{
+			OfferID:    offer.ID,
+			Actor:      arg.Actor,
+			Action:     OfferActionCancelled,
+			Amount:     offer.Amount,
+			Conditions: offer.Conditions,
+			ExpiresAt:  offer.ExpiresAt,
+			Note:       arg.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.SetAssetUnderOffer(ctx, SetAssetUnderOfferParams{
+			ID:         offer.AssetID,
+			UnderOffer: false,
+		})
+	})
+
+	return offer, err
+}
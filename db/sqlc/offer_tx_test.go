@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+)
+
+func offerRow(id int64, status OfferStatus) dbtest.Result {
+	now := time.Now()
+	return dbtest.Row(id, int64(3), "malee", "somchai", int64(450000000), "", now.Add(24*time.Hour), string(status), now, now)
+}
+
+func TestCancelOfferTx(t *testing.T) {
+	tests := []struct {
+		name      string
+		sold      bool
+		status    OfferStatus
+		wantErr   error
+		wantCalls []string
+	}{
+		{
+			name:      "accepted",
+			status:    OfferStatusAccepted,
+			wantCalls: []string{"BEGIN", "GetOffer", "LockAssetForOffer", "GetOfferForUpdate", "SetOfferStatus", "CreateOfferEvent", "SetAssetUnderOffer", "COMMIT"},
+		},
+		{
+			name:      "no longer accepted",
+			status:    OfferStatusCancelled,
+			wantErr:   ErrOfferNotOpen,
+			wantCalls: []string{"BEGIN", "GetOffer", "LockAssetForOffer", "GetOfferForUpdate", "ROLLBACK"},
+		},
+		{
+			name:      "asset sold",
+			sold:      true,
+			status:    OfferStatusAccepted,
+			wantErr:   ErrAssetSold,
+			wantCalls: []string{"BEGIN", "GetOffer", "LockAssetForOffer", "ROLLBACK"},
+		},
+	}
+
+	for _, tt := range tests {
+		fake, conn := dbtest.New(t)
+		store := NewStore(conn)
+
+		fake.Handle("GetOffer", func(args []driver.Value) (dbtest.Result, error) {
+			return offerRow(7, tt.status), nil
+		})
+		fake.Handle("GetOfferForUpdate", func(args []driver.Value) (dbtest.Result, error) {
+			return offerRow(7, tt.status), nil
+		})
+		fake.Handle("LockAssetForOffer", func(args []driver.Value) (dbtest.Result, error) {
+			return dbtest.Row(tt.sold, true), nil
+		})
+		fake.Handle("SetOfferStatus", func(args []driver.Value) (dbtest.Result, error) {
+			return offerRow(7, OfferStatus(args[1].(string))), nil
+		})
+		fake.Handle("CreateOfferEvent", func(args []driver.Value) (dbtest.Result, error) {
+			if args[2] != string(OfferActionCancelled) {
+				t.Errorf("%s: recorded action %v", tt.name, args[2])
+			}
+			return dbtest.Result{RowsAffected: 1}, nil
+		})
+		var underOffer []driver.Value
+		fake.Handle("SetAssetUnderOffer", func(args []driver.Value) (dbtest.Result, error) {
+			underOffer = append(underOffer, args[1])
+			return dbtest.Result{RowsAffected: 1}, nil
+		})
+
+		offer, err := store.CancelOfferTx(context.Background(), CancelOfferTxParams{OfferID: 7, Actor: "somchai"})
+		if err != tt.wantErr {
+			t.Errorf("%s: CancelOfferTx error %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if got := fake.Calls(); !slices.Equal(got, tt.wantCalls) {
+			t.Errorf("%s: queries %v, want %v", tt.name, got, tt.wantCalls)
+		}
+
+		if tt.wantErr != nil {
+			continue
+		}
+		if offer.Status != OfferStatusCancelled {
+			t.Errorf("%s: offer is %s, want cancelled", tt.name, offer.Status)
+		}
+		if !slices.Equal(underOffer, []driver.Value{false}) {
+			t.Errorf("%s: under_offer set to %v, want [false]", tt.name, underOffer)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// Domain events written to the outbox.
+const (
+	EventAssetCreated = "asset.created"
+	EventAssetUpdated = "asset.updated"
+	EventAssetSold    = "asset.sold"
+	EventAssetDeleted = "asset.deleted"
+
+	EventContactCreated = "contact.created"
+	EventContactUpdated = "contact.updated"
+	EventContactDeleted = "contact.deleted"
+
+	EventImageCreated = "image.created"
+	EventImageDeleted = "image.deleted"
+
+	EventUserCreated         = "user.created"
+	EventUserUpdated         = "user.updated"
+	EventUserPasswordChanged = "user.password_changed"
+)
+
+// OutboxMessage is a domain event to be stored with the change it describes.
+type OutboxMessage struct {
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       any
+	// IdempotencyKey is generated when empty. Writing the same key twice
+	// keeps only the first event.
+	IdempotencyKey string
+}
+
+// writeOutbox stores an event inside the caller's transaction, so it is only
+// published if the change itself is committed.
+func (q *Queries) writeOutbox(ctx context.Context, msg OutboxMessage) error {
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return err
+	}
+
+	if msg.IdempotencyKey == "" {
+		msg.IdempotencyKey = uuid.NewString()
+	}
+
+	return q.InsertOutboxEvent(ctx, InsertOutboxEventParams{
+		IdempotencyKey: msg.IdempotencyKey,
+		AggregateType:  msg.AggregateType,
+		AggregateID:    msg.AggregateID,
+		EventType:      msg.EventType,
+		Payload:        payload,
+	})
+}
+
+func assetEvent(eventType string, assetId int64, payload any) OutboxMessage {
+	return OutboxMessage{
+		AggregateType: "asset",
+		AggregateID:   strconv.FormatInt(assetId, 10),
+		EventType:     eventType,
+		Payload:       payload,
+	}
+}
+
+type RelayOutboxTxParams struct {
+	BatchSize int32
+	// MaxAttempts is how often an event may fail before it is set aside as dead.
+	MaxAttempts int32
+	// Publish hands one event to the subscribers. An error stops the batch
+	// and the event is retried on the next run.
+	Publish func(event OutboxEvent) error
+}
+
+type RelayOutboxTxResult struct {
+	Published int
+	Failed    bool
+	// Dead is the event that failed for the last time in this run, if any.
+	Dead *OutboxEvent
+}
+
+// RelayOutboxTx locks the oldest unpublished events with FOR UPDATE SKIP
+// LOCKED, publishes them in order and marks the published ones. Other relays
+// skip the locked rows instead of waiting for them. An event that runs out of
+// attempts is marked dead and skipped until RetryDeadOutboxEvent.
+func (store *Store) RelayOutboxTx(ctx context.Context, arg RelayOutboxTxParams) (RelayOutboxTxResult, error) {
+	var result RelayOutboxTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		events, err := q.LockOutboxEvents(ctx, arg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		published := make([]int64, 0, len(events))
+		for _, event := range events {
+			if err := arg.Publish(event); err != nil {
+				result.Failed = true
+				failed, err := q.MarkOutboxEventFailed(ctx, MarkOutboxEventFailedParams{
+					ID:          event.ID,
+					LastError:   sql.NullString{String: err.Error(), Valid: true},
+					MaxAttempts: arg.MaxAttempts,
+				})
+				if err != nil {
+					return err
+				}
+				if failed.DeadAt.Valid {
+					result.Dead = &failed
+				}
+				break
+			}
+			published = append(published, event.ID)
+		}
+
+		result.Published = len(published)
+		if len(published) == 0 {
+			return nil
+		}
+
+		return q.MarkOutboxEventsPublished(ctx, published)
+	})
+
+	return result, err
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+)
+
+// outboxEventRow is an outbox event in the column order of SELECT * FROM outbox_events.
+func outboxEventRow(id int64, attempts int32, lastError string, deadAt driver.Value) []driver.Value {
+	var errValue driver.Value
+	if lastError != "" {
+		errValue = lastError
+	}
+	return []driver.Value{id, "event-" + strconv.FormatInt(id, 10), "asset", "42", EventAssetUpdated, []byte(`{}`), int64(attempts), errValue, nil, time.Now(), deadAt}
+}
+
+func TestRelayOutboxTxMarksEventDead(t *testing.T) {
+	fake, conn := dbtest.New(t)
+	store := NewStore(conn)
+
+	attempts := map[int64]int32{1: 8, 2: 9}
+	fake.Handle("LockOutboxEvents", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{Rows: [][]driver.Value{outboxEventRow(2, attempts[2], "", nil)}}, nil
+	})
+	fake.Handle("MarkOutboxEventFailed", func(args []driver.Value) (dbtest.Result, error) {
+		lastError, maxAttempts, id := args[0].(string), args[1].(int64), args[2].(int64)
+		attempts[id]++
+
+		var deadAt driver.Value
+		if int64(attempts[id]) >= maxAttempts {
+			deadAt = time.Now()
+		}
+		return dbtest.Row(outboxEventRow(id, attempts[id], lastError, deadAt)...), nil
+	})
+
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		BatchSize:   10,
+		MaxAttempts: 10,
+		Publish: func(event OutboxEvent) error {
+			return errors.New("subscriber is down")
+		},
+	})
+	if err != nil {
+		t.Fatalf("RelayOutboxTx: %v", err)
+	}
+
+	if !result.Failed || result.Published != 0 {
+		t.Errorf("got %+v, want a failed run", result)
+	}
+	if result.Dead == nil {
+		t.Fatal("the event failed for the tenth time but is not dead")
+	}
+	if result.Dead.ID != 2 || result.Dead.Attempts != 10 || result.Dead.LastError.String != "subscriber is down" {
+		t.Errorf("dead event = %+v", result.Dead)
+	}
+}
+
+func TestRelayOutboxTxRetriesFailedEvent(t *testing.T) {
+	fake, conn := dbtest.New(t)
+	store := NewStore(conn)
+
+	fake.Handle("LockOutboxEvents", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{Rows: [][]driver.Value{outboxEventRow(1, 0, "", nil), outboxEventRow(2, 0, "", nil)}}, nil
+	})
+	fake.Handle("MarkOutboxEventsPublished", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Result{RowsAffected: 1}, nil
+	})
+	fake.Handle("MarkOutboxEventFailed", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(outboxEventRow(args[2].(int64), 1, args[0].(string), nil)...), nil
+	})
+
+	result, err := store.RelayOutboxTx(context.Background(), RelayOutboxTxParams{
+		BatchSize:   10,
+		MaxAttempts: 10,
+		Publish: func(event OutboxEvent) error {
+			if event.ID == 2 {
+				return errors.New("subscriber is down")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("RelayOutboxTx: %v", err)
+	}
+
+	if !result.Failed || result.Published != 1 || result.Dead != nil {
+		t.Errorf("got %+v, want one published event and a failure to retry", result)
+	}
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrOrderAmountMismatch = errors.New("paid amount does not match the order")
+	ErrOrderNotRefundable  = errors.New("only orders claimed for a refund can be refunded")
+)
+
+type ProcessPaymentEventTxParams struct {
+	Provider  string
+	EventID   string
+	EventType string
+	Reference string
+	Amount    int64
+	// Status is what the event moves the order to: paid, failed or refunded.
+	Status OrderStatus
+}
+
+type ProcessPaymentEventTxResult struct {
+	Order Order
+	// Duplicate is set when the event was already handled before.
+	Duplicate bool
+	// Activated is set when this event paid the order and turned its feature on.
+	Activated bool
+}
+
+// ProcessPaymentEventTx applies a provider webhook to its order exactly once.
+// Paying an order activates the purchased feature in the same transaction.
+func (store *Store) ProcessPaymentEventTx(ctx context.Context, arg ProcessPaymentEventTxParams) (ProcessPaymentEventTxResult, error) {
+	var result ProcessPaymentEventTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		order, err := q.GetOrderByProviderRefForUpdate(ctx, GetOrderByProviderRefForUpdateParams{
+			Provider:    arg.Provider,
+			ProviderRef: sql.NullString{String: arg.Reference, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+		result.Order = order
+
+		inserted, err := q.InsertPaymentEvent(ctx, InsertPaymentEventParams{
+			Provider: arg.Provider,
+			EventID:  arg.EventID,
+			OrderID:  order.ID,
+			Type:     arg.EventType,
+		})
+		if err != nil {
+			return err
+		}
+
+		if inserted == 0 {
+			result.Duplicate = true
+			return nil
+		}
+
+		switch arg.Status {
+		case OrderStatusPaid:
+			if order.Status != OrderStatusPending {
+				return nil
+			}
+
+			if arg.Amount != order.Amount {
+				return ErrOrderAmountMismatch
+			}
+
+			result.Order, err = activateOrder(ctx, q, order)
+			result.Activated = err == nil
+			return err
+		case OrderStatusFailed:
+			if order.Status != OrderStatusPending {
+				return nil
+			}
+
+			result.Order, err = q.MarkOrderFailed(ctx, order.ID)
+			return err
+		case OrderStatusRefunded:
+			// a refund an admin started may be confirmed before RefundOrderTx runs
+			if order.Status != OrderStatusPaid && order.Status != OrderStatusRefunding {
+				return nil
+			}
+
+			result.Order, err = refundOrder(ctx, q, order, sql.NullString{})
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// activateOrder turns on what the order paid for and marks it paid.
+func activateOrder(ctx context.Context, q *Queries, order Order) (Order, error) {
+	var promotionID sql.NullInt64
+
+	switch order.Feature {
+	case OrderFeatureListingExtension:
+		_, err := q.ExtendAssetExpiry(ctx, ExtendAssetExpiryParams{
+			ID:   order.AssetID,
+			Days: order.DurationDays,
+		})
+		if err != nil {
+			return order, err
+		}
+	default:
+		now := time.Now()
+		promotion, err := q.CreatePromotion(ctx, CreatePromotionParams{
+			AssetID:  order.AssetID,
+			Type:     PromotionType(order.Feature),
+			StartsAt: now,
+			EndsAt:   now.AddDate(0, 0, int(order.DurationDays)),
+		})
+		if err != nil {
+			return order, err
+		}
+		promotionID = sql.NullInt64{Int64: promotion.ID, Valid: true}
+	}
+
+	return q.MarkOrderPaid(ctx, MarkOrderPaidParams{
+		ID:          order.ID,
+		PromotionID: promotionID,
+	})
+}
+
+// refundOrder ends the promotion the order bought. A listing extension stays
+// in place since the listing may already have been renewed on top of it.
+func refundOrder(ctx context.Context, q *Queries, order Order, refundedBy sql.NullString) (Order, error) {
+	if order.PromotionID.Valid {
+		_, err := q.RevokePromotion(ctx, RevokePromotionParams{
+			ID:        order.PromotionID.Int64,
+			RevokedBy: refundedBy,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return order, err
+		}
+	}
+
+	return q.MarkOrderRefunded(ctx, order.ID)
+}
+
+type RefundOrderTxParams struct {
+	OrderID    int64
+	RefundedBy string
+}
+
+// RefundOrderTx marks an order claimed with ClaimOrderRefund refunded after
+// the provider returned the money. An order the provider's refund webhook
+// already refunded is returned as it is.
+func (store *Store) RefundOrderTx(ctx context.Context, arg RefundOrderTxParams) (Order, error) {
+	var order Order
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		order, err = q.GetOrderForUpdate(ctx, arg.OrderID)
+		if err != nil {
+			return err
+		}
+
+		switch order.Status {
+		case OrderStatusRefunded:
+			return nil
+		case OrderStatusRefunding:
+		default:
+			return ErrOrderNotRefundable
+		}
+
+		order, err = refundOrder(ctx, q, order, sql.NullString{String: arg.RefundedBy, Valid: true})
+		return err
+	})
+
+	return order, err
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+)
+
+// fakePayments keeps one order with its payment events and promotions in
+// memory and undoes the writes of rolled back transactions.
+type fakePayments struct {
+	order      Order
+	events     map[string]bool
+	promotions map[int64]PromotionStatus
+
+	saved fakePaymentsState
+}
+
+type fakePaymentsState struct {
+	order      Order
+	events     map[string]bool
+	promotions map[int64]PromotionStatus
+}
+
+func newFakePayments(t *testing.T, order Order) (*Store, *fakePayments) {
+	t.Helper()
+
+	fake, conn := dbtest.New(t)
+	payments := &fakePayments{
+		order:      order,
+		events:     map[string]bool{},
+		promotions: map[int64]PromotionStatus{},
+	}
+
+	fake.Hooks(dbtest.TxHooks{
+		Begin: func() {
+			payments.saved = fakePaymentsState{payments.order, maps.Clone(payments.events), maps.Clone(payments.promotions)}
+		},
+		Rollback: func() {
+			payments.order = payments.saved.order
+			payments.events = payments.saved.events
+			payments.promotions = payments.saved.promotions
+		},
+	})
+
+	fake.Handle("GetOrderByProviderRefForUpdate", func(args []driver.Value) (dbtest.Result, error) {
+		if args[0] != payments.order.Provider || args[1] != payments.order.ProviderRef.String {
+			return dbtest.Result{}, nil
+		}
+		return dbtest.Row(payments.orderRow()...), nil
+	})
+	fake.Handle("GetOrderForUpdate", func(args []driver.Value) (dbtest.Result, error) {
+		if args[0] != payments.order.ID {
+			return dbtest.Result{}, nil
+		}
+		return dbtest.Row(payments.orderRow()...), nil
+	})
+	fake.Handle("InsertPaymentEvent", func(args []driver.Value) (dbtest.Result, error) {
+		key := args[0].(string) + "/" + args[1].(string)
+		if payments.events[key] {
+			return dbtest.Result{RowsAffected: 0}, nil
+		}
+		payments.events[key] = true
+		return dbtest.Result{RowsAffected: 1}, nil
+	})
+	fake.Handle("CreatePromotion", func(args []driver.Value) (dbtest.Result, error) {
+		id := int64(len(payments.promotions) + 1)
+		payments.promotions[id] = PromotionStatusActive
+		now := time.Now()
+		return dbtest.Row(id, args[0], args[1], string(PromotionStatusActive), args[2], args[3], args[4], nil, now, now), nil
+	})
+	fake.Handle("RevokePromotion", func(args []driver.Value) (dbtest.Result, error) {
+		id := args[0].(int64)
+		payments.promotions[id] = PromotionStatusRevoked
+		now := time.Now()
+		return dbtest.Row(id, payments.order.AssetID, string(payments.order.Feature), string(PromotionStatusRevoked), now, now, nil, args[1], now, now), nil
+	})
+	fake.Handle("ExtendAssetExpiry", func(args []driver.Value) (dbtest.Result, error) {
+		return dbtest.Row(time.Now().AddDate(0, 0, int(args[0].(int64)))), nil
+	})
+	fake.Handle("MarkOrderPaid", func(args []driver.Value) (dbtest.Result, error) {
+		payments.order.Status = OrderStatusPaid
+		payments.order.PromotionID.Valid = args[1] != nil
+		if payments.order.PromotionID.Valid {
+			payments.order.PromotionID.Int64 = args[1].(int64)
+		}
+		payments.order.PaidAt.Time, payments.order.PaidAt.Valid = time.Now(), true
+		return dbtest.Row(payments.orderRow()...), nil
+	})
+	fake.Handle("MarkOrderFailed", func(args []driver.Value) (dbtest.Result, error) {
+		payments.order.Status = OrderStatusFailed
+		return dbtest.Row(payments.orderRow()...), nil
+	})
+	fake.Handle("MarkOrderRefunded", func(args []driver.Value) (dbtest.Result, error) {
+		payments.order.Status = OrderStatusRefunded
+		payments.order.RefundedAt.Time, payments.order.RefundedAt.Valid = time.Now(), true
+		return dbtest.Row(payments.orderRow()...), nil
+	})
+
+	return NewStore(conn), payments
+}
+
+// orderRow is the order in the column order of SELECT * FROM orders.
+func (payments *fakePayments) orderRow() []driver.Value {
+	order := payments.order
+
+	var providerRef, promotionID, paidAt, refundedAt driver.Value
+	if order.ProviderRef.Valid {
+		providerRef = order.ProviderRef.String
+	}
+	if order.PromotionID.Valid {
+		promotionID = order.PromotionID.Int64
+	}
+	if order.PaidAt.Valid {
+		paidAt = order.PaidAt.Time
+	}
+	if order.RefundedAt.Valid {
+		refundedAt = order.RefundedAt.Time
+	}
+
+	return []driver.Value{
+		order.ID,
+		order.Username,
+		order.AssetID,
+		string(order.Feature),
+		int64(order.DurationDays),
+		order.Amount,
+		order.Currency,
+		string(order.Status),
+		order.Provider,
+		providerRef,
+		promotionID,
+		paidAt,
+		refundedAt,
+		order.CreatedAt,
+		order.UpdatedAt,
+	}
+}
+
+func pendingOrder() Order {
+	now := time.Now()
+	order := Order{
+		ID:           7,
+		Username:     "somchai",
+		AssetID:      42,
+		Feature:      OrderFeatureFeatured,
+		DurationDays: 7,
+		Amount:       49900,
+		Currency:     "THB",
+		Status:       OrderStatusPending,
+		Provider:     "mock",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	order.ProviderRef.String, order.ProviderRef.Valid = "mock_abc", true
+	return order
+}
+
+func paymentEvent(id string, status OrderStatus, amount int64) ProcessPaymentEventTxParams {
+	return ProcessPaymentEventTxParams{
+		Provider:  "mock",
+		EventID:   id,
+		EventType: "payment." + string(status),
+		Reference: "mock_abc",
+		Amount:    amount,
+		Status:    status,
+	}
+}
+
+func TestProcessPaymentEventTxPaysOrder(t *testing.T) {
+	store, payments := newFakePayments(t, pendingOrder())
+
+	result, err := store.ProcessPaymentEventTx(context.Background(), paymentEvent("evt_1", OrderStatusPaid, 49900))
+	if err != nil {
+		t.Fatalf("ProcessPaymentEventTx: %v", err)
+	}
+
+	if !result.Activated || result.Duplicate {
+		t.Errorf("got %+v, want an activated, new event", result)
+	}
+	if result.Order.Status != OrderStatusPaid || !result.Order.PromotionID.Valid {
+		t.Errorf("order = %+v, want paid with a promotion", result.Order)
+	}
+	if payments.promotions[result.Order.PromotionID.Int64] != PromotionStatusActive {
+		t.Errorf("promotion %d is not active", result.Order.PromotionID.Int64)
+	}
+}
+
+func TestProcessPaymentEventTxDuplicateEvent(t *testing.T) {
+	store, payments := newFakePayments(t, pendingOrder())
+	ctx := context.Background()
+
+	_, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_1", OrderStatusPaid, 49900))
+	if err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+
+	result, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_1", OrderStatusPaid, 49900))
+	if err != nil {
+		t.Fatalf("second delivery: %v", err)
+	}
+
+	if !result.Duplicate || result.Activated {
+		t.Errorf("got %+v, want a duplicate that activates nothing", result)
+	}
+	if len(payments.promotions) != 1 {
+		t.Errorf("got %d promotions, want 1", len(payments.promotions))
+	}
+}
+
+func TestProcessPaymentEventTxAmountMismatch(t *testing.T) {
+	store, payments := newFakePayments(t, pendingOrder())
+	ctx := context.Background()
+
+	_, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_1", OrderStatusPaid, 100))
+	if !errors.Is(err, ErrOrderAmountMismatch) {
+		t.Fatalf("got %v, want %v", err, ErrOrderAmountMismatch)
+	}
+
+	if payments.order.Status != OrderStatusPending {
+		t.Errorf("order status = %s, want pending", payments.order.Status)
+	}
+	if len(payments.promotions) != 0 {
+		t.Errorf("got %d promotions, want none", len(payments.promotions))
+	}
+
+	// the event was rolled back with the rest, so a corrected delivery still applies
+	result, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_1", OrderStatusPaid, 49900))
+	if err != nil {
+		t.Fatalf("corrected delivery: %v", err)
+	}
+	if result.Duplicate || !result.Activated {
+		t.Errorf("got %+v, want the corrected delivery to activate the order", result)
+	}
+}
+
+func TestProcessPaymentEventTxRefundAfterPaid(t *testing.T) {
+	store, payments := newFakePayments(t, pendingOrder())
+	ctx := context.Background()
+
+	paid, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_1", OrderStatusPaid, 49900))
+	if err != nil {
+		t.Fatalf("pay: %v", err)
+	}
+
+	result, err := store.ProcessPaymentEventTx(ctx, paymentEvent("evt_2", OrderStatusRefunded, 49900))
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	if result.Order.Status != OrderStatusRefunded || !result.Order.RefundedAt.Valid {
+		t.Errorf("order = %+v, want refunded", result.Order)
+	}
+	if payments.promotions[paid.Order.PromotionID.Int64] != PromotionStatusRevoked {
+		t.Errorf("promotion %d was not revoked", paid.Order.PromotionID.Int64)
+	}
+}
+
+func TestProcessPaymentEventTxRefundBeforePaid(t *testing.T) {
+	store, payments := newFakePayments(t, pendingOrder())
+
+	result, err := store.ProcessPaymentEventTx(context.Background(), paymentEvent("evt_1", OrderStatusRefunded, 49900))
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	if result.Order.Status != OrderStatusPending || payments.order.Status != OrderStatusPending {
+		t.Errorf("order status = %s, want pending", payments.order.Status)
+	}
+}
+
+func TestProcessPaymentEventTxRefundWhileClaimed(t *testing.T) {
+	order := pendingOrder()
+	order.Status = OrderStatusRefunding
+	store, payments := newFakePayments(t, order)
+
+	result, err := store.ProcessPaymentEventTx(context.Background(), paymentEvent("evt_1", OrderStatusRefunded, 49900))
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	if result.Order.Status != OrderStatusRefunded || payments.order.Status != OrderStatusRefunded {
+		t.Errorf("order status = %s, want refunded", payments.order.Status)
+	}
+}
+
+func TestRefundOrderTx(t *testing.T) {
+	tests := []struct {
+		status  OrderStatus
+		want    OrderStatus
+		wantErr error
+	}{
+		{OrderStatusRefunding, OrderStatusRefunded, nil},
+		// the provider's webhook got there first
+		{OrderStatusRefunded, OrderStatusRefunded, nil},
+		// not claimed with ClaimOrderRefund
+		{OrderStatusPaid, OrderStatusPaid, ErrOrderNotRefundable},
+		{OrderStatusPending, OrderStatusPending, ErrOrderNotRefundable},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.status), func(t *testing.T) {
+			order := pendingOrder()
+			order.Status = tc.status
+			store, payments := newFakePayments(t, order)
+
+			_, err := store.RefundOrderTx(context.Background(), RefundOrderTxParams{OrderID: order.ID, RefundedBy: "admin"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got %v, want %v", err, tc.wantErr)
+			}
+			if payments.order.Status != tc.want {
+				t.Errorf("order status = %s, want %s", payments.order.Status, tc.want)
+			}
+		})
+	}
+}
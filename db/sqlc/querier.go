@@ -6,32 +6,253 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddAgencyMember(ctx context.Context, arg AddAgencyMemberParams) (AgencyMember, error)
+	AddAssetDailyStats(ctx context.Context, arg AddAssetDailyStatsParams) error
+	AddAssetSlugRedirect(ctx context.Context, arg AddAssetSlugRedirectParams) error
+	AddFavorite(ctx context.Context, arg AddFavoriteParams) (int64, error)
+	ArchiveExpiredAssets(ctx context.Context) ([]ArchiveExpiredAssetsRow, error)
+	AssignReport(ctx context.Context, arg AssignReportParams) (int64, error)
+	BuryJob(ctx context.Context, arg BuryJobParams) (int64, error)
+	CanManageAsset(ctx context.Context, arg CanManageAssetParams) (bool, error)
+	CancelAgencyInvite(ctx context.Context, arg CancelAgencyInviteParams) (int64, error)
+	CancelPendingWebhookDeliveries(ctx context.Context, subscriptionID int64) (int64, error)
+	CancelViewing(ctx context.Context, id int64) error
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]ClaimDueWebhookDeliveriesRow, error)
+	ClaimJobs(ctx context.Context, arg ClaimJobsParams) ([]Job, error)
+	ClaimOrderRefund(ctx context.Context, id int64) (Order, error)
+	CloseReport(ctx context.Context, arg CloseReportParams) (int64, error)
+	CompleteJob(ctx context.Context, arg CompleteJobParams) (int64, error)
+	CountAgencies(ctx context.Context, verified sql.NullBool) (int64, error)
+	CountAgencyAdmins(ctx context.Context, agencyID int64) (int64, error)
+	CountAssetImports(ctx context.Context, agencyID int64) (int64, error)
+	CountDeadOutboxEvents(ctx context.Context) (int64, error)
+	CountFavoritesByUsername(ctx context.Context, username string) (int64, error)
+	CountInquiryThreads(ctx context.Context, arg CountInquiryThreadsParams) (int64, error)
+	CountJobs(ctx context.Context, arg CountJobsParams) (int64, error)
+	CountModerationQueue(ctx context.Context, arg CountModerationQueueParams) (int64, error)
+	CountNotifications(ctx context.Context, arg CountNotificationsParams) (int64, error)
+	CountOffersByAsset(ctx context.Context, arg CountOffersByAssetParams) (int64, error)
+	CountOffersByBuyer(ctx context.Context, buyer string) (int64, error)
+	CountOpenReportsByAsset(ctx context.Context, assetID sql.NullInt64) (int64, error)
+	CountOrders(ctx context.Context, status NullOrderStatus) (int64, error)
+	CountOrdersByUser(ctx context.Context, username string) (int64, error)
+	CountPromotions(ctx context.Context, arg CountPromotionsParams) (int64, error)
+	CountReports(ctx context.Context, arg CountReportsParams) (int64, error)
+	CountReviewsForModeration(ctx context.Context, status NullReviewStatus) (int64, error)
+	CountSitemapAssets(ctx context.Context) (int64, error)
+	CountUnreadInquiryMessages(ctx context.Context, username string) (int64, error)
+	CountUnreadNotifications(ctx context.Context, username string) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, subscriptionID int64) (int64, error)
+	CreateAgency(ctx context.Context, arg CreateAgencyParams) (Agency, error)
+	CreateAgencyInvite(ctx context.Context, arg CreateAgencyInviteParams) (AgencyInvite, error)
+	CreateAssetImport(ctx context.Context, arg CreateAssetImportParams) (AssetImport, error)
+	CreateInquiryMessage(ctx context.Context, arg CreateInquiryMessageParams) (InquiryMessage, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateOffer(ctx context.Context, arg CreateOfferParams) (Offer, error)
+	CreateOfferEvent(ctx context.Context, arg CreateOfferEventParams) error
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreatePromotion(ctx context.Context, arg CreatePromotionParams) (AssetPromotion, error)
+	CreateReport(ctx context.Context, arg CreateReportParams) (Report, error)
+	CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error)
+	CreateSellerReview(ctx context.Context, arg CreateSellerReviewParams) (SellerReview, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateViewing(ctx context.Context, arg CreateViewingParams) (Viewing, error)
+	CreateViewingSlot(ctx context.Context, arg CreateViewingSlotParams) (ViewingSlot, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeclineAgencyInvite(ctx context.Context, arg DeclineAgencyInviteParams) (int64, error)
+	DeclineOpenOffers(ctx context.Context, arg DeclineOpenOffersParams) ([]Offer, error)
+	DeleteAgencyInvitesByUser(ctx context.Context, username string) error
 	DeleteAsset(ctx context.Context, id int64) error
+	DeleteAssetSlugRedirect(ctx context.Context, slug string) error
+	DeleteAssetTranslation(ctx context.Context, arg DeleteAssetTranslationParams) (int64, error)
+	DeleteAssetVisitorsBefore(ctx context.Context, day time.Time) error
+	DeleteExchangeRate(ctx context.Context, currency string) (int64, error)
+	DeleteFinishedJobs(ctx context.Context, finishedAt sql.NullTime) (int64, error)
 	DeleteImage(ctx context.Context, id int64) error
+	DeletePublishedOutboxEvents(ctx context.Context, publishedAt sql.NullTime) (int64, error)
+	DeleteSavedSearch(ctx context.Context, id int64) error
+	DeleteViewingSlot(ctx context.Context, id int64) (int64, error)
+	DeleteWebhookSubscription(ctx context.Context, id int64) error
+	EnqueueJob(ctx context.Context, arg EnqueueJobParams) (Job, error)
+	ExpirePromotions(ctx context.Context) ([]ExpirePromotionsRow, error)
+	ExtendAssetExpiry(ctx context.Context, arg ExtendAssetExpiryParams) (time.Time, error)
+	FinishAssetImport(ctx context.Context, arg FinishAssetImportParams) error
+	GetAgency(ctx context.Context, id int64) (Agency, error)
+	GetAgencyAssetCount(ctx context.Context, arg GetAgencyAssetCountParams) (int64, error)
+	GetAgencyAssets(ctx context.Context, arg GetAgencyAssetsParams) ([]GetAgencyAssetsRow, error)
+	GetAgencyForUpdate(ctx context.Context, id int64) (Agency, error)
+	GetAgencyInviteForUpdate(ctx context.Context, id int64) (AgencyInvite, error)
+	GetAgencyMembers(ctx context.Context, agencyID int64) ([]GetAgencyMembersRow, error)
+	GetAgencyMembership(ctx context.Context, username string) (AgencyMember, error)
 	GetAllAssets(ctx context.Context, arg GetAllAssetsParams) ([]GetAllAssetsRow, error)
 	GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]User, error)
+	GetApprovedAssetCountByUsername(ctx context.Context, owner string) (int64, error)
+	GetApprovedAssetsByUsername(ctx context.Context, arg GetApprovedAssetsByUsernameParams) ([]GetApprovedAssetsByUsernameRow, error)
+	GetAssetAnalytics(ctx context.Context, arg GetAssetAnalyticsParams) ([]GetAssetAnalyticsRow, error)
 	GetAssetById(ctx context.Context, id int64) (GetAssetByIdRow, error)
 	GetAssetContacts(ctx context.Context, assetID int64) ([]AssetContact, error)
-	GetAssetCount(ctx context.Context) (int64, error)
+	GetAssetContactsByAssetIDs(ctx context.Context, assetIds []int64) ([]AssetContact, error)
+	GetAssetCount(ctx context.Context, arg GetAssetCountParams) (int64, error)
 	GetAssetCountByUsername(ctx context.Context, owner string) (int64, error)
+	GetAssetExchangeRate(ctx context.Context, id int64) (ExchangeRate, error)
+	GetAssetImages(ctx context.Context, assetID int64) ([]AssetImage, error)
+	GetAssetImagesByAssetIDs(ctx context.Context, assetIds []int64) ([]AssetImage, error)
+	GetAssetImport(ctx context.Context, id int64) (AssetImport, error)
+	GetAssetPriceForUpdate(ctx context.Context, id int64) (int64, error)
+	GetAssetPriceHistory(ctx context.Context, arg GetAssetPriceHistoryParams) ([]AssetPriceHistory, error)
+	GetAssetPrimaryLocale(ctx context.Context, id int64) (string, error)
+	GetAssetPromotions(ctx context.Context, assetID int64) ([]AssetPromotion, error)
+	GetAssetSlugSource(ctx context.Context, id int64) (GetAssetSlugSourceRow, error)
+	GetAssetStatusCounts(ctx context.Context, arg GetAssetStatusCountsParams) (GetAssetStatusCountsRow, error)
+	GetAssetTranslations(ctx context.Context, assetID int64) ([]AssetTranslation, error)
 	GetAssetsByUsername(ctx context.Context, arg GetAssetsByUsernameParams) ([]GetAssetsByUsernameRow, error)
+	GetBuyerViewings(ctx context.Context, buyer string) ([]GetBuyerViewingsRow, error)
 	GetContact(ctx context.Context, id int64) (AssetContact, error)
+	GetDueSavedSearches(ctx context.Context, limit int32) ([]SavedSearch, error)
+	GetExchangeRate(ctx context.Context, currency string) (ExchangeRate, error)
+	GetFavoriteAssets(ctx context.Context, arg GetFavoriteAssetsParams) ([]GetFavoriteAssetsRow, error)
+	GetFavoriteCounts(ctx context.Context, assetIds []int64) ([]GetFavoriteCountsRow, error)
+	GetFavoritedAssetIDs(ctx context.Context, arg GetFavoritedAssetIDsParams) ([]int64, error)
 	GetImageById(ctx context.Context, id int64) (AssetImage, error)
+	GetInquiryInboxByAsset(ctx context.Context, seller string) ([]GetInquiryInboxByAssetRow, error)
+	GetInquiryMessages(ctx context.Context, arg GetInquiryMessagesParams) ([]InquiryMessage, error)
+	GetInquiryThread(ctx context.Context, id int64) (InquiryThread, error)
+	GetJob(ctx context.Context, id int64) (Job, error)
+	GetJobStats(ctx context.Context) ([]GetJobStatsRow, error)
+	GetLivePromotions(ctx context.Context, assetIds []int64) ([]GetLivePromotionsRow, error)
+	GetLocalizedAssets(ctx context.Context, arg GetLocalizedAssetsParams) ([]GetLocalizedAssetsRow, error)
+	GetModerationLogs(ctx context.Context, assetID int64) ([]AssetModerationLog, error)
+	GetModerationQueue(ctx context.Context, arg GetModerationQueueParams) ([]Asset, error)
+	GetNewAssetsPerBucket(ctx context.Context, arg GetNewAssetsPerBucketParams) ([]GetNewAssetsPerBucketRow, error)
+	GetNewUsersPerBucket(ctx context.Context, arg GetNewUsersPerBucketParams) ([]GetNewUsersPerBucketRow, error)
+	GetNotification(ctx context.Context, id int64) (Notification, error)
+	GetNotificationsAfter(ctx context.Context, arg GetNotificationsAfterParams) ([]Notification, error)
+	GetOffer(ctx context.Context, id int64) (Offer, error)
+	GetOfferEvents(ctx context.Context, offerID int64) ([]OfferEvent, error)
+	GetOfferForUpdate(ctx context.Context, id int64) (Offer, error)
+	GetOrder(ctx context.Context, id int64) (Order, error)
+	GetOrderByProviderRef(ctx context.Context, arg GetOrderByProviderRefParams) (Order, error)
+	GetOrderByProviderRefForUpdate(ctx context.Context, arg GetOrderByProviderRefForUpdateParams) (Order, error)
+	GetOrderForUpdate(ctx context.Context, id int64) (Order, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetPriceStats(ctx context.Context, arg GetPriceStatsParams) ([]GetPriceStatsRow, error)
+	GetPromotion(ctx context.Context, id int64) (AssetPromotion, error)
+	GetRepliedInquiryAssetIDs(ctx context.Context, arg GetRepliedInquiryAssetIDsParams) ([]int64, error)
+	GetReport(ctx context.Context, id int64) (Report, error)
+	GetReportCountsByAsset(ctx context.Context, arg GetReportCountsByAssetParams) ([]GetReportCountsByAssetRow, error)
+	GetSavedSearch(ctx context.Context, id int64) (SavedSearch, error)
+	GetSavedSearchMatches(ctx context.Context, arg GetSavedSearchMatchesParams) ([]GetSavedSearchMatchesRow, error)
+	GetSavedSearchesByUsername(ctx context.Context, username string) ([]SavedSearch, error)
+	GetSellerCalendar(ctx context.Context, arg GetSellerCalendarParams) ([]GetSellerCalendarRow, error)
+	GetSellerListingCounts(ctx context.Context, owner string) (GetSellerListingCountsRow, error)
+	GetSellerRatingSummary(ctx context.Context, seller string) (GetSellerRatingSummaryRow, error)
+	GetSellerResponseStats(ctx context.Context, seller string) (GetSellerResponseStatsRow, error)
+	GetSellerReview(ctx context.Context, id int64) (SellerReview, error)
+	GetTopSellers(ctx context.Context, arg GetTopSellersParams) ([]GetTopSellersRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
 	GetUserPassword(ctx context.Context, username string) (string, error)
+	GetViewingDetail(ctx context.Context, id int64) (GetViewingDetailRow, error)
+	GetViewingSlot(ctx context.Context, id int64) (ViewingSlot, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhookDeliveryAttempt, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	GetWebhookSubscriptionsForEvent(ctx context.Context, arg GetWebhookSubscriptionsForEventParams) ([]WebhookSubscription, error)
+	HasCompletedInteraction(ctx context.Context, arg HasCompletedInteractionParams) (bool, error)
 	InsertAsset(ctx context.Context, arg InsertAssetParams) (Asset, error)
 	InsertAssetContact(ctx context.Context, arg InsertAssetContactParams) (AssetContact, error)
 	InsertAssetImage(ctx context.Context, arg InsertAssetImageParams) (AssetImage, error)
+	InsertAssetPriceHistory(ctx context.Context, arg InsertAssetPriceHistoryParams) error
+	InsertAssetVisitors(ctx context.Context, arg InsertAssetVisitorsParams) (int64, error)
+	InsertModerationLog(ctx context.Context, arg InsertModerationLogParams) (AssetModerationLog, error)
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error
+	InsertPaymentEvent(ctx context.Context, arg InsertPaymentEventParams) (int64, error)
+	ListAgencies(ctx context.Context, arg ListAgenciesParams) ([]Agency, error)
+	ListAgencyInvites(ctx context.Context, agencyID int64) ([]AgencyInvite, error)
+	ListAgencyInvitesByUser(ctx context.Context, username string) ([]ListAgencyInvitesByUserRow, error)
+	ListAssetImports(ctx context.Context, arg ListAssetImportsParams) ([]ListAssetImportsRow, error)
+	ListAvailableViewingSlots(ctx context.Context, assetID int64) ([]ListAvailableViewingSlotsRow, error)
+	ListDeadOutboxEvents(ctx context.Context, arg ListDeadOutboxEventsParams) ([]OutboxEvent, error)
+	ListExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	ListInquiryThreads(ctx context.Context, arg ListInquiryThreadsParams) ([]ListInquiryThreadsRow, error)
+	ListJobs(ctx context.Context, arg ListJobsParams) ([]Job, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOffersByAsset(ctx context.Context, arg ListOffersByAssetParams) ([]Offer, error)
+	ListOffersByBuyer(ctx context.Context, arg ListOffersByBuyerParams) ([]Offer, error)
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error)
+	ListOrdersByUser(ctx context.Context, arg ListOrdersByUserParams) ([]Order, error)
+	ListPromotions(ctx context.Context, arg ListPromotionsParams) ([]AssetPromotion, error)
+	ListReports(ctx context.Context, arg ListReportsParams) ([]Report, error)
+	ListReviewsForModeration(ctx context.Context, arg ListReviewsForModerationParams) ([]SellerReview, error)
+	ListSellerReviews(ctx context.Context, arg ListSellerReviewsParams) ([]ListSellerReviewsRow, error)
+	ListSitemapAssets(ctx context.Context, arg ListSitemapAssetsParams) ([]ListSitemapAssetsRow, error)
+	ListViewingSlotsByAsset(ctx context.Context, assetID int64) ([]ListViewingSlotsByAssetRow, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context, arg ListWebhookSubscriptionsParams) ([]WebhookSubscription, error)
+	LockAssetForOffer(ctx context.Context, id int64) (LockAssetForOfferRow, error)
+	LockOutboxEvents(ctx context.Context, limit int32) ([]OutboxEvent, error)
 	LoginUser(ctx context.Context, username string) (LoginUserRow, error)
+	MarkAllNotificationsRead(ctx context.Context, username string) (int64, error)
+	MarkAssetSold(ctx context.Context, id int64) (int64, error)
+	MarkInquiryMessagesRead(ctx context.Context, arg MarkInquiryMessagesReadParams) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MarkOrderFailed(ctx context.Context, id int64) (Order, error)
+	MarkOrderPaid(ctx context.Context, arg MarkOrderPaidParams) (Order, error)
+	MarkOrderRefunded(ctx context.Context, id int64) (Order, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) (OutboxEvent, error)
+	MarkOutboxEventsPublished(ctx context.Context, ids []int64) error
+	MarkSavedSearchChecked(ctx context.Context, arg MarkSavedSearchCheckedParams) error
+	QueueWebhookDelivery(ctx context.Context, arg QueueWebhookDeliveryParams) error
+	RecordAssetImportRow(ctx context.Context, arg RecordAssetImportRowParams) error
+	ReleaseOrderRefund(ctx context.Context, id int64) error
+	RemoveAgencyMember(ctx context.Context, arg RemoveAgencyMemberParams) (int64, error)
 	RemoveContact(ctx context.Context, id int64) error
+	RemoveFavorite(ctx context.Context, arg RemoveFavoriteParams) error
+	RenewAsset(ctx context.Context, arg RenewAssetParams) (time.Time, error)
+	ReplySellerReview(ctx context.Context, arg ReplySellerReviewParams) (int64, error)
+	RequeueExpiredJobs(ctx context.Context) (int64, error)
+	RescheduleViewing(ctx context.Context, arg RescheduleViewingParams) error
+	ResolveAssetSlug(ctx context.Context, slug string) (ResolveAssetSlugRow, error)
+	RetryDeadJob(ctx context.Context, id int64) (Job, error)
+	RetryDeadOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	RetryJobLater(ctx context.Context, arg RetryJobLaterParams) (int64, error)
+	RevokePromotion(ctx context.Context, arg RevokePromotionParams) (AssetPromotion, error)
+	SetAgencyVerified(ctx context.Context, arg SetAgencyVerifiedParams) (Agency, error)
+	SetAssetAgency(ctx context.Context, arg SetAssetAgencyParams) error
+	SetAssetDetail(ctx context.Context, arg SetAssetDetailParams) error
+	SetAssetImportJob(ctx context.Context, arg SetAssetImportJobParams) error
+	SetAssetSlug(ctx context.Context, arg SetAssetSlugParams) error
+	SetAssetUnderOffer(ctx context.Context, arg SetAssetUnderOfferParams) error
+	SetOfferStatus(ctx context.Context, arg SetOfferStatusParams) (Offer, error)
+	SetOrderProviderRef(ctx context.Context, arg SetOrderProviderRefParams) (Order, error)
+	SetPrimaryTranslationDescription(ctx context.Context, id int64) error
+	SetSellerReviewStatus(ctx context.Context, arg SetSellerReviewStatusParams) (SellerReview, error)
+	SetUserTrusted(ctx context.Context, arg SetUserTrustedParams) error
+	StartAssetImport(ctx context.Context, id int64) (AssetImport, error)
+	UnassignAgentAssets(ctx context.Context, arg UnassignAgentAssetsParams) error
+	UpdateAgency(ctx context.Context, arg UpdateAgencyParams) (Agency, error)
+	UpdateAgencyMemberRole(ctx context.Context, arg UpdateAgencyMemberRoleParams) (int64, error)
 	UpdateAsset(ctx context.Context, arg UpdateAssetParams) error
+	UpdateAssetModeration(ctx context.Context, arg UpdateAssetModerationParams) error
 	UpdateContact(ctx context.Context, arg UpdateContactParams) error
+	UpdateInquiryThreadActivity(ctx context.Context, arg UpdateInquiryThreadActivityParams) error
+	UpdateOfferTerms(ctx context.Context, arg UpdateOfferTermsParams) (Offer, error)
+	UpdateSavedSearch(ctx context.Context, arg UpdateSavedSearchParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWebhookDeliveryResult(ctx context.Context, arg UpdateWebhookDeliveryResultParams) error
+	UpdateWebhookSubscription(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error)
+	UpsertAssetTranslation(ctx context.Context, arg UpsertAssetTranslationParams) (AssetTranslation, error)
+	UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) (ExchangeRate, error)
+	UpsertInquiryThread(ctx context.Context, arg UpsertInquiryThreadParams) (InquiryThread, error)
+	UpsertSavedSearchNotification(ctx context.Context, arg UpsertSavedSearchNotificationParams) error
+	WarnExpiringAssets(ctx context.Context, warnBefore time.Time) ([]WarnExpiringAssetsRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -15,7 +15,7 @@ INSERT INTO users
     (username, name, email, phone, password) 
 VALUES(
     $1, $2, $3, $4, $5
-) RETURNING username, name, email, phone, password, profile_url, created_at, updated_at, roles
+) RETURNING username, name, email, phone, password, profile_url, created_at, updated_at, roles, is_trusted
 `
 
 type CreateUserParams struct {
@@ -45,12 +45,13 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Roles,
+		&i.IsTrusted,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, name, email, phone, password, profile_url, created_at, updated_at, roles FROM users 
+SELECT username, name, email, phone, password, profile_url, created_at, updated_at, roles, is_trusted FROM users 
 WHERE username = $1
 `
 
@@ -67,6 +68,7 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Roles,
+		&i.IsTrusted,
 	)
 	return i, err
 }
@@ -0,0 +1,70 @@
+package db
+
+import "context"
+
+// userEvent never includes the password hash.
+func userEvent(eventType string, username string, payload map[string]any) OutboxMessage {
+	payload["username"] = username
+	return OutboxMessage{
+		AggregateType: "user",
+		AggregateID:   username,
+		EventType:     eventType,
+		Payload:       payload,
+	}
+}
+
+func (store *Store) CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, userEvent(EventUserCreated, user.Username, map[string]any{
+			"name":       user.Name,
+			"email":      user.Email,
+			"phone":      user.Phone,
+			"created_at": user.CreatedAt,
+		}))
+	})
+
+	return user, err
+}
+
+func (store *Store) UpdateUserTx(ctx context.Context, arg UpdateUserParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.UpdateUser(ctx, arg); err != nil {
+			return err
+		}
+
+		// only the fields that were changed
+		changes := map[string]any{}
+		if arg.Name.Valid {
+			changes["name"] = arg.Name.String
+		}
+		if arg.Email.Valid {
+			changes["email"] = arg.Email.String
+		}
+		if arg.Phone.Valid {
+			changes["phone"] = arg.Phone.String
+		}
+		if arg.ProfileUrl.Valid {
+			changes["profile_url"] = arg.ProfileUrl.String
+		}
+
+		return q.writeOutbox(ctx, userEvent(EventUserUpdated, arg.Username, changes))
+	})
+}
+
+func (store *Store) UpdateUserPasswordTx(ctx context.Context, arg UpdateUserPasswordParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.UpdateUserPassword(ctx, arg); err != nil {
+			return err
+		}
+
+		return q.writeOutbox(ctx, userEvent(EventUserPasswordChanged, arg.Username, map[string]any{}))
+	})
+}
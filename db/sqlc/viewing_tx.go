@@ -0,0 +1,22 @@
+package db
+
+import "context"
+
+// CreateViewingSlotsTx publishes several viewing slots at once; if any of them
+// clashes with an existing slot none are created.
+func (store *Store) CreateViewingSlotsTx(ctx context.Context, args []CreateViewingSlotParams) ([]ViewingSlot, error) {
+	slots := make([]ViewingSlot, 0, len(args))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, arg := range args {
+			slot, err := q.CreateViewingSlot(ctx, arg)
+			if err != nil {
+				return err
+			}
+			slots = append(slots, slot)
+		}
+		return nil
+	})
+
+	return slots, err
+}
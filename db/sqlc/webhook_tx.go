@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RecordWebhookAttemptTxParams struct {
+	DeliveryID int64
+	Attempt    int32
+	StatusCode sql.NullInt32
+	Error      sql.NullString
+	DurationMs int32
+	// Status is pending while the delivery will be retried at NextAttemptAt
+	Status        WebhookDeliveryStatus
+	NextAttemptAt time.Time
+}
+
+// RecordWebhookAttemptTx logs one delivery attempt and moves the delivery to
+// its next state.
+func (store *Store) RecordWebhookAttemptTx(ctx context.Context, arg RecordWebhookAttemptTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.CreateWebhookDeliveryAttempt(ctx, CreateWebhookDeliveryAttemptParams{
+			DeliveryID: arg.DeliveryID,
+			Attempt:    arg.Attempt,
+			StatusCode: arg.StatusCode,
+			Error:      arg.Error,
+			DurationMs: arg.DurationMs,
+		})
+		if err != nil {
+			return err
+		}
+
+		var deliveredAt sql.NullTime
+		if arg.Status == WebhookDeliveryStatusSucceeded {
+			deliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+
+		return q.UpdateWebhookDeliveryResult(ctx, UpdateWebhookDeliveryResultParams{
+			ID:             arg.DeliveryID,
+			Status:         arg.Status,
+			Attempts:       arg.Attempt,
+			NextAttemptAt:  arg.NextAttemptAt,
+			LastStatusCode: arg.StatusCode,
+			LastError:      arg.Error,
+			DeliveredAt:    deliveredAt,
+		})
+	})
+}
+
+// UpdateWebhookSubscriptionTx saves a subscription. Deactivating it cancels
+// the deliveries still pending, so they are not sent when it is turned back on.
+func (store *Store) UpdateWebhookSubscriptionTx(ctx context.Context, arg UpdateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	var subscription WebhookSubscription
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		subscription, err = q.UpdateWebhookSubscription(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if !subscription.Active {
+			_, err = q.CancelPendingWebhookDeliveries(ctx, subscription.ID)
+		}
+		return err
+	})
+
+	return subscription, err
+}
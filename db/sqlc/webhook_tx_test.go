@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+)
+
+func TestUpdateWebhookSubscriptionTxCancelsPendingDeliveries(t *testing.T) {
+	for _, active := range []bool{true, false} {
+		fake, conn := dbtest.New(t)
+		store := NewStore(conn)
+
+		fake.Handle("UpdateWebhookSubscription", func(args []driver.Value) (dbtest.Result, error) {
+			now := time.Now()
+			return dbtest.Row(args[0], "somchai", nil, args[1], "secret", "{asset.created}", args[3], "somchai", now, now), nil
+		})
+		var cancelled []driver.Value
+		fake.Handle("CancelPendingWebhookDeliveries", func(args []driver.Value) (dbtest.Result, error) {
+			cancelled = append(cancelled, args[0])
+			return dbtest.Result{RowsAffected: 2}, nil
+		})
+
+		subscription, err := store.UpdateWebhookSubscriptionTx(context.Background(), UpdateWebhookSubscriptionParams{
+			ID:         5,
+			Url:        "https://example.com/hook",
+			EventTypes: []string{"asset.created"},
+			Active:     active,
+		})
+		if err != nil {
+			t.Fatalf("active %t: UpdateWebhookSubscriptionTx: %v", active, err)
+		}
+		if subscription.Active != active {
+			t.Errorf("active %t: got subscription active %t", active, subscription.Active)
+		}
+
+		if active && len(cancelled) != 0 {
+			t.Errorf("active subscription cancelled deliveries of %v", cancelled)
+		}
+		if !active && !slices.Equal(cancelled, []driver.Value{int64(5)}) {
+			t.Errorf("deactivated subscription cancelled deliveries of %v, want [5]", cancelled)
+		}
+	}
+}
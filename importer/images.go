@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+)
+
+const (
+	maxImageSize = 10 << 20
+	fetchTimeout = 20 * time.Second
+)
+
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".gif": true}
+
+// ParseHosts reads a comma separated host allowlist.
+func ParseHosts(s string) []string {
+	var hosts []string
+	for _, host := range strings.Split(s, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// IsURL tells image URLs apart from file names in the uploaded zip.
+func IsURL(image string) bool {
+	lower := strings.ToLower(image)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// AllowedURL reports whether raw points at one of the allowed hosts. A host
+// also allows its subdomains.
+func AllowedURL(raw string, hosts []string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range hosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetcher downloads images from the allowed hosts only, redirects included.
+// An allowed host that resolves to a private address is refused as well.
+type Fetcher struct {
+	client *http.Client
+	hosts  []string
+}
+
+func NewFetcher(hosts []string) *Fetcher {
+	fetcher := &Fetcher{hosts: hosts, client: safehttp.NewClient(fetchTimeout)}
+
+	checkRedirect := fetcher.client.CheckRedirect
+	fetcher.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := checkRedirect(req, via); err != nil {
+			return err
+		}
+		if !AllowedURL(req.URL.String(), fetcher.hosts) {
+			return fmt.Errorf("redirect to %s is not allowed", req.URL.Host)
+		}
+		return nil
+	}
+	return fetcher
+}
+
+// Fetch downloads an image and returns it with a file extension matching
+// its content type.
+func (fetcher *Fetcher) Fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	if !AllowedURL(rawURL, fetcher.hosts) {
+		return nil, "", fmt.Errorf("%s is not on the image host allowlist", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rsp, err := fetcher.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("%s responded with status %d", rawURL, rsp.StatusCode)
+	}
+
+	contentType := strings.TrimSpace(strings.SplitN(rsp.Header.Get("Content-Type"), ";", 2)[0])
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return nil, "", fmt.Errorf("%s is not an image (%s)", rawURL, contentType)
+	}
+
+	data, err := readLimited(rsp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", rawURL, err)
+	}
+
+	return data, ext, nil
+}
+
+// FindInZip looks an image up by its path in the archive, or by file name
+// when the path does not match.
+func FindInZip(archive *zip.Reader, name string) *zip.File {
+	var byBase *zip.File
+	for _, file := range archive.File {
+		if file.Name == name {
+			return file
+		}
+		if byBase == nil && path.Base(file.Name) == path.Base(name) {
+			byBase = file
+		}
+	}
+	return byBase
+}
+
+// ReadZipImage returns an image from the archive and its file extension.
+func ReadZipImage(archive *zip.Reader, name string) ([]byte, string, error) {
+	file := FindInZip(archive, name)
+	if file == nil {
+		return nil, "", fmt.Errorf("%s is not in the zip file", name)
+	}
+
+	ext := strings.ToLower(path.Ext(file.Name))
+	if !imageExts[ext] {
+		return nil, "", fmt.Errorf("%s is not a jpg, png, webp or gif image", name)
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	data, err := readLimited(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	return data, ext, nil
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, errors.New("image is larger than 10MB")
+	}
+	return data, nil
+}
+
+// SaveUpload writes a file to the uploads directory the same way the image
+// upload handlers name them and returns its stored path.
+func SaveUpload(data []byte, ext string) (string, error) {
+	name := fmt.Sprintf("uploads/%d_import%s", time.Now().UnixNano(), ext)
+	if err := os.WriteFile("./"+name, data, 0o644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
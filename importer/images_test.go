@@ -0,0 +1,39 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+)
+
+func TestFetchRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the allowlist alone would let a loopback host through
+	fetcher := NewFetcher([]string{u.Hostname()})
+	_, _, err = fetcher.Fetch(context.Background(), srv.URL+"/photo.png")
+	if !errors.Is(err, safehttp.ErrPrivateAddress) {
+		t.Fatalf("got %v, want %v", err, safehttp.ErrPrivateAddress)
+	}
+}
+
+func TestFetchRefusesHostsOffTheAllowlist(t *testing.T) {
+	fetcher := NewFetcher([]string{"cdn.example.com"})
+	if _, _, err := fetcher.Fetch(context.Background(), "https://example.org/photo.png"); err == nil {
+		t.Fatal("got no error for a host off the allowlist")
+	}
+}
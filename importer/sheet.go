@@ -0,0 +1,166 @@
+// Package importer reads listing spreadsheets for bulk imports and fetches
+// the images they refer to.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Fields a sheet column can be mapped to.
+const (
+	FieldPrice         = "price"
+	FieldDetail        = "detail"
+	FieldPropertyType  = "property_type"
+	FieldProvince      = "province"
+	FieldContactName   = "contact_name"
+	FieldContactDetail = "contact_detail"
+	FieldImages        = "images"
+)
+
+var fields = []string{
+	FieldPrice, FieldDetail, FieldPropertyType, FieldProvince,
+	FieldContactName, FieldContactDetail, FieldImages,
+}
+
+var ErrUnsupportedFormat = errors.New("only .csv and .xlsx files are supported")
+
+// Mapping tells which sheet column, by header name, holds each field. Fields
+// left out are looked up by their own name.
+type Mapping map[string]string
+
+// Record is one data row of the sheet keyed by field.
+type Record struct {
+	// Line is the row number in the sheet, counting the header as 1
+	Line   int
+	Values map[string]string
+}
+
+// ReadSheet parses a .csv or .xlsx file into its rows.
+func ReadSheet(filename string, data []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		// spreadsheet programs like to start UTF-8 files with a BOM
+		data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case ".xlsx":
+		return readXLSX(data)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// MapRecords applies the mapping to the header row and returns the data rows
+// that are not empty.
+func MapRecords(rows [][]string, mapping Mapping) ([]Record, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("sheet is empty")
+	}
+
+	header := map[string]int{}
+	for i, name := range rows[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for field := range mapping {
+		if !isField(field) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+	}
+
+	columns := map[string]int{}
+	for _, field := range fields {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+
+		i, ok := header[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			if _, mapped := mapping[field]; mapped {
+				return nil, fmt.Errorf("column %q mapped to %s is not in the sheet", name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns[FieldPrice]; !ok {
+		return nil, errors.New("no column is mapped to price")
+	}
+	if _, ok := columns[FieldDetail]; !ok {
+		return nil, errors.New("no column is mapped to detail")
+	}
+
+	records := make([]Record, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		record := Record{Line: n + 2, Values: map[string]string{}}
+
+		empty := true
+		for field, i := range columns {
+			if i < len(row) {
+				value := strings.TrimSpace(row[i])
+				record.Values[field] = value
+				empty = empty && value == ""
+			}
+		}
+
+		if !empty {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func isField(name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitImages splits an images cell. Entries are separated by new lines,
+// semicolons or pipes, so URLs with commas survive.
+func SplitImages(cell string) []string {
+	parts := strings.FieldsFunc(cell, func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ';' || r == '|'
+	})
+
+	images := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			images = append(images, part)
+		}
+	}
+	return images
+}
+
+// Row is a validated listing waiting to be imported.
+type Row struct {
+	Line          int      `json:"line"`
+	Price         int64    `json:"price"`
+	Detail        string   `json:"detail"`
+	PropertyType  string   `json:"property_type"`
+	Province      string   `json:"province"`
+	ContactName   string   `json:"contact_name,omitempty"`
+	ContactDetail string   `json:"contact_detail,omitempty"`
+	Images        []string `json:"images,omitempty"`
+}
+
+// RowError reports why a row cannot be imported.
+type RowError struct {
+	Line    int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxXMLSize caps every part read from a workbook, a small zip can expand a lot.
+	maxXMLSize = 50 << 20
+	// maxColumns is the last column Excel supports, XFD.
+	maxColumns = 16384
+	// maxCells caps the cells of a sheet including the blanks that pad rows
+	// out to their last column, a short reference like "XFD1" expands a lot too.
+	maxCells = 4 << 20
+)
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX returns the cell values of the first worksheet. Only what a
+// listing sheet needs is supported: text, numbers and booleans.
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx file: %w", err)
+	}
+
+	files := map[string]*zip.File{}
+	var sheets []string
+	for _, file := range archive.File {
+		files[file.Name] = file
+		if strings.HasPrefix(file.Name, "xl/worksheets/") && strings.HasSuffix(file.Name, ".xml") {
+			sheets = append(sheets, file.Name)
+		}
+	}
+
+	sheetName := "xl/worksheets/sheet1.xml"
+	if files[sheetName] == nil {
+		if len(sheets) == 0 {
+			return nil, errors.New("xlsx file has no worksheet")
+		}
+		sort.Strings(sheets)
+		sheetName = sheets[0]
+	}
+
+	var shared []string
+	if file := files["xl/sharedStrings.xml"]; file != nil {
+		var sst xlsxSharedStrings
+		if err := decodeXMLFile(file, &sst); err != nil {
+			return nil, fmt.Errorf("cannot read shared strings: %w", err)
+		}
+
+		for _, item := range sst.Items {
+			text := item.Text
+			for _, run := range item.Runs {
+				text += run.Text
+			}
+			shared = append(shared, text)
+		}
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeXMLFile(files[sheetName], &sheet); err != nil {
+		return nil, fmt.Errorf("cannot read worksheet: %w", err)
+	}
+
+	cells := 0
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				if col, err = columnIndex(cell.Ref); err != nil {
+					return nil, err
+				}
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				n, err := strconv.Atoi(cell.Value)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("cell %s: invalid shared string", cell.Ref)
+				}
+				value = shared[n]
+			case "inlineStr":
+				value = cell.Inline.Text
+			case "b":
+				value = map[string]string{"1": "TRUE", "0": "FALSE"}[cell.Value]
+			}
+
+			if col >= len(record) {
+				if cells += col + 1 - len(record); cells > maxCells {
+					return nil, fmt.Errorf("worksheet has more than %d cells", maxCells)
+				}
+				record = append(record, make([]string, col+1-len(record))...)
+			}
+			record[col] = value
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func decodeXMLFile(file *zip.File, v any) error {
+	r, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return xml.NewDecoder(io.LimitReader(r, maxXMLSize)).Decode(v)
+}
+
+// columnIndex turns a cell reference like "AB12" into the zero based column 27.
+// Columns past XFD are rejected.
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			if col = col*26 + int(r-'A'+1); col > maxColumns {
+				break
+			}
+			continue
+		}
+		if i == 0 || r < '0' || r > '9' {
+			break
+		}
+		return col - 1, nil
+	}
+	return 0, fmt.Errorf("invalid cell reference %q", ref)
+}
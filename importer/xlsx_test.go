@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{"A1", 0},
+		{"Z9", 25},
+		{"AA1", 26},
+		{"AB12", 27},
+		{"XFD1048576", maxColumns - 1},
+	}
+
+	for _, tc := range tests {
+		got, err := columnIndex(tc.ref)
+		if err != nil {
+			t.Errorf("columnIndex(%q): %v", tc.ref, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("columnIndex(%q) = %d, want %d", tc.ref, got, tc.want)
+		}
+	}
+}
+
+func TestColumnIndexInvalid(t *testing.T) {
+	refs := []string{
+		"",
+		"1",
+		"A",
+		"a1",
+		"A-1",
+		"XFE1",
+		"ZZZZZZZ1",
+		// overflows int without the column limit
+		strings.Repeat("Z", 14) + "1",
+	}
+
+	for _, ref := range refs {
+		if col, err := columnIndex(ref); err == nil {
+			t.Errorf("columnIndex(%q) = %d, want an error", ref, col)
+		}
+	}
+}
+
+// xlsxFile zips sheet up as the first worksheet of a workbook.
+func xlsxFile(t *testing.T, sheet string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	w, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatalf("cannot create worksheet: %v", err)
+	}
+	if _, err := w.Write([]byte(`<worksheet><sheetData>` + sheet + `</sheetData></worksheet>`)); err != nil {
+		t.Fatalf("cannot write worksheet: %v", err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("cannot close workbook: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := xlsxFile(t, `
+		<row><c r="A1" t="inlineStr"><is><t>price</t></is></c><c r="C1" t="inlineStr"><is><t>detail</t></is></c></row>
+		<row><c r="A2"><v>4500000</v></c><c r="C2" t="inlineStr"><is><t>Condo near BTS</t></is></c></row>`)
+
+	rows, err := ReadSheet("listings.xlsx", data)
+	if err != nil {
+		t.Fatalf("ReadSheet: %v", err)
+	}
+
+	want := [][]string{{"price", "", "detail"}, {"4500000", "", "Condo near BTS"}}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i+1, rows[i], want[i])
+		}
+	}
+}
+
+func TestReadXLSXBadCellReference(t *testing.T) {
+	for _, ref := range []string{"ZZZZZZZ1", strings.Repeat("Z", 14) + "1", "XFE1", "1A"} {
+		data := xlsxFile(t, `<row><c r="`+ref+`"><v>1</v></c></row>`)
+		if _, err := ReadSheet("listings.xlsx", data); err == nil {
+			t.Errorf("cell %s: got no error", ref)
+		}
+	}
+}
+
+func TestReadXLSXTooManyCells(t *testing.T) {
+	row := `<row><c r="XFD1"><v>1</v></c></row>`
+	data := xlsxFile(t, strings.Repeat(row, maxCells/maxColumns+1))
+
+	if _, err := ReadSheet("listings.xlsx", data); err == nil {
+		t.Error("got no error for a sheet past the cell limit")
+	}
+}
@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const (
+	DefaultQueue       = "default"
+	DefaultMaxAttempts = 5
+)
+
+// ErrDuplicateJob is returned when a job with the same unique key was
+// already enqueued.
+var ErrDuplicateJob = errors.New("job with this unique key already exists")
+
+type enqueueOptions struct {
+	queue       string
+	runAt       time.Time
+	maxAttempts int32
+	uniqueKey   string
+}
+
+type Option func(*enqueueOptions)
+
+// OnQueue puts the job on a named queue instead of the default one.
+func OnQueue(queue string) Option {
+	return func(opts *enqueueOptions) {
+		opts.queue = queue
+	}
+}
+
+// RunAt schedules the job for a later time.
+func RunAt(t time.Time) Option {
+	return func(opts *enqueueOptions) {
+		opts.runAt = t
+	}
+}
+
+// Delay schedules the job to run after d.
+func Delay(d time.Duration) Option {
+	return func(opts *enqueueOptions) {
+		opts.runAt = time.Now().Add(d)
+	}
+}
+
+// MaxAttempts sets how often the job is tried before it is moved to the
+// dead state.
+func MaxAttempts(n int) Option {
+	return func(opts *enqueueOptions) {
+		opts.maxAttempts = int32(n)
+	}
+}
+
+// UniqueKey makes sure a job is only enqueued once for the key.
+func UniqueKey(key string) Option {
+	return func(opts *enqueueOptions) {
+		opts.uniqueKey = key
+	}
+}
+
+// Client adds jobs to the queue. Any number of processes can enqueue while
+// workers run elsewhere.
+type Client struct {
+	store *db.Store
+}
+
+func NewClient(store *db.Store) *Client {
+	return &Client{store: store}
+}
+
+// Enqueue stores a job of the given kind. The payload is encoded as JSON and
+// decoded into the payload type of the kind's handler.
+func (client *Client) Enqueue(ctx context.Context, kind string, payload any, options ...Option) (db.Job, error) {
+	opts := enqueueOptions{
+		queue:       DefaultQueue,
+		runAt:       time.Now(),
+		maxAttempts: DefaultMaxAttempts,
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return db.Job{}, err
+	}
+
+	job, err := client.store.EnqueueJob(ctx, db.EnqueueJobParams{
+		Queue:       opts.queue,
+		Kind:        kind,
+		Payload:     data,
+		MaxAttempts: opts.maxAttempts,
+		RunAt:       opts.runAt,
+		UniqueKey:   sql.NullString{String: opts.uniqueKey, Valid: opts.uniqueKey != ""},
+	})
+	if err == sql.ErrNoRows {
+		return job, ErrDuplicateJob
+	}
+
+	return job, err
+}
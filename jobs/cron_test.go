@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"*/15 * * * *", false},
+		{"0 9-17/2 * * 1-5", false},
+		{"30 2 1,15 * *", false},
+		{"0 0 * * 7", false},
+		{"5/10 * * * *", false},
+		{"@daily", false},
+		{" @weekly ", false},
+		{"", true},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+		{"5-1 * * * *", true},
+		{"*/0 * * * *", true},
+		{"a * * * *", true},
+		{"1-a * * * *", true},
+		{"@yearly", true},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSchedule(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSchedule(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		layout := "2006-01-02 15:04"
+		if len(s) > len(layout) {
+			layout += ":05"
+		}
+		v, err := time.Parse(layout, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want string
+	}{
+		{"every minute", "* * * * *", "2025-03-10 10:00", "2025-03-10 10:01"},
+		{"seconds are dropped", "* * * * *", "2025-03-10 10:00:42", "2025-03-10 10:01"},
+		{"step", "*/15 * * * *", "2025-03-10 10:16", "2025-03-10 10:30"},
+		{"step wraps the hour", "*/15 * * * *", "2025-03-10 10:45", "2025-03-10 11:00"},
+		{"step from a value", "5/20 * * * *", "2025-03-10 10:26", "2025-03-10 10:45"},
+		{"range", "0 9-17 * * *", "2025-03-10 17:30", "2025-03-11 09:00"},
+		{"range with step", "0 9-17/4 * * *", "2025-03-10 13:01", "2025-03-10 17:00"},
+		{"list", "30 2 1,15 * *", "2025-03-02 00:00", "2025-03-15 02:30"},
+		{"weekdays", "0 8 * * 1-5", "2025-03-07 09:00", "2025-03-10 08:00"}, // friday to monday
+		{"sunday as 0", "0 0 * * 0", "2025-03-10 00:00", "2025-03-16 00:00"},
+		{"sunday as 7", "0 0 * * 7", "2025-03-10 00:00", "2025-03-16 00:00"},
+		{"weekly", "@weekly", "2025-03-10 00:00", "2025-03-16 00:00"},
+		{"month rollover", "0 0 1 * *", "2025-03-10 00:00", "2025-04-01 00:00"},
+		{"year rollover", "@monthly", "2025-12-15 12:00", "2026-01-01 00:00"},
+		{"month restricted", "0 12 1 6 *", "2025-07-01 00:00", "2026-06-01 12:00"},
+		{"31st skips short months", "0 0 31 * *", "2025-03-31 00:00", "2025-05-31 00:00"},
+		{"leap day", "0 0 29 2 *", "2025-03-01 00:00", "2028-02-29 00:00"},
+		// both day fields restricted: either one matches
+		{"day of month or week", "0 0 13 * 5", "2025-03-10 00:00", "2025-03-13 00:00"},
+		{"day of week or month", "0 0 13 * 5", "2025-03-13 00:00", "2025-03-14 00:00"},
+		// a stepped "*" still counts as unrestricted, so both must match
+		{"stepped day of month", "0 0 */2 * 1", "2025-03-10 00:00", "2025-03-17 00:00"},
+		{"stepped day of week", "0 0 13 * */2", "2025-03-10 00:00", "2025-03-13 00:00"},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: ParseSchedule(%q): %v", tt.name, tt.spec, err)
+		}
+
+		if got := schedule.Next(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("%s: %q.Next(%s) = %s, want %s", tt.name, tt.spec, tt.from, got.Format("2006-01-02 15:04 Mon"), tt.want)
+		}
+	}
+}
+
+func TestScheduleNextNeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("got %s, want the zero time for february 30th", got)
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		interval time.Duration
+		want     time.Time
+	}{
+		{time.Minute, time.Date(2026, 3, 14, 10, 8, 0, 0, time.UTC)},
+		{5 * time.Minute, time.Date(2026, 3, 14, 10, 10, 0, 0, time.UTC)},
+		{time.Hour, time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := every(tt.interval).Next(base); !got.Equal(tt.want) {
+			t.Errorf("every(%s).Next(%s) = %s, want %s", tt.interval, base, got, tt.want)
+		}
+	}
+
+	// a run time is never repeated
+	at := time.Date(2026, 3, 14, 10, 10, 0, 0, time.UTC)
+	if got := every(5 * time.Minute).Next(at); !got.Equal(at.Add(5 * time.Minute)) {
+		t.Errorf("every(5m).Next(%s) = %s, want %s", at, got, at.Add(5*time.Minute))
+	}
+}
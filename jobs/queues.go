@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseQueues reads a queue list like "default:4,uploads:2" into the number
+// of jobs each queue may run at once. A queue without a number runs one.
+func ParseQueues(s string) (map[string]int, error) {
+	queues := map[string]int{}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, concurrency := part, 1
+		if i := strings.Index(part, ":"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid concurrency in %q", part)
+			}
+			name, concurrency = strings.TrimSpace(part[:i]), n
+		}
+
+		queues[name] = concurrency
+	}
+
+	return queues, nil
+}
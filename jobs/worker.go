@@ -0,0 +1,347 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const (
+	baseBackoff = 15 * time.Second
+	maxBackoff  = time.Hour
+	// extra time past the job timeout before another worker may take it over
+	leaseGrace     = time.Minute
+	reaperInterval = time.Minute
+	cronInterval   = 15 * time.Second
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying will not fix. The job goes straight
+// to the dead state.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+type handlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// scheduler tells when a recurring job runs next, see Schedule and every.
+type scheduler interface {
+	Next(t time.Time) time.Time
+}
+
+type cronEntry struct {
+	name     string
+	kind     string
+	payload  any
+	options  []Option
+	schedule scheduler
+	next     time.Time
+}
+
+// Worker runs the jobs of its queues with a fixed number of goroutines per
+// queue. Jobs are claimed with FOR UPDATE SKIP LOCKED, so any number of
+// workers can share the queues.
+type Worker struct {
+	store        *db.Store
+	client       *Client
+	id           string
+	pollInterval time.Duration
+	timeout      time.Duration
+
+	queues   map[string]int
+	handlers map[string]handlerFunc
+	crons    []*cronEntry
+}
+
+func NewWorker(store *db.Store, pollInterval, timeout time.Duration) *Worker {
+	host, _ := os.Hostname()
+
+	return &Worker{
+		store:        store,
+		client:       NewClient(store),
+		id:           fmt.Sprintf("%s:%d:%04x", host, os.Getpid(), rand.Intn(1<<16)),
+		pollInterval: pollInterval,
+		timeout:      timeout,
+		queues:       map[string]int{DefaultQueue: 1},
+		handlers:     map[string]handlerFunc{},
+	}
+}
+
+// Queue sets how many jobs of the queue this worker runs at the same time.
+func (worker *Worker) Queue(name string, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	worker.queues[name] = concurrency
+}
+
+// Register adds the handler for a job kind. The job payload is decoded into T.
+func Register[T any](worker *Worker, kind string, fn func(ctx context.Context, payload T) error) {
+	worker.handlers[kind] = func(ctx context.Context, data json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return Permanent(fmt.Errorf("cannot decode payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// Cron enqueues a job of the kind every time the cron spec matches. When
+// several workers share the database, only one of them enqueues each run.
+func (worker *Worker) Cron(name, spec, kind string, payload any, options ...Option) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	worker.crons = append(worker.crons, &cronEntry{
+		name:     name,
+		kind:     kind,
+		payload:  payload,
+		options:  options,
+		schedule: schedule,
+	})
+	return nil
+}
+
+// Every enqueues a job of the kind once every interval, on multiples of the
+// interval so that every worker agrees on the run times. Like Cron, it does
+// not run more often than once a minute.
+func (worker *Worker) Every(name string, interval time.Duration, kind string, payload any, options ...Option) error {
+	if interval < time.Minute {
+		return fmt.Errorf("every %s: interval must be at least a minute", name)
+	}
+
+	worker.crons = append(worker.crons, &cronEntry{
+		name:     name,
+		kind:     kind,
+		payload:  payload,
+		options:  options,
+		schedule: every(interval),
+	})
+	return nil
+}
+
+// Start runs the queues until ctx is cancelled, then waits for the running
+// jobs to finish.
+func (worker *Worker) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for queue, concurrency := range worker.queues {
+		wg.Add(1)
+		go func(queue string, concurrency int) {
+			defer wg.Done()
+			worker.runQueue(ctx, queue, concurrency)
+		}(queue, concurrency)
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		worker.runReaper(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		worker.runCron(ctx)
+	}()
+
+	wg.Wait()
+}
+
+func (worker *Worker) runQueue(ctx context.Context, queue string, concurrency int) {
+	ticker := time.NewTicker(worker.pollInterval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, concurrency)
+	done := make(chan struct{}, concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	for {
+		if free := concurrency - len(slots); free > 0 {
+			claimed, err := worker.store.ClaimJobs(ctx, db.ClaimJobsParams{
+				WorkerID:    sql.NullString{String: worker.id, Valid: true},
+				LockedUntil: sql.NullTime{Time: time.Now().Add(worker.timeout + leaseGrace), Valid: true},
+				Queue:       queue,
+				BatchSize:   int32(free),
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Printf("job worker: cannot claim %s jobs: %v", queue, err)
+			}
+
+			for _, job := range claimed {
+				slots <- struct{}{}
+				running.Add(1)
+				go func(job db.Job) {
+					defer func() {
+						<-slots
+						// wake the loop up to claim the next job, unless it is already awake
+						select {
+						case done <- struct{}{}:
+						default:
+						}
+						running.Done()
+					}()
+					worker.run(ctx, job)
+				}(job)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-done:
+		}
+	}
+}
+
+// run executes one job. It is not cancelled with the worker, a job that was
+// started gets its full timeout to finish.
+func (worker *Worker) run(ctx context.Context, job db.Job) {
+	ctx = context.WithoutCancel(ctx)
+
+	jobCtx, cancel := context.WithTimeout(ctx, worker.timeout)
+	err := worker.execute(jobCtx, job)
+	cancel()
+
+	// the job is only finished while this worker still holds it; after its lock
+	// expired it may have been requeued and claimed by another worker
+	lockedBy := sql.NullString{String: worker.id, Valid: true}
+
+	if err == nil {
+		updated, err := worker.store.CompleteJob(ctx, db.CompleteJobParams{ID: job.ID, LockedBy: lockedBy})
+		if err != nil {
+			log.Printf("job worker: cannot complete job %d: %v", job.ID, err)
+		} else if updated == 0 {
+			log.Printf("job worker: job %d (%s) finished after this worker lost it", job.ID, job.Kind)
+		}
+		return
+	}
+
+	log.Printf("job worker: job %d (%s) attempt %d failed: %v", job.ID, job.Kind, job.Attempts, err)
+	lastError := sql.NullString{String: err.Error(), Valid: true}
+
+	var updated int64
+	var permanent permanentError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		updated, err = worker.store.BuryJob(ctx, db.BuryJobParams{ID: job.ID, LockedBy: lockedBy, LastError: lastError})
+	} else {
+		updated, err = worker.store.RetryJobLater(ctx, db.RetryJobLaterParams{
+			ID:        job.ID,
+			LockedBy:  lockedBy,
+			RunAt:     time.Now().Add(backoff(job.Attempts)),
+			LastError: lastError,
+		})
+	}
+	if err != nil {
+		log.Printf("job worker: cannot record failure of job %d: %v", job.ID, err)
+	} else if updated == 0 {
+		log.Printf("job worker: job %d (%s) failed after this worker lost it", job.ID, job.Kind)
+	}
+}
+
+func (worker *Worker) execute(ctx context.Context, job db.Job) (err error) {
+	handler, ok := worker.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("no handler registered for %q", job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// backoff is the wait before the next attempt, doubling from 15 seconds up to
+// an hour with some jitter so failed jobs do not retry in lockstep.
+func backoff(attempt int32) time.Duration {
+	d := baseBackoff
+	for i := int32(1); i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/10+1))
+}
+
+// runReaper puts back the jobs whose worker died or ran past the timeout.
+func (worker *Worker) runReaper(ctx context.Context) {
+	ticker := time.NewTicker(reaperInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := worker.store.RequeueExpiredJobs(ctx); err != nil && ctx.Err() == nil {
+			log.Println("job worker: cannot requeue expired jobs:", err)
+		} else if n > 0 {
+			log.Printf("job worker: requeued %d expired jobs", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (worker *Worker) runCron(ctx context.Context) {
+	if len(worker.crons) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range worker.crons {
+		entry.next = entry.schedule.Next(now)
+	}
+
+	ticker := time.NewTicker(cronInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+
+		for _, entry := range worker.crons {
+			if entry.next.IsZero() || now.Before(entry.next) {
+				continue
+			}
+
+			// the run time in the key lets only one worker enqueue each run
+			options := append([]Option{
+				RunAt(entry.next),
+				UniqueKey(fmt.Sprintf("cron:%s:%d", entry.name, entry.next.Unix())),
+			}, entry.options...)
+
+			_, err := worker.client.Enqueue(ctx, entry.kind, entry.payload, options...)
+			if err != nil && !errors.Is(err, ErrDuplicateJob) {
+				log.Printf("job worker: cannot enqueue cron %s: %v", entry.name, err)
+				continue
+			}
+
+			entry.next = entry.schedule.Next(now)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/db/dbtest"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int32
+		want    time.Duration
+	}{
+		{0, 15 * time.Second},
+		{1, 15 * time.Second},
+		{2, 30 * time.Second},
+		{3, time.Minute},
+		{5, 4 * time.Minute},
+		{8, 32 * time.Minute},
+		{9, time.Hour},
+		{25, time.Hour},
+		{1000, time.Hour},
+	}
+
+	for _, tt := range tests {
+		// jitter adds up to a tenth on top
+		for i := 0; i < 20; i++ {
+			got := backoff(tt.attempt)
+			if got < tt.want || got > tt.want+tt.want/10 {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.want, tt.want+tt.want/10)
+				break
+			}
+		}
+	}
+}
+
+func TestEveryRejectsShortIntervals(t *testing.T) {
+	worker := NewWorker(nil, time.Second, time.Minute)
+
+	if err := worker.Every("sweep", 30*time.Second, "sweep", nil); err == nil {
+		t.Error("got no error for an interval under a minute")
+	}
+	if err := worker.Every("sweep", time.Minute, "sweep", nil); err != nil {
+		t.Errorf("Every: %v", err)
+	}
+	if len(worker.crons) != 1 {
+		t.Errorf("got %d schedules, want 1", len(worker.crons))
+	}
+}
+
+func TestRunRecordsOnlyJobsTheWorkerHolds(t *testing.T) {
+	fake, conn := dbtest.New(t)
+	worker := NewWorker(db.NewStore(conn), time.Second, time.Minute)
+	Register(worker, "ok", func(ctx context.Context, _ struct{}) error { return nil })
+	Register(worker, "fails", func(ctx context.Context, _ struct{}) error { return errors.New("boom") })
+	Register(worker, "broken", func(ctx context.Context, _ struct{}) error { return Permanent(errors.New("boom")) })
+
+	var lockedBy []driver.Value
+	lost := func(args []driver.Value) (dbtest.Result, error) {
+		lockedBy = append(lockedBy, args[1])
+		// the lock expired and another worker claimed the job
+		return dbtest.Result{RowsAffected: 0}, nil
+	}
+	fake.Handle("CompleteJob", lost)
+	fake.Handle("RetryJobLater", lost)
+	fake.Handle("BuryJob", lost)
+
+	for _, kind := range []string{"ok", "fails", "broken"} {
+		worker.run(context.Background(), db.Job{ID: 7, Kind: kind, Payload: json.RawMessage(`{}`), Attempts: 1, MaxAttempts: 3})
+	}
+
+	if len(lockedBy) != 3 {
+		t.Fatalf("got %d updates, want 3", len(lockedBy))
+	}
+	for _, value := range lockedBy {
+		if value != worker.id {
+			t.Errorf("job updated for locked_by %v, want %q", value, worker.id)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"sync"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// subscriberBuffer is how many notifications a slow client may lag behind
+// before further ones are dropped for it; it catches up from the list endpoint.
+const subscriberBuffer = 16
+
+// Hub fans notifications out to the live streams connected to this instance.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan db.Notification]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[string]map[chan db.Notification]struct{})}
+}
+
+// Subscribe registers a stream for username. Call the returned function once
+// the stream is closed.
+func (hub *Hub) Subscribe(username string) (<-chan db.Notification, func()) {
+	ch := make(chan db.Notification, subscriberBuffer)
+
+	hub.mu.Lock()
+	if hub.subscribers[username] == nil {
+		hub.subscribers[username] = make(map[chan db.Notification]struct{})
+	}
+	hub.subscribers[username][ch] = struct{}{}
+	hub.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			hub.mu.Lock()
+			delete(hub.subscribers[username], ch)
+			if len(hub.subscribers[username]) == 0 {
+				delete(hub.subscribers, username)
+			}
+			hub.mu.Unlock()
+		})
+	}
+}
+
+func (hub *Hub) HasSubscribers(username string) bool {
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	return len(hub.subscribers[username]) > 0
+}
+
+func (hub *Hub) Publish(notification db.Notification) {
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+
+	for ch := range hub.subscribers[notification.Username] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// InAppNotifier stores notifications in the notifications table for the user
+// to read in the app.
+type InAppNotifier struct {
+	store *db.Store
+}
+
+func NewInAppNotifier(store *db.Store) *InAppNotifier {
+	return &InAppNotifier{store: store}
+}
+
+func (notifier *InAppNotifier) Notify(ctx context.Context, to Recipient, notification Notification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = notifier.store.CreateNotification(ctx, db.CreateNotificationParams{
+		Username: to.Username,
+		Kind:     notification.Kind,
+		Title:    notification.Title,
+		Body:     notification.Body,
+		Data:     data,
+	})
+	return err
+}
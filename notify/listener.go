@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// channel must match the pg_notify call in the notifications trigger.
+const channel = "notifications"
+
+// Listener relays the notifications trigger from Postgres to the local Hub, so
+// a notification created on any instance reaches streams on every instance.
+type Listener struct {
+	dbSource string
+	store    *db.Store
+	hub      *Hub
+}
+
+func NewListener(dbSource string, store *db.Store, hub *Hub) *Listener {
+	return &Listener{
+		dbSource: dbSource,
+		store:    store,
+		hub:      hub,
+	}
+}
+
+// Start listens until ctx is cancelled, reconnecting when the connection drops.
+func (listener *Listener) Start(ctx context.Context) error {
+	pqListener := pq.NewListener(listener.dbSource, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("notification listener:", err)
+		}
+	})
+	defer pqListener.Close()
+
+	if err := pqListener.Listen(channel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-pqListener.Notify:
+			// nil after a reconnect, anything sent meanwhile is in the list endpoint
+			if n == nil {
+				continue
+			}
+			listener.relay(ctx, n.Extra)
+		case <-time.After(90 * time.Second):
+			go pqListener.Ping()
+		}
+	}
+}
+
+func (listener *Listener) relay(ctx context.Context, payload string) {
+	var event struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	}
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		log.Println("notification listener: invalid payload:", err)
+		return
+	}
+
+	if !listener.hub.HasSubscribers(event.Username) {
+		return
+	}
+
+	notification, err := listener.store.GetNotification(ctx, event.ID)
+	if err != nil {
+		log.Println("notification listener: cannot get notification:", err)
+		return
+	}
+
+	listener.hub.Publish(notification)
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// Recipient is who a notification is delivered to. Each notifier only uses
+// the fields it needs.
+type Recipient struct {
+	Username   string
+	Email      string
+	WebhookURL string
+}
+
+type Notification struct {
+	Kind  string         `json:"kind"`
+	Title string         `json:"title"`
+	Body  string         `json:"body"`
+	Data  map[string]any `json:"data"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, to Recipient, notification Notification) error
+}
+
+// Dispatcher sends a notification through the notifier registered for a channel.
+type Dispatcher struct {
+	notifiers map[db.NotificationChannel]Notifier
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{notifiers: make(map[db.NotificationChannel]Notifier)}
+}
+
+func (dispatcher *Dispatcher) Register(channel db.NotificationChannel, notifier Notifier) {
+	dispatcher.notifiers[channel] = notifier
+}
+
+func (dispatcher *Dispatcher) Dispatch(ctx context.Context, channel db.NotificationChannel, to Recipient, notification Notification) error {
+	notifier, ok := dispatcher.notifiers[channel]
+	if !ok {
+		return fmt.Errorf("no notifier registered for channel %q", channel)
+	}
+
+	return notifier.Notify(ctx, to, notification)
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+)
+
+// WebhookNotifier POSTs the notification as JSON to the recipient's webhook URL.
+// The URL comes from the user, so it is never allowed to reach a private address.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{client: safehttp.NewClient(10 * time.Second)}
+}
+
+func (notifier *WebhookNotifier) Notify(ctx context.Context, to Recipient, notification Notification) error {
+	if to.WebhookURL == "" {
+		return errors.New("recipient has no webhook url")
+	}
+
+	body, err := json.Marshal(struct {
+		Username string `json:"username"`
+		Notification
+	}{to.Username, notification})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := notifier.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", rsp.StatusCode)
+	}
+
+	return nil
+}
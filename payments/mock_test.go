@@ -0,0 +1,72 @@
+package payments
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMockProviderVerifyWebhook(t *testing.T) {
+	provider := NewMockProvider("secret", "http://localhost:8080")
+
+	payload, signature, err := provider.SignedEvent(EventPaymentSucceeded, "mock_abc", 49900)
+	if err != nil {
+		t.Fatalf("SignedEvent: %v", err)
+	}
+
+	event, err := provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		t.Fatalf("VerifyWebhook: %v", err)
+	}
+	if event.Type != EventPaymentSucceeded || event.Reference != "mock_abc" || event.Amount != 49900 || event.ID == "" {
+		t.Errorf("got %+v", event)
+	}
+}
+
+func TestMockProviderVerifyWebhookBadSignature(t *testing.T) {
+	provider := NewMockProvider("secret", "http://localhost:8080")
+
+	payload, signature, err := provider.SignedEvent(EventPaymentSucceeded, "mock_abc", 49900)
+	if err != nil {
+		t.Fatalf("SignedEvent: %v", err)
+	}
+
+	_, otherSignature, err := NewMockProvider("other secret", "").SignedEvent(EventPaymentSucceeded, "mock_abc", 49900)
+	if err != nil {
+		t.Fatalf("SignedEvent: %v", err)
+	}
+
+	tampered := []byte(string(payload[:len(payload)-1]) + "0}")
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+	}{
+		{"empty", payload, ""},
+		{"not hex", payload, "not-a-signature"},
+		{"other secret", payload, otherSignature},
+		{"truncated", payload, signature[:len(signature)-2]},
+		{"tampered payload", tampered, signature},
+	}
+
+	for _, tt := range tests {
+		_, err := provider.VerifyWebhook(tt.payload, tt.signature)
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidSignature)
+		}
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	if _, err := NewProvider("", "secret", ""); err == nil {
+		t.Error("an empty provider name must not fall back to the mock provider")
+	}
+	if _, err := NewProvider("stripe", "secret", ""); err == nil {
+		t.Error("got no error for an unknown provider")
+	}
+
+	provider, err := NewProvider("mock", "secret", "")
+	if err != nil || provider.Name() != "mock" {
+		t.Errorf("got %v, %v, want the mock provider", provider, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Package payments abstracts the payment provider used to sell listing
+// features such as promotions.
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+type EventType string
+
+const (
+	EventPaymentSucceeded EventType = "payment.succeeded"
+	EventPaymentFailed    EventType = "payment.failed"
+	EventRefunded         EventType = "payment.refunded"
+)
+
+type CheckoutRequest struct {
+	OrderID     int64
+	Amount      int64
+	Currency    string
+	Description string
+}
+
+type Checkout struct {
+	// Reference is the provider's id for the payment, webhooks refer to it.
+	Reference string
+	// URL is where the buyer is sent to pay.
+	URL string
+}
+
+// Event is a verified webhook notification from the provider.
+type Event struct {
+	// ID is unique per event and used to ignore redelivered webhooks.
+	ID        string
+	Type      EventType
+	Reference string
+	Amount    int64
+}
+
+type Provider interface {
+	Name() string
+	CreateCheckout(ctx context.Context, req CheckoutRequest) (Checkout, error)
+	// SignatureHeader is the request header the provider signs webhooks in.
+	SignatureHeader() string
+	// VerifyWebhook checks the signature of a webhook payload and parses it.
+	VerifyWebhook(payload []byte, signature string) (Event, error)
+	Refund(ctx context.Context, reference string, amount int64) error
+}
+
+// NewProvider creates the provider configured by name. There is no default,
+// the mock provider pays for anything and has to be asked for by name.
+func NewProvider(name, webhookSecret, baseURL string) (Provider, error) {
+	switch name {
+	case "":
+		return nil, errors.New("no payment provider configured")
+	case "mock":
+		return NewMockProvider(webhookSecret, baseURL), nil
+	}
+
+	return nil, fmt.Errorf("unknown payment provider %q", name)
+}
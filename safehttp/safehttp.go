@@ -0,0 +1,140 @@
+// Package safehttp makes requests to URLs users give us, like webhook
+// endpoints, without letting them reach this server, the cloud metadata
+// service or anything else on the private network.
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const maxRedirects = 5
+
+var (
+	ErrInvalidURL     = errors.New("url must use http or https and have a host")
+	ErrPrivateAddress = errors.New("address is not publicly routable")
+)
+
+// blocked are ranges the netip.Addr methods used in IsPublic do not cover.
+var blocked = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT, some clouds put metadata here
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, includes broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, can embed any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),  // documentation
+	netip.MustParsePrefix("2002::/16"),      // 6to4, can embed any IPv4 address
+	netip.MustParsePrefix("fec0::/10"),      // deprecated site-local
+}
+
+// IsPublic reports whether addr is a unicast address on the public internet.
+// Loopback, private (RFC 1918 and IPv6 ULA), link-local (which includes the
+// 169.254.169.254 metadata service), multicast and reserved addresses are not.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() || addr.IsUnspecified() {
+		return false
+	}
+
+	for _, prefix := range blocked {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL reports whether raw is an http or https URL whose host only
+// resolves to public addresses. It is meant for validating URLs when they
+// are saved; DNS can change afterwards, so requests must still go through
+// a client from NewClient.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !IsPublic(addr) {
+			return fmt.Errorf("%s: %w", host, ErrPrivateAddress)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %w", host, err)
+	}
+
+	for _, addr := range addrs {
+		if !IsPublic(addr) {
+			return fmt.Errorf("%s resolves to %s: %w", host, addr, ErrPrivateAddress)
+		}
+	}
+	return nil
+}
+
+// NewClient returns a client that refuses to connect to addresses that are
+// not public. The check runs on the address actually dialed, so a host that
+// resolved to a public address when its URL was saved cannot be pointed at
+// the private network later. Redirects are followed only to http and https
+// URLs, and the dialer checks their addresses the same way.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+
+	transport := &http.Transport{
+		// a proxy would do the dialing for us, past the check
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("too many redirects")
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to %s: %w", req.URL.Scheme, ErrInvalidURL)
+	}
+	return nil
+}
+
+// control runs after DNS resolution, right before the socket connects.
+func control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("cannot parse %s: %w", address, err)
+	}
+
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("%s: %w", addrPort.Addr(), ErrPrivateAddress)
+	}
+	return nil
+}
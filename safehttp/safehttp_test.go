@@ -0,0 +1,105 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.10.0.1", false},
+		{"::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.100.100.200", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr error
+	}{
+		{"https://93.184.216.34/hook", nil},
+		{"http://[2606:4700::1111]:8443/hook", nil},
+		{"ftp://93.184.216.34/hook", ErrInvalidURL},
+		{"https:///hook", ErrInvalidURL},
+		{"not a url", ErrInvalidURL},
+		{"http://127.0.0.1:8080/hook", ErrPrivateAddress},
+		{"http://169.254.169.254/latest/meta-data/", ErrPrivateAddress},
+		{"http://[::1]/hook", ErrPrivateAddress},
+		{"http://192.168.0.10/hook", ErrPrivateAddress},
+		{"http://localhost/hook", ErrPrivateAddress},
+	}
+
+	for _, tt := range tests {
+		err := CheckURL(context.Background(), tt.url)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("CheckURL(%q) = %v, want no error", tt.url, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("CheckURL(%q) = %v, want %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("got %v, want %v", err, ErrPrivateAddress)
+	}
+	if hit {
+		t.Error("request reached the loopback server")
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "file:///etc/passwd", nil)
+	if err := checkRedirect(req, make([]*http.Request, 1)); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("redirect to file: got %v, want %v", err, ErrInvalidURL)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "https://example.com/hook", nil)
+	if err := checkRedirect(req, make([]*http.Request, 1)); err != nil {
+		t.Errorf("redirect to https: got %v, want no error", err)
+	}
+	if err := checkRedirect(req, make([]*http.Request, maxRedirects)); err == nil {
+		t.Error("got no error after too many redirects")
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+// CalendarEvent is a single iCalendar (RFC 5545) event.
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Cancelled   bool
+}
+
+// ICS renders the event as a .ics calendar file.
+func (event CalendarEvent) ICS() []byte {
+	var buf bytes.Buffer
+
+	method, status := "REQUEST", "CONFIRMED"
+	if event.Cancelled {
+		method, status = "CANCEL", "CANCELLED"
+	}
+
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//real-estate-backend//viewings//EN")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+	writeICSLine(&buf, "METHOD:"+method)
+	writeICSLine(&buf, "BEGIN:VEVENT")
+	writeICSLine(&buf, "UID:"+event.UID)
+	writeICSLine(&buf, "DTSTAMP:"+time.Now().UTC().Format(icsTimeLayout))
+	writeICSLine(&buf, "DTSTART:"+event.Start.UTC().Format(icsTimeLayout))
+	writeICSLine(&buf, "DTEND:"+event.End.UTC().Format(icsTimeLayout))
+	writeICSLine(&buf, "SUMMARY:"+escapeICSText(event.Summary))
+	if event.Description != "" {
+		writeICSLine(&buf, "DESCRIPTION:"+escapeICSText(event.Description))
+	}
+	if event.Location != "" {
+		writeICSLine(&buf, "LOCATION:"+escapeICSText(event.Location))
+	}
+	writeICSLine(&buf, "STATUS:"+status)
+	writeICSLine(&buf, "END:VEVENT")
+	writeICSLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// writeICSLine folds content lines longer than 75 octets as the RFC requires,
+// taking care not to split a multi-byte character.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(buf, "%s\r\n ", line[:cut])
+		line = line[cut:]
+		// continuation lines start with a space, which counts towards the limit
+		limit = 74
+	}
+	buf.WriteString(line + "\r\n")
+}
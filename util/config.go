@@ -12,6 +12,78 @@ type Config struct {
 	DBSource          string        `mapstructure:"DB_SOURCE"`
 	TokenSymmetricKey string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	TokenDuration     time.Duration `mapstructure:"TOKEN_DURATION"`
+
+	// auto-approval rules for the listing moderation queue
+	AutoApproveTrustedSellers bool `mapstructure:"MODERATION_AUTO_APPROVE_TRUSTED"`
+	AutoApproveMinorEdits     bool `mapstructure:"MODERATION_AUTO_APPROVE_MINOR_EDITS"`
+
+	// number of distinct open reports that hides a listing until a moderator looks at it, 0 disables
+	ReportAutoHideThreshold int `mapstructure:"REPORT_AUTO_HIDE_THRESHOLD"`
+
+	StatsCacheTTL time.Duration `mapstructure:"STATS_CACHE_TTL"`
+
+	// withhold seller contact details from buyers until the seller replies to their inquiry
+	HideContactUntilReply bool `mapstructure:"INQUIRY_HIDE_CONTACT_UNTIL_REPLY"`
+
+	// how often saved searches are checked for new matches, 0 disables the job
+	SavedSearchInterval time.Duration `mapstructure:"SAVED_SEARCH_INTERVAL"`
+
+	// how long a listing stays up before it has to be renewed
+	ListingDuration time.Duration `mapstructure:"LISTING_DURATION"`
+	// owners are warned this many days before their listing expires
+	ListingExpiryWarningDays int `mapstructure:"LISTING_EXPIRY_WARNING_DAYS"`
+	// how often expired listings are archived, 0 disables the job
+	ListingExpiryInterval time.Duration `mapstructure:"LISTING_EXPIRY_INTERVAL"`
+
+	// how often finished promotions are expired, 0 disables the job
+	PromotionExpiryInterval time.Duration `mapstructure:"PROMOTION_EXPIRY_INTERVAL"`
+
+	// payments for listing features, only the "mock" provider exists so far
+	PaymentProvider      string `mapstructure:"PAYMENT_PROVIDER"`
+	PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
+	// serve the mock provider's checkout page, which pays orders for free;
+	// for development only and never served in production
+	PaymentMockCheckout bool `mapstructure:"PAYMENT_MOCK_CHECKOUT"`
+	// public address of this API, used in links handed to other services
+	PublicBaseURL string `mapstructure:"PUBLIC_BASE_URL"`
+	// address of the website, listing pages live at SITE_URL/listing/<slug>
+	SiteURL string `mapstructure:"SITE_URL"`
+
+	// how often new outbox events are relayed to their subscribers, 0 disables the
+	// relay and with it every integration that listens for domain events
+	OutboxRelayInterval time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+
+	// how often pending outgoing webhooks are sent, 0 disables delivery
+	WebhookDeliveryInterval time.Duration `mapstructure:"WEBHOOK_DELIVERY_INTERVAL"`
+
+	// background jobs and the periodic sweeps above, run by the API itself when
+	// JOBS_IN_PROCESS is set and by the "worker" command otherwise; sweeps of
+	// less than a minute run every interval within a job scheduled each minute
+	JobsInProcess   bool          `mapstructure:"JOBS_IN_PROCESS"`
+	JobPollInterval time.Duration `mapstructure:"JOB_POLL_INTERVAL"`
+	JobTimeout      time.Duration `mapstructure:"JOB_TIMEOUT"`
+	// queues and how many jobs each runs at once, e.g. "default:4,uploads:2"
+	JobQueues string `mapstructure:"JOB_QUEUES"`
+
+	// hosts bulk imports may download listing images from, comma separated
+	ImportImageHosts string `mapstructure:"IMPORT_IMAGE_HOSTS"`
+
+	// how long the public property feed is served from memory before it is rebuilt
+	FeedCacheTTL time.Duration `mapstructure:"FEED_CACHE_TTL"`
+
+	// exchange rates loaded into the database at startup, see currency.ReadRates
+	// for the format; leave empty to manage rates through the admin API only
+	ExchangeRatesFile string `mapstructure:"EXCHANGE_RATES_FILE"`
+
+	// how often buffered listing views are written, 0 disables view tracking
+	ViewFlushInterval time.Duration `mapstructure:"VIEW_FLUSH_INTERVAL"`
+
+	// outgoing mail for email notifications, leave SMTP_HOST empty to disable
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom     string `mapstructure:"SMTP_FROM"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -0,0 +1,155 @@
+package worker
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/importer"
+	"github.com/sangketkit01/real-estate-backend/jobs"
+)
+
+const JobImportAssets = "assets.import"
+
+type ImportAssetsPayload struct {
+	ImportID int64 `json:"import_id"`
+}
+
+// assetImporter runs bulk imports row by row. Progress is stored after every
+// row, so a retried job carries on where the last attempt stopped.
+type assetImporter struct {
+	store   *db.Store
+	fetcher *importer.Fetcher
+	jobs    *jobs.Client
+}
+
+func (runner *assetImporter) run(ctx context.Context, payload ImportAssetsPayload) error {
+	assetImport, err := runner.store.StartAssetImport(ctx, payload.ImportID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// finished or gone, nothing left to do
+			return nil
+		}
+		return err
+	}
+
+	var rows []importer.Row
+	if err := json.Unmarshal(assetImport.Items, &rows); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	var archive *zip.Reader
+	if assetImport.ArchivePath.Valid {
+		file, err := zip.OpenReader(assetImport.ArchivePath.String)
+		if err != nil {
+			return fmt.Errorf("cannot open image archive: %w", err)
+		}
+		defer file.Close()
+		archive = &file.Reader
+	}
+
+	for i := int(assetImport.ProcessedRows); i < len(rows); i++ {
+		if err := runner.importRow(ctx, assetImport, rows[i], archive); err != nil {
+			return err
+		}
+	}
+
+	if err := runner.store.FinishAssetImport(ctx, db.FinishAssetImportParams{
+		ID:     assetImport.ID,
+		Status: db.ImportStatusCompleted,
+	}); err != nil {
+		return err
+	}
+
+	if assetImport.ArchivePath.Valid {
+		runner.removeFiles(ctx, assetImport.ArchivePath.String)
+	}
+
+	return nil
+}
+
+// importRow returns an error only when the row has to be tried again. A row
+// whose images cannot be loaded is recorded as failed and skipped.
+func (runner *assetImporter) importRow(ctx context.Context, assetImport db.AssetImport, row importer.Row, archive *zip.Reader) error {
+	images, err := runner.loadImages(ctx, row.Images, archive)
+	if err != nil {
+		runner.removeFiles(ctx, images...)
+
+		rowErrors, _ := json.Marshal([]importer.RowError{{Line: row.Line, Field: importer.FieldImages, Message: err.Error()}})
+		return runner.store.RecordAssetImportRow(ctx, db.RecordAssetImportRowParams{
+			Imported:  false,
+			RowErrors: rowErrors,
+			ID:        assetImport.ID,
+		})
+	}
+
+	_, err = runner.store.ImportAssetTx(ctx, db.ImportAssetTxParams{
+		ImportID: assetImport.ID,
+		AgencyID: assetImport.AgencyID,
+		Asset: db.InsertAssetParams{
+			Owner:            assetImport.CreatedBy,
+			Price:            row.Price,
+			Detail:           row.Detail,
+			ModerationStatus: assetImport.ModerationStatus,
+			PropertyType:     db.PropertyType(row.PropertyType),
+			Province:         row.Province,
+			ExpiresAt:        assetImport.ExpiresAt,
+		},
+		ModerationReason: assetImport.ModerationReason,
+		ContactName:      row.ContactName,
+		ContactDetail:    row.ContactDetail,
+		ImageUrls:        images,
+	})
+	if err != nil {
+		runner.removeFiles(ctx, images...)
+		return fmt.Errorf("row %d: %w", row.Line, err)
+	}
+
+	return nil
+}
+
+// loadImages stores the images of a row in the uploads directory and returns
+// their paths, including the ones saved before an error.
+func (runner *assetImporter) loadImages(ctx context.Context, sources []string, archive *zip.Reader) ([]string, error) {
+	paths := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		var data []byte
+		var ext string
+		var err error
+
+		if importer.IsURL(source) {
+			data, ext, err = runner.fetcher.Fetch(ctx, source)
+		} else if archive != nil {
+			data, ext, err = importer.ReadZipImage(archive, source)
+		} else {
+			err = fmt.Errorf("%s is not a URL and no zip file was uploaded", source)
+		}
+		if err != nil {
+			return paths, err
+		}
+
+		path, err := importer.SaveUpload(data, ext)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func (runner *assetImporter) removeFiles(ctx context.Context, paths ...string) {
+	for _, path := range paths {
+		_, err := runner.jobs.Enqueue(ctx, JobDeleteUpload, DeleteUploadPayload{Path: path}, jobs.OnQueue(UploadsQueue))
+		if err != nil {
+			log.Printf("asset import: cannot enqueue removal of %s: %v", path, err)
+			os.Remove(path)
+		}
+	}
+}
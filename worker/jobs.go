@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/importer"
+	"github.com/sangketkit01/real-estate-backend/jobs"
+	"github.com/sangketkit01/real-estate-backend/notify"
+	"github.com/sangketkit01/real-estate-backend/util"
+)
+
+// Job kinds handled by the job worker.
+const (
+	JobDeleteUpload = "upload.delete"
+	JobPruneJobs    = "jobs.prune"
+)
+
+// UploadsQueue keeps file work away from the default queue.
+const UploadsQueue = "uploads"
+
+const finishedJobRetention = 14 * 24 * time.Hour
+
+type DeleteUploadPayload struct {
+	// path relative to the working directory, e.g. "uploads/123_photo.jpg"
+	Path string `json:"path"`
+}
+
+type PruneJobsPayload struct{}
+
+// RegisterJobs adds the handlers and cron schedules of every job kind. The
+// relay comes with its subscribers already registered.
+func RegisterJobs(worker *jobs.Worker, store *db.Store, config util.Config, dispatcher *notify.Dispatcher, relay *OutboxRelay) error {
+	jobs.Register(worker, JobDeleteUpload, deleteUpload)
+
+	runner := &assetImporter{
+		store:   store,
+		fetcher: importer.NewFetcher(importer.ParseHosts(config.ImportImageHosts)),
+		jobs:    jobs.NewClient(store),
+	}
+	jobs.Register(worker, JobImportAssets, runner.run)
+
+	jobs.Register(worker, JobPruneJobs, func(ctx context.Context, _ PruneJobsPayload) error {
+		deleted, err := store.DeleteFinishedJobs(ctx, sql.NullTime{Time: time.Now().Add(-finishedJobRetention), Valid: true})
+		if err != nil {
+			return err
+		}
+
+		log.Printf("job worker: pruned %d finished jobs", deleted)
+		return nil
+	})
+
+	if err := worker.Cron("prune-jobs", "30 3 * * *", JobPruneJobs, PruneJobsPayload{}); err != nil {
+		return err
+	}
+
+	inApp := notify.NewInAppNotifier(store)
+	sweeps := []struct {
+		kind     string
+		interval time.Duration
+		run      func(ctx context.Context) error
+	}{
+		{JobExpireListings, config.ListingExpiryInterval, NewListingExpiryWorker(store, inApp, config.ListingExpiryWarningDays).RunOnce},
+		{JobExpirePromotions, config.PromotionExpiryInterval, NewPromotionExpiryWorker(store, inApp).RunOnce},
+		{JobCheckSavedSearches, config.SavedSearchInterval, NewSavedSearchWorker(store, dispatcher).RunOnce},
+		{JobDeliverWebhooks, config.WebhookDeliveryInterval, NewWebhookDeliveryWorker(store).RunOnce},
+		{JobRelayOutbox, config.OutboxRelayInterval, relay.RunOnce},
+	}
+	for _, sweep := range sweeps {
+		if err := registerSweep(worker, sweep.kind, sweep.interval, sweep.run); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteUpload(ctx context.Context, payload DeleteUploadPayload) error {
+	path := filepath.Clean(payload.Path)
+	if !strings.HasPrefix(path, "uploads"+string(filepath.Separator)) {
+		return jobs.Permanent(fmt.Errorf("refusing to delete %q outside of uploads", payload.Path))
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+// ListingExpiryWorker warns owners before their listings expire and archives
+// the ones that did.
+type ListingExpiryWorker struct {
+	store    *db.Store
+	notifier notify.Notifier
+	warnDays int
+}
+
+func NewListingExpiryWorker(store *db.Store, notifier notify.Notifier, warnDays int) *ListingExpiryWorker {
+	return &ListingExpiryWorker{
+		store:    store,
+		notifier: notifier,
+		warnDays: warnDays,
+	}
+}
+
+func (worker *ListingExpiryWorker) RunOnce(ctx context.Context) error {
+	if worker.warnDays > 0 {
+		expiring, err := worker.store.WarnExpiringAssets(ctx, time.Now().AddDate(0, 0, worker.warnDays))
+		if err != nil {
+			return fmt.Errorf("cannot warn expiring assets: %w", err)
+		}
+
+		for _, asset := range expiring {
+			worker.notify(ctx, asset.Owner, notify.Notification{
+				Kind:  "listing_expiring",
+				Title: "Your listing expires soon",
+				Body:  fmt.Sprintf("Listing #%d expires on %s. Renew it to keep it in search.", asset.ID, asset.ExpiresAt.Format("2006-01-02")),
+				Data:  map[string]any{"asset_id": asset.ID, "expires_at": asset.ExpiresAt},
+			})
+		}
+	}
+
+	expired, err := worker.store.ArchiveExpiredAssets(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot archive expired assets: %w", err)
+	}
+
+	for _, asset := range expired {
+		worker.notify(ctx, asset.Owner, notify.Notification{
+			Kind:  "listing_expired",
+			Title: "Your listing has expired",
+			Body:  fmt.Sprintf("Listing #%d expired and was archived. Renew it to put it back up.", asset.ID),
+			Data:  map[string]any{"asset_id": asset.ID, "expires_at": asset.ExpiresAt},
+		})
+	}
+
+	return nil
+}
+
+func (worker *ListingExpiryWorker) notify(ctx context.Context, username string, notification notify.Notification) {
+	err := worker.notifier.Notify(ctx, notify.Recipient{Username: username}, notification)
+	if err != nil {
+		log.Printf("listing expiry worker: cannot notify %s: %v", username, err)
+	}
+}
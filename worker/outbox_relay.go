@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+const (
+	outboxBatchSize   = 100
+	outboxMaxAttempts = 10
+	outboxRetention   = 7 * 24 * time.Hour
+)
+
+// OutboxHandler receives an event from the outbox. Events are delivered at
+// least once, so handlers should use the event's IdempotencyKey to ignore
+// events they have already seen.
+type OutboxHandler func(ctx context.Context, event db.OutboxEvent) error
+
+type outboxSubscriber struct {
+	name       string
+	eventTypes map[string]bool
+	handler    OutboxHandler
+}
+
+// OutboxRelay publishes the events written to the outbox to the in-process
+// subscribers, oldest first. An event stays in the outbox until every
+// subscriber has handled it; when one fails, the relay stops and retries
+// from that event on the next run. After outboxMaxAttempts failures the event
+// is marked dead and the relay moves on; admins can list and retry dead
+// events.
+type OutboxRelay struct {
+	store *db.Store
+
+	mu          sync.RWMutex
+	subscribers []outboxSubscriber
+}
+
+func NewOutboxRelay(store *db.Store) *OutboxRelay {
+	return &OutboxRelay{
+		store: store,
+	}
+}
+
+// Subscribe registers a handler for the given event types, or for every
+// event when none are given.
+func (relay *OutboxRelay) Subscribe(name string, handler OutboxHandler, eventTypes ...string) {
+	subscriber := outboxSubscriber{name: name, handler: handler}
+	if len(eventTypes) > 0 {
+		subscriber.eventTypes = make(map[string]bool, len(eventTypes))
+		for _, eventType := range eventTypes {
+			subscriber.eventTypes[eventType] = true
+		}
+	}
+
+	relay.mu.Lock()
+	relay.subscribers = append(relay.subscribers, subscriber)
+	relay.mu.Unlock()
+}
+
+// RunOnce publishes batches until the outbox is drained or a subscriber fails.
+func (relay *OutboxRelay) RunOnce(ctx context.Context) error {
+	for {
+		result, err := relay.store.RelayOutboxTx(ctx, db.RelayOutboxTxParams{
+			BatchSize:   outboxBatchSize,
+			MaxAttempts: outboxMaxAttempts,
+			Publish: func(event db.OutboxEvent) error {
+				return relay.publish(ctx, event)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("cannot relay outbox events: %w", err)
+		}
+
+		if dead := result.Dead; dead != nil {
+			log.Printf("outbox relay: GIVING UP on event %d (%s of %s %s) after %d attempts, subscribers will not see it until it is retried: %s",
+				dead.ID, dead.EventType, dead.AggregateType, dead.AggregateID, dead.Attempts, dead.LastError.String)
+		}
+
+		if result.Failed || result.Published < outboxBatchSize {
+			break
+		}
+	}
+
+	_, err := relay.store.DeletePublishedOutboxEvents(ctx, sql.NullTime{Time: time.Now().Add(-outboxRetention), Valid: true})
+	if err != nil {
+		return fmt.Errorf("cannot delete published outbox events: %w", err)
+	}
+
+	return nil
+}
+
+func (relay *OutboxRelay) publish(ctx context.Context, event db.OutboxEvent) error {
+	relay.mu.RLock()
+	defer relay.mu.RUnlock()
+
+	for _, subscriber := range relay.subscribers {
+		if subscriber.eventTypes != nil && !subscriber.eventTypes[event.EventType] {
+			continue
+		}
+
+		if err := subscriber.handler(ctx, event); err != nil {
+			log.Printf("outbox relay: %s failed on event %d (%s): %v", subscriber.name, event.ID, event.EventType, err)
+			return fmt.Errorf("%s: %w", subscriber.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+// PromotionExpiryWorker ends promotions whose paid period is over and lets
+// the listing owners know.
+type PromotionExpiryWorker struct {
+	store    *db.Store
+	notifier notify.Notifier
+}
+
+func NewPromotionExpiryWorker(store *db.Store, notifier notify.Notifier) *PromotionExpiryWorker {
+	return &PromotionExpiryWorker{
+		store:    store,
+		notifier: notifier,
+	}
+}
+
+func (worker *PromotionExpiryWorker) RunOnce(ctx context.Context) error {
+	expired, err := worker.store.ExpirePromotions(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot expire promotions: %w", err)
+	}
+
+	for _, promotion := range expired {
+		err := worker.notifier.Notify(ctx, notify.Recipient{Username: promotion.Owner}, notify.Notification{
+			Kind:  "promotion_expired",
+			Title: "Your promotion has ended",
+			Body:  fmt.Sprintf("The %s promotion of listing #%d has ended.", promotion.Type, promotion.AssetID),
+			Data:  map[string]any{"asset_id": promotion.AssetID, "promotion_id": promotion.ID, "type": promotion.Type},
+		})
+		if err != nil {
+			log.Printf("promotion expiry worker: cannot notify %s: %v", promotion.Owner, err)
+		}
+	}
+
+	return nil
+}
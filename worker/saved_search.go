@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/currency"
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/notify"
+)
+
+const (
+	// dueSearchBatch is how many saved searches are checked per tick.
+	dueSearchBatch = 100
+	// matchLimit mirrors the LIMIT in GetSavedSearchMatches.
+	matchLimit = 50
+)
+
+// SavedSearchWorker periodically runs saved searches and alerts their owners
+// about new listings and price drops since the last check.
+type SavedSearchWorker struct {
+	store      *db.Store
+	dispatcher *notify.Dispatcher
+}
+
+func NewSavedSearchWorker(store *db.Store, dispatcher *notify.Dispatcher) *SavedSearchWorker {
+	return &SavedSearchWorker{
+		store:      store,
+		dispatcher: dispatcher,
+	}
+}
+
+func (worker *SavedSearchWorker) RunOnce(ctx context.Context) error {
+	searches, err := worker.store.GetDueSavedSearches(ctx, dueSearchBatch)
+	if err != nil {
+		return fmt.Errorf("cannot get due saved searches: %w", err)
+	}
+
+	for _, search := range searches {
+		if err := worker.check(ctx, search); err != nil {
+			log.Printf("saved search worker: search %d: %v", search.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (worker *SavedSearchWorker) check(ctx context.Context, search db.SavedSearch) error {
+	checkedAt := time.Now()
+
+	var filter db.AssetFilter
+	if err := json.Unmarshal(search.Filters, &filter); err != nil {
+		return fmt.Errorf("invalid filters: %w", err)
+	}
+
+	matches, err := worker.store.GetSavedSearchMatches(ctx, db.GetSavedSearchMatchesParams{
+		SavedSearchID: search.ID,
+		Username:      search.Username,
+		Since:         search.LastCheckedAt,
+		PropertyType:  filter.NullPropertyType(),
+		Province:      filter.NullProvince(),
+		MinPrice:      filter.NullMinPrice(),
+		MaxPrice:      filter.NullMaxPrice(),
+		ReducedSince:  filter.NullReducedSince(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get matches: %w", err)
+	}
+
+	if len(matches) > 0 {
+		user, err := worker.store.GetUser(ctx, search.Username)
+		if err != nil {
+			return fmt.Errorf("cannot get user: %w", err)
+		}
+
+		to := notify.Recipient{
+			Username:   user.Username,
+			Email:      user.Email,
+			WebhookURL: search.WebhookUrl.String,
+		}
+
+		// leave last_checked_at alone on failure so the next tick retries
+		if err := worker.dispatcher.Dispatch(ctx, search.Channel, to, newMatchNotification(search, matches)); err != nil {
+			return fmt.Errorf("cannot send notification: %w", err)
+		}
+
+		for _, match := range matches {
+			err := worker.store.UpsertSavedSearchNotification(ctx, db.UpsertSavedSearchNotificationParams{
+				SavedSearchID: search.ID,
+				AssetID:       match.ID,
+				Price:         match.Price,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot record notified asset: %w", err)
+			}
+		}
+	}
+
+	// a full page means there may be more matches, keep the old checkpoint so
+	// the rest are picked up next time; already notified assets are skipped
+	if len(matches) == matchLimit {
+		return nil
+	}
+
+	return worker.store.MarkSavedSearchChecked(ctx, db.MarkSavedSearchCheckedParams{
+		ID:            search.ID,
+		LastCheckedAt: checkedAt,
+	})
+}
+
+func newMatchNotification(search db.SavedSearch, matches []db.GetSavedSearchMatchesRow) notify.Notification {
+	assets := make([]map[string]any, 0, len(matches))
+
+	var body strings.Builder
+	for _, match := range matches {
+		asset := map[string]any{
+			"id":            match.ID,
+			"price":         match.Price,
+			"currency":      match.Currency,
+			"property_type": match.PropertyType,
+			"province":      match.Province,
+		}
+
+		// prices are in minor units, the text shows them as decimals
+		minorUnits := int32(match.MinorUnits)
+		fmt.Fprintf(&body, "#%d %s %s - %s %s", match.ID, match.PropertyType, match.Province,
+			currency.FormatAmount(match.Price, minorUnits), match.Currency)
+		if match.NotifiedPrice.Valid {
+			asset["previous_price"] = match.NotifiedPrice.Int64
+			fmt.Fprintf(&body, " (reduced from %s)", currency.FormatAmount(match.NotifiedPrice.Int64, minorUnits))
+		}
+		body.WriteString("\n")
+
+		assets = append(assets, asset)
+	}
+
+	return notify.Notification{
+		Kind:  "saved_search_match",
+		Title: fmt.Sprintf("%d new matches for %q", len(matches), search.Name),
+		Body:  body.String(),
+		Data: map[string]any{
+			"saved_search_id": search.ID,
+			"assets":          assets,
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sangketkit01/real-estate-backend/jobs"
+)
+
+// Job kinds of the periodic sweeps. Each sweep has a queue of its own with a
+// single slot, so a run never overlaps the previous one.
+const (
+	JobExpireListings     = "listings.expire"
+	JobExpirePromotions   = "promotions.expire"
+	JobCheckSavedSearches = "saved_searches.check"
+	JobDeliverWebhooks    = "webhooks.deliver"
+	JobRelayOutbox        = "outbox.relay"
+)
+
+type SweepPayload struct{}
+
+// registerSweep runs fn every interval as a job. Jobs are scheduled at most
+// once a minute, so a job of a shorter interval keeps running fn every
+// interval until the minute is over. A zero interval turns the sweep off.
+func registerSweep(worker *jobs.Worker, kind string, interval time.Duration, fn func(ctx context.Context) error) error {
+	if interval <= 0 {
+		return nil
+	}
+
+	worker.Queue(kind, 1)
+	jobs.Register(worker, kind, func(ctx context.Context, _ SweepPayload) error {
+		if interval >= time.Minute {
+			return fn(ctx)
+		}
+		return poll(ctx, kind, interval, time.Minute, fn)
+	})
+
+	return worker.Every(kind, max(interval, time.Minute), kind, SweepPayload{}, jobs.OnQueue(kind), jobs.MaxAttempts(1))
+}
+
+// poll runs fn every interval for the given duration. A failed run is logged
+// and the next one tries again.
+func poll(ctx context.Context, kind string, interval, duration time.Duration, fn func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+
+	for {
+		if err := fn(ctx); err != nil {
+			log.Printf("job worker: %s: %v", kind, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+)
+
+// viewBufferSize is how many views may wait for the next flush before new
+// ones are dropped.
+const viewBufferSize = 4096
+
+type assetDay struct {
+	assetID int64
+	day     time.Time
+}
+
+type viewBatch struct {
+	views    int32
+	visitors map[string]struct{}
+}
+
+type viewEvent struct {
+	assetID int64
+	visitor string
+	at      time.Time
+}
+
+// ViewTracker counts listing views off the request path. Views are buffered
+// in memory and written as daily aggregates every interval.
+type ViewTracker struct {
+	store    *db.Store
+	interval time.Duration
+	events   chan viewEvent
+}
+
+func NewViewTracker(store *db.Store, interval time.Duration) *ViewTracker {
+	return &ViewTracker{
+		store:    store,
+		interval: interval,
+		events:   make(chan viewEvent, viewBufferSize),
+	}
+}
+
+// Track records a view without blocking; when the buffer is full the view is
+// dropped rather than slowing down the request.
+func (tracker *ViewTracker) Track(assetID int64, visitor string) {
+	select {
+	case tracker.events <- viewEvent{assetID: assetID, visitor: visitor, at: time.Now()}:
+	default:
+	}
+}
+
+// Start collects views and flushes them every interval until ctx is
+// cancelled. It returns once the views still buffered are written, so stop
+// tracking first and wait for Start to return.
+func (tracker *ViewTracker) Start(ctx context.Context) {
+	ticker := time.NewTicker(tracker.interval)
+	defer ticker.Stop()
+
+	batches := make(map[assetDay]*viewBatch)
+	var purgedDay time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-tracker.events:
+					addView(batches, event)
+				default:
+					// ctx is already cancelled, write what is left without it
+					tracker.flush(context.Background(), batches)
+					return
+				}
+			}
+		case event := <-tracker.events:
+			addView(batches, event)
+		case <-ticker.C:
+			tracker.flush(ctx, batches)
+			batches = make(map[assetDay]*viewBatch)
+
+			// visitors are only needed to deduplicate today's views
+			if today := viewDay(time.Now()); !today.Equal(purgedDay) {
+				if err := tracker.store.DeleteAssetVisitorsBefore(ctx, today.AddDate(0, 0, -1)); err != nil {
+					log.Println("view tracker: cannot purge visitors:", err)
+				} else {
+					purgedDay = today
+				}
+			}
+		}
+	}
+}
+
+func addView(batches map[assetDay]*viewBatch, event viewEvent) {
+	key := assetDay{assetID: event.assetID, day: viewDay(event.at)}
+	batch, ok := batches[key]
+	if !ok {
+		batch = &viewBatch{visitors: make(map[string]struct{})}
+		batches[key] = batch
+	}
+	batch.views++
+	batch.visitors[event.visitor] = struct{}{}
+}
+
+func (tracker *ViewTracker) flush(ctx context.Context, batches map[assetDay]*viewBatch) {
+	for key, batch := range batches {
+		visitors := make([]string, 0, len(batch.visitors))
+		for visitor := range batch.visitors {
+			visitors = append(visitors, visitor)
+		}
+
+		err := tracker.store.RecordAssetViewsTx(ctx, db.RecordAssetViewsTxParams{
+			AssetID:  key.assetID,
+			Day:      key.day,
+			Views:    batch.views,
+			Visitors: visitors,
+		})
+		if err != nil {
+			log.Printf("view tracker: asset %d: %v", key.assetID, err)
+		}
+	}
+}
+
+// viewDay gives the UTC day of t, days are UTC throughout the analytics.
+func viewDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
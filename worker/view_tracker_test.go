@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestViewDayIsUTC(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+
+	tests := []struct {
+		at   time.Time
+		want time.Time
+	}{
+		// still the previous day in UTC
+		{time.Date(2026, 3, 14, 2, 0, 0, 0, bangkok), time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 14, 8, 0, 0, 0, bangkok), time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 14, 23, 59, 0, 0, time.UTC), time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := viewDay(tt.at); !got.Equal(tt.want) {
+			t.Errorf("viewDay(%s) = %s, want %s", tt.at, got, tt.want)
+		}
+	}
+}
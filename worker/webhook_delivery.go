@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	db "github.com/sangketkit01/real-estate-backend/db/sqlc"
+	"github.com/sangketkit01/real-estate-backend/safehttp"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	webhookMaxAttempts  = 8
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = 6 * time.Hour
+	webhookBatchSize    = 20
+	webhookTimeout      = 10 * time.Second
+	webhookLease        = time.Minute
+	webhookErrorMaxSize = 500
+)
+
+// WebhookDeliveryWorker sends pending webhook deliveries to their subscriber.
+// Failed deliveries are retried with exponential backoff until they run out
+// of attempts. Subscriber URLs are never allowed to reach a private address,
+// otherwise the recorded status codes would map out the internal network.
+type WebhookDeliveryWorker struct {
+	store  *db.Store
+	client *http.Client
+}
+
+func NewWebhookDeliveryWorker(store *db.Store) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		store:  store,
+		client: safehttp.NewClient(webhookTimeout),
+	}
+}
+
+// SignWebhook returns the signature header value of a payload sent at timestamp:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<payload>".
+func SignWebhook(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff is the wait before retrying after the given attempt number.
+func webhookBackoff(attempt int32) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := int32(1); i < attempt; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+func (worker *WebhookDeliveryWorker) RunOnce(ctx context.Context) error {
+	// claiming pushes next_attempt_at past the lease so another instance
+	// does not pick the same deliveries while they are being sent
+	deliveries, err := worker.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(webhookLease),
+		BatchSize:  webhookBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot claim webhook deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery db.ClaimDueWebhookDeliveriesRow) {
+			defer wg.Done()
+			if err := worker.deliver(ctx, delivery); err != nil {
+				log.Printf("webhook delivery worker: delivery %d: %v", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (worker *WebhookDeliveryWorker) deliver(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) error {
+	attempt := delivery.Attempts + 1
+	started := time.Now()
+
+	statusCode, sendErr := worker.send(ctx, delivery)
+
+	arg := db.RecordWebhookAttemptTxParams{
+		DeliveryID: delivery.ID,
+		Attempt:    attempt,
+		StatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+		DurationMs: int32(time.Since(started).Milliseconds()),
+		Status:     db.WebhookDeliveryStatusSucceeded,
+	}
+
+	if sendErr != nil {
+		message := sendErr.Error()
+		if len(message) > webhookErrorMaxSize {
+			message = message[:webhookErrorMaxSize]
+		}
+		arg.Error = sql.NullString{String: message, Valid: true}
+
+		arg.Status = db.WebhookDeliveryStatusPending
+		arg.NextAttemptAt = time.Now().Add(webhookBackoff(attempt))
+		if attempt >= webhookMaxAttempts {
+			arg.Status = db.WebhookDeliveryStatusFailed
+		}
+	}
+
+	if arg.NextAttemptAt.IsZero() {
+		arg.NextAttemptAt = time.Now()
+	}
+
+	return worker.store.RecordWebhookAttemptTx(ctx, arg)
+}
+
+// send posts the payload and returns the response status code, 0 when no
+// response was received.
+func (worker *WebhookDeliveryWorker) send(ctx context.Context, delivery db.ClaimDueWebhookDeliveriesRow) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(delivery.Secret, timestamp, delivery.Payload))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	rsp, err := worker.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(rsp.Body, 64<<10))
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return rsp.StatusCode, fmt.Errorf("subscriber responded with status %d", rsp.StatusCode)
+	}
+
+	return rsp.StatusCode, nil
+}